@@ -39,5 +39,7 @@ func main() {
 
 	// Start the service
 	log.Println("Starting the fulfiller service...")
-	service.Start(ctx)
+	if err := service.Start(ctx); err != nil {
+		log.Fatalf("Failed to start fulfiller service: %v", err)
+	}
 }
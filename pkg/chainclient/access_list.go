@@ -0,0 +1,98 @@
+package chainclient
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// accessListTimeout is the maximum duration of an access list creation, the transaction is sent without one after it
+const accessListTimeout = 5 * time.Second
+
+// accessListResult is the result of eth_createAccessList
+type accessListResult struct {
+	AccessList types.AccessList `json:"accessList"`
+	GasUsed    hexutil.Uint64   `json:"gasUsed"`
+	Error      string           `json:"error,omitempty"`
+}
+
+// CreateAccessList returns the access list of the call to the contract at to with input from the account,
+// created by the node with eth_createAccessList on the pending state
+func (c *Client) CreateAccessList(
+	ctx context.Context,
+	from common.Address,
+	to common.Address,
+	input []byte,
+	value *big.Int,
+) (types.AccessList, error) {
+	client := c.ReadClient()
+	if client == nil {
+		return nil, fmt.Errorf("client not connected")
+	}
+
+	call := map[string]interface{}{
+		"from":  from,
+		"to":    to,
+		"input": hexutil.Bytes(input),
+	}
+	if value != nil {
+		call["value"] = (*hexutil.Big)(value)
+	}
+
+	result, err := rpcCall(ctx, c, "CreateAccessList", func() (*accessListResult, error) {
+		var result accessListResult
+		err := client.Client().CallContext(ctx, &result, "eth_createAccessList", call, "pending")
+		return &result, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("access list creation failed: %s", result.Error)
+	}
+	return result.AccessList, nil
+}
+
+// withAccessList returns a copy of opts sending the transaction with the access list of the call created by the node,
+// the transaction is sent as a dynamic fee transaction paying at most the gas price of opts.
+// opts is returned unchanged if the node doesn't support eth_createAccessList, the access list is empty
+// or the priority fee of a legacy transaction can't be derived
+func (c *Client) withAccessList(opts *bind.TransactOpts, to common.Address, input []byte) *bind.TransactOpts {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, accessListTimeout)
+	defer cancel()
+
+	accessList, err := c.CreateAccessList(ctx, opts.From, to, input, opts.Value)
+	if err != nil {
+		c.logger.DebugWithChain(c.ChainID, "Sending transaction without access list: %v", err)
+		return opts
+	}
+	if len(accessList) == 0 {
+		return opts
+	}
+
+	withList := *opts
+	withList.AccessList = accessList
+	// legacy transactions can't carry an access list, the gas price is used as fee cap with the part above
+	// the base fee as tip so that the transaction pays the same price
+	if withList.GasPrice != nil {
+		tip, err := c.dynamicFeeTip(ctx, withList.GasPrice)
+		if err != nil {
+			c.logger.DebugWithChain(c.ChainID, "Sending transaction without access list: %v", err)
+			return opts
+		}
+		withList.GasFeeCap = withList.GasPrice
+		withList.GasTipCap = tip
+		withList.GasPrice = nil
+	}
+	return &withList
+}
@@ -0,0 +1,76 @@
+package chainclient
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/speedrun-hq/speedrunner/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAccessListAddress is the address of the access list served by fakeAccessListService
+var fakeAccessListAddress = common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+
+// fakeAccessListService serves eth_createAccessList for in-process clients
+type fakeAccessListService struct {
+	calls int
+}
+
+func (s *fakeAccessListService) CreateAccessList(_ map[string]interface{}, _ string) accessListResult {
+	s.calls++
+	return accessListResult{
+		AccessList: types.AccessList{{Address: fakeAccessListAddress, StorageKeys: []common.Hash{{1}}}},
+		GasUsed:    50000,
+	}
+}
+
+// TestWithAccessList verifies the access list created by the node is set on the transaction with the gas price as fee cap
+// and the part of the gas price above the base fee as tip
+func TestWithAccessList(t *testing.T) {
+	service := &fakeAccessListService{}
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", service))
+	require.NoError(t, server.RegisterName("eth", &fakeBaseFeeService{baseFee: big.NewInt(600000000)}))
+	t.Cleanup(server.Stop)
+
+	c := &Client{ChainID: 1, Client: ethclient.NewClient(rpc.DialInProc(server)), logger: &logger.EmptyLogger{}}
+	opts := &bind.TransactOpts{GasPrice: big.NewInt(1000000000)}
+
+	withList := c.withAccessList(opts, common.Address{}, []byte{0x01})
+	require.Len(t, withList.AccessList, 1)
+	assert.Equal(t, fakeAccessListAddress, withList.AccessList[0].Address)
+	assert.Nil(t, withList.GasPrice)
+	assert.Equal(t, big.NewInt(1000000000), withList.GasFeeCap)
+	assert.Equal(t, big.NewInt(400000000), withList.GasTipCap)
+	assert.Equal(t, 1, service.calls)
+
+	// the original options are left untouched
+	assert.Nil(t, opts.AccessList)
+	assert.Equal(t, big.NewInt(1000000000), opts.GasPrice)
+}
+
+// TestWithAccessListUnsupported verifies the transaction is sent without access list if the node doesn't support it
+func TestWithAccessListUnsupported(t *testing.T) {
+	c := &Client{ChainID: 1, Client: newFakeEthClient(t), logger: &logger.EmptyLogger{}}
+	opts := &bind.TransactOpts{GasPrice: big.NewInt(1000000000)}
+
+	assert.Same(t, opts, c.withAccessList(opts, common.Address{}, []byte{0x01}))
+}
+
+// TestWithAccessListNoTip verifies a legacy transaction is sent without access list if its tip can't be derived
+func TestWithAccessListNoTip(t *testing.T) {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", &fakeAccessListService{}))
+	t.Cleanup(server.Stop)
+
+	c := &Client{ChainID: 1, Client: ethclient.NewClient(rpc.DialInProc(server)), logger: &logger.EmptyLogger{}}
+	opts := &bind.TransactOpts{GasPrice: big.NewInt(1000000000)}
+
+	assert.Same(t, opts, c.withAccessList(opts, common.Address{}, []byte{0x01}))
+}
@@ -0,0 +1,29 @@
+package chainclient
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/speedrun-hq/speedrunner/pkg/contracts"
+)
+
+// TokenBalance returns the balance of the account in base units of the ERC20 token
+func (c *Client) TokenBalance(ctx context.Context, token common.Address, account common.Address) (*big.Int, error) {
+	erc20, err := contracts.NewERC20(token, c.ReadClient())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ERC20 contract: %v", err)
+	}
+	return rpcCall(ctx, c, "BalanceOf", func() (*big.Int, error) {
+		return erc20.BalanceOf(&bind.CallOpts{Context: ctx}, account)
+	})
+}
+
+// NativeBalance returns the balance of the account in wei of the gas token
+func (c *Client) NativeBalance(ctx context.Context, account common.Address) (*big.Int, error) {
+	return rpcCall(ctx, c, "BalanceAt", func() (*big.Int, error) {
+		return c.ReadClient().BalanceAt(ctx, account, nil)
+	})
+}
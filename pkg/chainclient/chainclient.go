@@ -10,11 +10,12 @@ import (
 	"github.com/speedrun-hq/speedrunner/pkg/config"
 	"github.com/speedrun-hq/speedrunner/pkg/logger"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/speedrun-hq/speedrunner/pkg/contracts"
+	"github.com/speedrun-hq/speedrunner/pkg/signer"
 )
 
 // Client contains client and config information for a specific blockchain
@@ -23,21 +24,101 @@ type Client struct {
 	ChainID        int
 	RPCURL         string
 	IntentAddress  string
-	MinFee         *big.Int
-	MaxGasPrice    *big.Int
+	MinFee         *big.Int // read with GetMinFee once the client is running, it can be updated at runtime
+	MinFeeUSD      float64
+	MaxGasPrice    *big.Int // read with GetMaxGasPrice once the client is running, it can be updated at runtime
+	MinGasPrice    *big.Int
+	MinTip         *big.Int // priority fee floor of dynamic fee transactions, nil for no floor
 	Client         *ethclient.Client
 	IntentContract *contracts.Intent
 	Auth           *bind.TransactOpts
 	GasMultiplier  float64
+	GasSource      string
+	GasPercentile  float64
+	WithdrawGas    uint64
+	Confirmations  uint64
+	MaxPendingTx   uint64
+
+	// ReceiptPollInterval is the interval between receipt requests while waiting for a transaction to be mined
+	ReceiptPollInterval time.Duration
+
+	// ApprovalGasMultiplier is applied on the fulfill gas price for approval transactions,
+	// a higher price can be accepted for the one-time approval blocking the fulfillment
+	ApprovalGasMultiplier float64
+
+	// BaseFeeMultiplier is the multiplier of the base fee in the fee cap of dynamic fee transactions
+	BaseFeeMultiplier float64
+
+	// MaxConcurrentRPC limits the concurrent RPC calls to the chain, 0 for no limit
+	MaxConcurrentRPC int
+	// chainSem limits the concurrent RPC calls to all the endpoints of the chain, set on connection
+	chainSem chan struct{}
+	// callLimiter limits the calls to the chain when one of its endpoints isn't HTTP, set on connection
+	callLimiter *rpcLimiter
+
+	// AllowedTokens restricts the token types fulfilled on the chain, nil to allow all tokens
+	AllowedTokens []string
+
+	// ReceiverOverrides maps token types (or config.ReceiverOverrideAllTokens) to the address fulfillments are
+	// sent to instead of the intent recipient, e.g. an intermediary contract forwarding the funds.
+	// Security: funds no longer go to the address the user signed for, the override must be a contract that
+	// the operator controls or trusts to forward them to the recipient, and the fulfillment recorded on-chain
+	// carries the override as receiver, so it is only reimbursed on settlement if the protocol expects that receiver.
+	// A wrong address loses the fulfilled amount, which is why an invalid mapping fails the client creation.
+	ReceiverOverrides map[string]common.Address
+
+	// ProcessingInterval is the minimum time between two polls processing intents to the chain, 0 for every poll
+	ProcessingInterval time.Duration
+
+	// ReorgCheckDepth is the number of blocks after which fulfillments are re-checked for reorgs, 0 to disable
+	ReorgCheckDepth uint64
+
+	// UseAccessList sends fulfill transactions with the access list created by the node to reduce their gas
+	UseAccessList bool
+
+	// reconnection to the RPC after consecutive failed health checks
+	ReconnectFailures   int
+	ReconnectMaxBackoff time.Duration
+	disconnected        bool // set after ReconnectFailures failed health checks until one succeeds
 
 	// updated fees
 	CurrentGasPrice *big.Int
+	EffectiveTip    *big.Int // priority fee of the last dynamic fee transaction, read with GetEffectiveTip
 	TokenPriceUSD   float64
+	L1FeeUSD        float64
 	WithdrawFeeUSD  float64
+	FeeUpdatedAt    time.Time
+
+	// signer used to rebuild the authenticator on reconnection
+	txSigner signer.Signer
+
+	// IntentAddresses are the Intent contracts fulfilled through besides IntentAddress, e.g. a new version
+	// deployed alongside the current one, intents select the contract with their contract field
+	IntentAddresses []string
+	intentContracts map[common.Address]*contracts.Intent
+
+	// PriceID is the CoinGecko API id of the gas token used to price fees
+	PriceID string
+
+	// SubmitRPCURL is the endpoint transactions are sent to instead of RPCURL if set (e.g. a private mempool)
+	SubmitRPCURL string
+	submitClient *ethclient.Client
+
+	// ReadRPCURL is the endpoint state reads, gas price and gas estimation requests are sent to instead of RPCURL
+	// if set (e.g. a cheaper or archive provider), transactions and nonces still go through RPCURL or SubmitRPCURL
+	ReadRPCURL string
+	readClient *ethclient.Client
+
+	// ABI of the Intent contract and name of the fulfill method
+	intentABI     abi.ABI
+	fulfillMethod string
 
 	logger     logger.Logger
 	mu         sync.RWMutex
 	feeRoutine *FeeUpdateRoutine
+
+	// nonces of transactions not mined in time, reused to replace them
+	releasedNonces []releasedNonce
 }
 
 // New creates a new client
@@ -47,8 +128,8 @@ func New(
 	chainID int,
 	rpcURL,
 	intentAddress,
-	minFee,
-	privateKey string,
+	minFee string,
+	txSigner signer.Signer,
 	logger logger.Logger,
 ) (*Client, error) {
 	minFeeBig := big.NewInt(0)
@@ -60,6 +141,13 @@ func New(
 		}
 	}
 
+	// Get the min fee in USD, overriding the min fee in base units if set
+	minFeeUSD, err := config.GetEnvChainMinFeeUSD(chainID)
+	if err != nil {
+		logger.ErrorWithChain(chainID, "Invalid min fee USD: %v, falling back to min fee %s", err, minFeeBig.String())
+		minFeeUSD = 0
+	}
+
 	// Get gas multiplier from environment (centralized in config), default to 1.1
 	gasMultiplier, err := config.GetEnvChainGasMultiplier(chainID)
 	if err != nil {
@@ -67,6 +155,140 @@ func New(
 		gasMultiplier = 1.1
 	}
 
+	approvalGasMultiplier, err := config.GetEnvChainApprovalGasMultiplier(chainID)
+	if err != nil {
+		logger.ErrorWithChain(chainID, "Invalid approval gas multiplier: %v, falling back to %.2f",
+			err, config.DefaultApprovalGasMultiplier)
+		approvalGasMultiplier = config.DefaultApprovalGasMultiplier
+	}
+
+	baseFeeMultiplier, err := config.GetEnvChainBaseFeeMultiplier(chainID)
+	if err != nil {
+		logger.ErrorWithChain(chainID, "Invalid base fee multiplier: %v, falling back to %.2f",
+			err, config.DefaultBaseFeeMultiplier)
+		baseFeeMultiplier = config.DefaultBaseFeeMultiplier
+	}
+
+	minTip, err := config.GetEnvChainMinTip(chainID)
+	if err != nil {
+		logger.ErrorWithChain(chainID, "Invalid min tip: %v, using no tip floor", err)
+		minTip = nil
+	}
+
+	// Get the source of gas prices and the fee history percentile
+	gasSource, err := config.GetEnvChainGasSource(chainID)
+	if err != nil {
+		logger.ErrorWithChain(chainID, "Invalid gas source: %v, falling back to %s", err, config.DefaultGasSource)
+		gasSource = config.DefaultGasSource
+	}
+	gasPercentile, err := config.GetEnvChainGasPercentile(chainID)
+	if err != nil {
+		logger.ErrorWithChain(chainID, "Invalid gas percentile: %v, falling back to %.0f", err, config.DefaultGasPercentile)
+		gasPercentile = config.DefaultGasPercentile
+	}
+
+	// Get gas units used to estimate the withdraw fee
+	withdrawGas, err := config.GetEnvChainWithdrawGas(chainID)
+	if err != nil {
+		logger.ErrorWithChain(chainID, "Invalid withdraw gas: %v, falling back to %d", err, config.DefaultWithdrawGas)
+		withdrawGas = config.DefaultWithdrawGas
+	}
+
+	// Get the token types allowed on the chain, an invalid list is an error as it is meant to restrict fulfillments
+	allowedTokens, err := config.GetEnvChainAllowedTokens(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the additional Intent contracts, an invalid address is an error as intents would be sent to the wrong contract
+	intentAddresses, err := config.GetEnvChainIntentAddresses(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the receivers substituted for the intent recipient, an invalid mapping is an error as funds would be misrouted
+	receiverOverrides, err := config.GetEnvChainReceiverOverrides(chainID)
+	if err != nil {
+		return nil, err
+	}
+	for token, receiver := range receiverOverrides {
+		logger.NoticeWithChain(chainID, "Fulfillments of %s tokens are sent to %s instead of the intent recipient", token, receiver.Hex())
+	}
+
+	// Get the depth at which fulfillments are re-checked for reorgs
+	reorgCheckDepth, err := config.GetEnvChainReorgCheckDepth(chainID)
+	if err != nil {
+		logger.ErrorWithChain(chainID, "Invalid reorg check depth: %v, falling back to %d", err, config.DefaultReorgCheckDepth)
+		reorgCheckDepth = config.DefaultReorgCheckDepth
+	}
+
+	// Get the number of confirmations required for transactions
+	confirmations, err := config.GetEnvChainConfirmations(chainID)
+	if err != nil {
+		logger.ErrorWithChain(chainID, "Invalid confirmations: %v, falling back to %d", err, config.DefaultConfirmations)
+		confirmations = config.DefaultConfirmations
+	}
+
+	// Get the interval between receipt requests while waiting for transactions to be mined
+	receiptPollInterval, err := config.GetEnvChainReceiptPollInterval(chainID)
+	if err != nil {
+		logger.ErrorWithChain(chainID, "Invalid receipt poll interval: %v, falling back to %v", err, config.DefaultReceiptPollInterval)
+		receiptPollInterval = config.DefaultReceiptPollInterval
+	}
+
+	// Get the gas price floor used when the gas source returns a lower price
+	minGasPrice, err := config.GetEnvChainMinGasPrice(chainID)
+	if err != nil {
+		logger.ErrorWithChain(chainID, "Invalid min gas price: %v, falling back to %s", err, config.DefaultMinGasPrice)
+		minGasPrice, _ = new(big.Int).SetString(config.DefaultMinGasPrice, 10)
+	}
+
+	// Get the maximum number of pending transactions before pausing fulfillments
+	maxPendingTx, err := config.GetEnvChainMaxPendingTx(chainID)
+	if err != nil {
+		logger.ErrorWithChain(chainID, "Invalid max pending transactions: %v, falling back to %d", err, config.DefaultMaxPendingTx)
+		maxPendingTx = config.DefaultMaxPendingTx
+	}
+
+	// Get the interval at which intents to the chain are processed
+	processingInterval, err := config.GetEnvChainProcessingInterval(chainID)
+	if err != nil {
+		logger.ErrorWithChain(chainID, "Invalid processing interval: %v, falling back to every poll", err)
+		processingInterval = 0
+	}
+
+	// Get the maximum number of concurrent RPC calls to the chain
+	maxConcurrentRPC, err := config.GetEnvChainMaxConcurrentRPC(chainID)
+	if err != nil {
+		logger.ErrorWithChain(chainID, "Invalid max concurrent RPC calls: %v, falling back to no limit", err)
+		maxConcurrentRPC = 0
+	}
+
+	// Get the reconnection settings for dropped RPC connections
+	reconnectFailures, err := config.GetEnvRPCReconnectFailures()
+	if err != nil {
+		logger.ErrorWithChain(chainID, "Invalid reconnect failures: %v, falling back to %d", err, config.DefaultRPCReconnectFailures)
+		reconnectFailures = config.DefaultRPCReconnectFailures
+	}
+	reconnectMaxBackoff, err := config.GetEnvRPCReconnectMaxBackoff()
+	if err != nil {
+		logger.ErrorWithChain(chainID, "Invalid reconnect max backoff: %v, falling back to %ds", err, config.DefaultRPCReconnectMaxBackoff)
+		reconnectMaxBackoff = config.DefaultRPCReconnectMaxBackoff * time.Second
+	}
+
+	// Get whether fulfill transactions are sent with an access list
+	useAccessList, err := config.GetEnvUseAccessList()
+	if err != nil {
+		logger.ErrorWithChain(chainID, "Invalid use access list: %v, falling back to %t", err, config.DefaultUseAccessList)
+		useAccessList = config.DefaultUseAccessList
+	}
+
+	// Get the CoinGecko API id of the gas token
+	priceID := config.GetEnvChainPriceID(chainID)
+	if priceID == "" {
+		priceID = gasTokenPriceID(chainID)
+	}
+
 	// Connect to the chain using the provided RPC URL
 	client := &Client{
 		Ctx:           ctx,
@@ -74,14 +296,43 @@ func New(
 		RPCURL:        rpcURL,
 		IntentAddress: intentAddress,
 		MinFee:        minFeeBig,
+		MinFeeUSD:     minFeeUSD,
+		MinGasPrice:   minGasPrice,
+		MinTip:        minTip,
 		GasMultiplier: gasMultiplier,
-		logger:        logger,
-		feeRoutine:    nil,
+		GasSource:     gasSource,
+		GasPercentile: gasPercentile,
+		WithdrawGas:   withdrawGas,
+		Confirmations: confirmations,
+		MaxPendingTx:  maxPendingTx,
+
+		ApprovalGasMultiplier: approvalGasMultiplier,
+		BaseFeeMultiplier:     baseFeeMultiplier,
+		ReceiptPollInterval:   receiptPollInterval,
+
+		MaxConcurrentRPC:    maxConcurrentRPC,
+		ReorgCheckDepth:     reorgCheckDepth,
+		UseAccessList:       useAccessList,
+		ProcessingInterval:  processingInterval,
+		AllowedTokens:       allowedTokens,
+		IntentAddresses:     intentAddresses,
+		ReceiverOverrides:   receiverOverrides,
+		SubmitRPCURL:        config.GetEnvChainSubmitRPCURL(chainID),
+		ReadRPCURL:          config.GetEnvChainReadRPCURL(chainID),
+		PriceID:             priceID,
+		ReconnectFailures:   reconnectFailures,
+		ReconnectMaxBackoff: reconnectMaxBackoff,
+
+		logger:     logger,
+		feeRoutine: nil,
 	}
-	if err := client.connect(ctx, privateKey); err != nil {
+	if err := client.connect(ctx, txSigner); err != nil {
 		return nil, fmt.Errorf("failed to connect to chain %d: %v", chainID, err)
 	}
 
+	// check the gas token can be priced, fees can't be computed otherwise
+	client.validatePriceID(ctx)
+
 	// start fee update routine
 	client.StartFeeUpdateRoutine(15 * time.Second)
 
@@ -107,9 +358,57 @@ func (c *Client) StopFeeUpdateRoutine() {
 	}
 }
 
+// Close stops the background routines of the client and closes the RPC connection
+func (c *Client) Close() {
+	c.StopFeeUpdateRoutine()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Client != nil {
+		c.Client.Close()
+		c.Client = nil
+	}
+	if c.submitClient != nil {
+		c.submitClient.Close()
+		c.submitClient = nil
+	}
+	if c.readClient != nil {
+		c.readClient.Close()
+		c.readClient = nil
+	}
+}
+
+// ReadClient returns the client state reads and gas estimations are sent to, the read endpoint if configured
+func (c *Client) ReadClient() *ethclient.Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.readClient != nil {
+		return c.readClient
+	}
+	return c.Client
+}
+
+// RPCClient returns the client of the primary RPC endpoint, it is replaced on reconnection and nil once closed
+func (c *Client) RPCClient() *ethclient.Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Client
+}
+
+// TransactOpts returns a copy of the transactor with the current gas price, it is replaced on reconnection
+func (c *Client) TransactOpts() (*bind.TransactOpts, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.Auth == nil {
+		return nil, fmt.Errorf("no transactor configured")
+	}
+	opts := *c.Auth
+	return &opts, nil
+}
+
 // UpdateGasPrice updates the gas price based on current network conditions
 func (c *Client) UpdateGasPrice(ctx context.Context) (*big.Int, error) {
-	if c.Client == nil {
+	if c.RPCClient() == nil {
 		return nil, fmt.Errorf("client not connected")
 	}
 
@@ -117,7 +416,7 @@ func (c *Client) UpdateGasPrice(ctx context.Context) (*big.Int, error) {
 	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	gasPrice, err := c.Client.SuggestGasPrice(timeoutCtx)
+	gasPrice, err := c.fetchGasPrice(timeoutCtx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get gas price: %v", err)
 	}
@@ -133,23 +432,25 @@ func (c *Client) UpdateGasPrice(ctx context.Context) (*big.Int, error) {
 	multipliedGasPrice.Int(finalGasPrice)
 
 	// Update the auth with the new gas price
+	c.mu.Lock()
 	if c.Auth != nil {
 		c.Auth.GasPrice = finalGasPrice
 	}
+	c.mu.Unlock()
 
 	return finalGasPrice, nil
 }
 
-// EffectiveGasPrice returns the suggested gas price multiplied by the client's GasMultiplier, without mutating auth
+// EffectiveGasPrice returns the gas price from the gas source multiplied by the client's GasMultiplier, without mutating auth
 func (c *Client) EffectiveGasPrice(ctx context.Context) (*big.Int, error) {
-	if c.Client == nil {
+	if c.RPCClient() == nil {
 		return nil, fmt.Errorf("client not connected")
 	}
 
 	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	gasPrice, err := c.Client.SuggestGasPrice(timeoutCtx)
+	gasPrice, err := c.fetchGasPrice(timeoutCtx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get gas price: %v", err)
 	}
@@ -160,24 +461,78 @@ func (c *Client) EffectiveGasPrice(ctx context.Context) (*big.Int, error) {
 	return finalGasPrice, nil
 }
 
+// ApprovalGasPrice returns the gas price of an approval transaction sent before a fulfillment at gasPrice
+func (c *Client) ApprovalGasPrice(gasPrice *big.Int) *big.Int {
+	if gasPrice == nil || c.ApprovalGasMultiplier <= 0 || c.ApprovalGasMultiplier == 1 {
+		return gasPrice
+	}
+	multiplied := new(big.Float).Mul(new(big.Float).SetInt(gasPrice), big.NewFloat(c.ApprovalGasMultiplier))
+	approvalGasPrice := new(big.Int)
+	multiplied.Int(approvalGasPrice)
+	return approvalGasPrice
+}
+
+// IsTokenAllowed returns true if the token type can be fulfilled on the chain
+func (c *Client) IsTokenAllowed(tokenType string) bool {
+	if c.AllowedTokens == nil {
+		return true
+	}
+	for _, allowed := range c.AllowedTokens {
+		if allowed == tokenType {
+			return true
+		}
+	}
+	return false
+}
+
+// Receiver returns the address fulfillments of the token type are sent to on the chain,
+// the override configured for the token type, then for all tokens, otherwise the intent recipient
+func (c *Client) Receiver(tokenType string, recipient common.Address) common.Address {
+	if receiver, ok := c.ReceiverOverrides[tokenType]; ok {
+		return receiver
+	}
+	if receiver, ok := c.ReceiverOverrides[config.ReceiverOverrideAllTokens]; ok {
+		return receiver
+	}
+	return recipient
+}
+
 // IsWithinMax returns true if gp <= MaxGasPrice or if MaxGasPrice is nil (no cap)
 func (c *Client) IsWithinMax(gp *big.Int) bool {
 	if gp == nil {
 		return false
 	}
-	if c.MaxGasPrice == nil {
+	maxGasPrice := c.GetMaxGasPrice()
+	if maxGasPrice == nil {
 		return true
 	}
-	return gp.Cmp(c.MaxGasPrice) <= 0
+	return gp.Cmp(maxGasPrice) <= 0
+}
+
+// GetMaxGasPrice returns the gas price cap in wei, nil for no cap
+func (c *Client) GetMaxGasPrice() *big.Int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.MaxGasPrice
+}
+
+// SetMaxGasPrice updates the gas price cap in wei, nil for no cap
+func (c *Client) SetMaxGasPrice(maxGasPrice *big.Int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MaxGasPrice = maxGasPrice
 }
 
 // GetLatestBlockNumber gets the latest block number from the chain
 func (c *Client) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
-	if c.Client == nil {
+	client := c.RPCClient()
+	if client == nil {
 		return 0, fmt.Errorf("client not connected")
 	}
 
-	return c.Client.BlockNumber(ctx)
+	return rpcCall(ctx, c, "BlockNumber", func() (uint64, error) {
+		return client.BlockNumber(ctx)
+	})
 }
 
 // GetCurrentGasPrice returns the current gas price
@@ -187,6 +542,20 @@ func (c *Client) GetCurrentGasPrice() *big.Int {
 	return c.CurrentGasPrice
 }
 
+// GetMinFee returns the minimum intent fee in base units of the token
+func (c *Client) GetMinFee() *big.Int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.MinFee
+}
+
+// SetMinFee updates the minimum intent fee in base units of the token
+func (c *Client) SetMinFee(minFee *big.Int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MinFee = minFee
+}
+
 // GetStoredTokenPriceUSD returns the current token price in USD
 func (c *Client) GetStoredTokenPriceUSD() float64 {
 	c.mu.RLock()
@@ -194,6 +563,13 @@ func (c *Client) GetStoredTokenPriceUSD() float64 {
 	return c.TokenPriceUSD
 }
 
+// GetL1FeeUSD returns the current L1 data fee in USD, zero for chains that are not rollups
+func (c *Client) GetL1FeeUSD() float64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.L1FeeUSD
+}
+
 // GetWithdrawFeeUSD returns the current withdraw fee in USD
 func (c *Client) GetWithdrawFeeUSD() float64 {
 	c.mu.RLock()
@@ -201,42 +577,98 @@ func (c *Client) GetWithdrawFeeUSD() float64 {
 	return c.WithdrawFeeUSD
 }
 
+// IsConnected returns false if the RPC health checks are failing and the client is reconnecting
+func (c *Client) IsConnected() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return !c.disconnected
+}
+
+// setDisconnected records whether the RPC health checks are failing
+func (c *Client) setDisconnected(disconnected bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.disconnected = disconnected
+}
+
+// GetEffectiveTip returns the priority fee of the last dynamic fee transaction, nil if none was sent
+func (c *Client) GetEffectiveTip() *big.Int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.EffectiveTip
+}
+
+// GetFeeUpdatedAt returns the time of the last successful fee update, zero if the fees were never updated
+func (c *Client) GetFeeUpdatedAt() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.FeeUpdatedAt
+}
+
 // connect establishes connections to blockchain RPC and initializes contract instances
-func (c *Client) connect(ctx context.Context, privateKey string) error {
-	// Connect to Ethereum client
-	client, err := ethclient.Dial(c.RPCURL)
+func (c *Client) connect(ctx context.Context, txSigner signer.Signer) error {
+	// Connect to Ethereum client, the requests to HTTP endpoints are limited by their transport
+	c.chainSem = newChainSemaphore(c.MaxConcurrentRPC)
+	c.callLimiter = newCallLimiter(c.chainSem, c.RPCURL, c.SubmitRPCURL, c.ReadRPCURL)
+	client, err := c.dialEndpoint(ctx, c.RPCURL)
 	if err != nil {
 		return fmt.Errorf("failed to connect to client: %v", err)
 	}
 	c.Client = client
+	c.txSigner = txSigner
+
+	// Connect to the transaction submission endpoint
+	if c.SubmitRPCURL != "" {
+		submitClient, err := c.dialEndpoint(ctx, c.SubmitRPCURL)
+		if err != nil {
+			return fmt.Errorf("failed to connect to submission endpoint: %v", err)
+		}
+		c.submitClient = submitClient
+	}
+
+	// Connect to the read endpoint
+	if c.ReadRPCURL != "" {
+		readClient, err := c.dialEndpoint(ctx, c.ReadRPCURL)
+		if err != nil {
+			return fmt.Errorf("failed to connect to read endpoint: %v", err)
+		}
+		c.readClient = readClient
+	}
 
 	// Set up authenticator and contract binding
-	if privateKey != "" {
-		auth, err := createAuthenticator(ctx, client, privateKey)
+	if txSigner != nil {
+		auth, err := createAuthenticator(ctx, ctx, client, txSigner)
 		if err != nil {
 			return fmt.Errorf("failed to create authenticator: %v", err)
 		}
 		c.Auth = auth
 	}
 
-	// Initialize contract binding
-	contract, err := contracts.NewIntent(common.HexToAddress(c.IntentAddress), client)
+	// Initialize contract binding with the baked-in ABI, a custom one can be set with SetIntentABI
+	intentABI, err := contracts.LoadIntentABI("")
+	if err != nil {
+		return fmt.Errorf("failed to parse intent ABI: %v", err)
+	}
+	contract, intentContracts, err := c.bindIntentContracts(intentABI, c.contractBackend(client, c.readClient, c.submitClient))
 	if err != nil {
 		return fmt.Errorf("failed to initialize contract: %v", err)
 	}
 	c.IntentContract = contract
+	c.intentContracts = intentContracts
+	c.intentABI = intentABI
+	c.fulfillMethod = config.DefaultIntentFulfillMethod
 
 	return nil
 }
 
 // Helper function to create authenticator
-func createAuthenticator(ctx context.Context, client *ethclient.Client, privateKeyHex string) (*bind.TransactOpts, error) {
-	// Parse private key
-	privateKey, err := crypto.HexToECDSA(privateKeyHex)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse private key: %v", err)
-	}
-
+// ctx bounds the chain ID request, signerCtx is used by the signer for every transaction and must outlive the client
+func createAuthenticator(
+	ctx context.Context,
+	signerCtx context.Context,
+	client *ethclient.Client,
+	txSigner signer.Signer,
+) (*bind.TransactOpts, error) {
 	// Get chain ID
 	chainID, err := client.ChainID(ctx)
 	if err != nil {
@@ -244,7 +676,7 @@ func createAuthenticator(ctx context.Context, client *ethclient.Client, privateK
 	}
 
 	// Create transaction signer
-	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
+	auth, err := signer.NewTransactOpts(signerCtx, txSigner, chainID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create transactor: %v", err)
 	}
@@ -0,0 +1,212 @@
+package chainclient
+
+import (
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/speedrun-hq/speedrunner/pkg/config"
+	"github.com/speedrun-hq/speedrunner/pkg/contracts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestIsTokenAllowed tests the restriction of the token types fulfilled on a chain
+func TestIsTokenAllowed(t *testing.T) {
+	client := &Client{}
+	assert.True(t, client.IsTokenAllowed("USDT"))
+
+	t.Setenv("CHAIN_1_ALLOWED_TOKENS", "usdc, NATIVE")
+	allowed, err := config.GetEnvChainAllowedTokens(1)
+	require.NoError(t, err)
+	client.AllowedTokens = allowed
+
+	assert.True(t, client.IsTokenAllowed("USDC"))
+	assert.True(t, client.IsTokenAllowed("NATIVE"))
+	assert.False(t, client.IsTokenAllowed("USDT"))
+	assert.False(t, client.IsTokenAllowed(""))
+
+	t.Setenv("CHAIN_1_ALLOWED_TOKENS", "USDC,DAI")
+	_, err = config.GetEnvChainAllowedTokens(1)
+	assert.Error(t, err)
+}
+
+// TestApprovalGasPrice tests the multiplier applied on the fulfill gas price for approval transactions
+func TestApprovalGasPrice(t *testing.T) {
+	gasPrice := big.NewInt(1_000_000_000)
+
+	multiplier, err := config.GetEnvChainApprovalGasMultiplier(1)
+	require.NoError(t, err)
+	client := &Client{ApprovalGasMultiplier: multiplier}
+	assert.Equal(t, gasPrice, client.ApprovalGasPrice(gasPrice))
+	assert.Nil(t, client.ApprovalGasPrice(nil))
+
+	t.Setenv("CHAIN_1_APPROVAL_GAS_MULTIPLIER", "1.5")
+	multiplier, err = config.GetEnvChainApprovalGasMultiplier(1)
+	require.NoError(t, err)
+	client.ApprovalGasMultiplier = multiplier
+	assert.Equal(t, big.NewInt(1_500_000_000), client.ApprovalGasPrice(gasPrice))
+	assert.Equal(t, big.NewInt(1_000_000_000), gasPrice)
+
+	t.Setenv("CHAIN_1_APPROVAL_GAS_MULTIPLIER", "0")
+	_, err = config.GetEnvChainApprovalGasMultiplier(1)
+	assert.Error(t, err)
+}
+
+// TestReceiver tests the substitution of the intent recipient by the configured receiver overrides
+func TestReceiver(t *testing.T) {
+	recipient := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	usdcReceiver := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	allReceiver := common.HexToAddress("0x3333333333333333333333333333333333333333")
+
+	client := &Client{}
+	assert.Equal(t, recipient, client.Receiver("USDC", recipient))
+
+	t.Setenv("CHAIN_1_RECEIVER_OVERRIDES", "usdc:"+usdcReceiver.Hex())
+	overrides, err := config.GetEnvChainReceiverOverrides(1)
+	require.NoError(t, err)
+	client.ReceiverOverrides = overrides
+
+	assert.Equal(t, usdcReceiver, client.Receiver("USDC", recipient))
+	assert.Equal(t, recipient, client.Receiver("USDT", recipient))
+
+	t.Setenv("CHAIN_1_RECEIVER_OVERRIDES", "USDC:"+usdcReceiver.Hex()+", *:"+allReceiver.Hex())
+	overrides, err = config.GetEnvChainReceiverOverrides(1)
+	require.NoError(t, err)
+	client.ReceiverOverrides = overrides
+
+	assert.Equal(t, usdcReceiver, client.Receiver("USDC", recipient))
+	assert.Equal(t, allReceiver, client.Receiver("NATIVE", recipient))
+
+	for _, invalid := range []string{
+		"DAI:" + usdcReceiver.Hex(),
+		"USDC",
+		"USDC:0x1234",
+		"USDC:0x0000000000000000000000000000000000000000",
+	} {
+		t.Setenv("CHAIN_1_RECEIVER_OVERRIDES", invalid)
+		_, err = config.GetEnvChainReceiverOverrides(1)
+		assert.Error(t, err, invalid)
+	}
+}
+
+// TestIntentContracts tests the selection of the Intent contract of an intent among the contracts of the chain
+func TestIntentContracts(t *testing.T) {
+	defaultAddress := common.HexToAddress("0x999fce149FD078DCFaa2C681e060e00F528552f4")
+	v2Address := common.HexToAddress("0x4444444444444444444444444444444444444444")
+
+	t.Setenv("CHAIN_8453_INTENT_ADDRESSES", strings.ToLower(v2Address.Hex()))
+	intentAddresses, err := config.GetEnvChainIntentAddresses(8453)
+	require.NoError(t, err)
+
+	client := &Client{
+		ChainID:         8453,
+		IntentAddress:   defaultAddress.Hex(),
+		IntentAddresses: intentAddresses,
+		Client:          newFakeEthClient(t),
+	}
+	intentABI, err := contracts.LoadIntentABI("")
+	require.NoError(t, err)
+	require.NoError(t, client.SetIntentABI(intentABI, config.DefaultIntentFulfillMethod))
+	assert.Len(t, client.intentContracts, 2)
+	assert.Equal(t, client.intentContracts[defaultAddress], client.IntentContract)
+
+	address, err := client.ResolveIntentAddress("")
+	require.NoError(t, err)
+	assert.Equal(t, defaultAddress, address)
+
+	address, err = client.ResolveIntentAddress(strings.ToLower(v2Address.Hex()))
+	require.NoError(t, err)
+	assert.Equal(t, v2Address, address)
+
+	assert.True(t, client.HasIntentContract(v2Address.Hex()))
+	assert.False(t, client.HasIntentContract("0x5555555555555555555555555555555555555555"))
+	assert.False(t, client.HasIntentContract("v2"))
+
+	// transactions are only sent to bound contracts
+	_, err = client.Fulfill(&bind.TransactOpts{}, common.HexToAddress("0x5555555555555555555555555555555555555555"),
+		[32]byte{}, common.Address{}, big.NewInt(1), common.Address{})
+	assert.ErrorContains(t, err, "no binding")
+
+	t.Setenv("CHAIN_8453_INTENT_ADDRESSES", "0x1234")
+	_, err = config.GetEnvChainIntentAddresses(8453)
+	assert.Error(t, err)
+}
+
+// TestFulfillBatch verifies batches are only sent with a multicall method and pack a fulfill call per intent
+func TestFulfillBatch(t *testing.T) {
+	client := &Client{
+		ChainID:       8453,
+		IntentAddress: "0x999fce149FD078DCFaa2C681e060e00F528552f4",
+		Client:        newFakeEthClient(t),
+	}
+	intentABI, err := contracts.LoadIntentABI("")
+	require.NoError(t, err)
+	require.NoError(t, client.SetIntentABI(intentABI, config.DefaultIntentFulfillMethod))
+	assert.False(t, client.SupportsMulticall())
+
+	_, err = client.FulfillBatch(&bind.TransactOpts{}, common.HexToAddress(client.IntentAddress),
+		[][32]byte{{1}}, common.Address{}, []*big.Int{big.NewInt(1)}, common.Address{})
+	assert.ErrorContains(t, err, "no multicall(bytes[]) method")
+
+	multicallABI := strings.TrimSuffix(strings.TrimSpace(contracts.IntentABI), "]") +
+		`,{"type":"function","name":"multicall","inputs":[{"name":"data","type":"bytes[]"}],"outputs":[]}]`
+	parsed, err := abi.JSON(strings.NewReader(multicallABI))
+	require.NoError(t, err)
+	require.NoError(t, client.SetIntentABI(parsed, config.DefaultIntentFulfillMethod))
+	assert.True(t, client.SupportsMulticall())
+
+	// native token intents can't share the value of the transaction
+	_, err = client.FulfillBatch(&bind.TransactOpts{Value: big.NewInt(1)}, common.HexToAddress(client.IntentAddress),
+		[][32]byte{{1}}, common.Address{}, []*big.Int{big.NewInt(1)}, common.Address{})
+	assert.ErrorContains(t, err, "cannot batch native token intents")
+
+	asset := common.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913")
+	receiver := common.HexToAddress("0x1234567890abcdef1234567890abcdef12345678")
+	calls, err := client.batchCalls([][32]byte{{1}, {2}}, asset, []*big.Int{big.NewInt(10), big.NewInt(20)}, receiver)
+	require.NoError(t, err)
+	require.Len(t, calls, 2)
+	method := parsed.Methods[config.DefaultIntentFulfillMethod]
+	args, err := method.Inputs.Unpack(calls[1][4:])
+	require.NoError(t, err)
+	assert.Equal(t, [32]byte{2}, args[0])
+	assert.Equal(t, asset, args[1])
+	assert.Equal(t, big.NewInt(20), args[2])
+	assert.Equal(t, receiver, args[3])
+
+	_, err = client.batchCalls([][32]byte{{1}}, asset, nil, receiver)
+	assert.Error(t, err)
+}
+
+// TestRuntimeFeeLimits verifies the min fee and max gas price can be updated while being read, run with -race
+func TestRuntimeFeeLimits(t *testing.T) {
+	client := &Client{MinFee: big.NewInt(100), MaxGasPrice: big.NewInt(1000)}
+
+	var wg sync.WaitGroup
+	for i := int64(1); i <= 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			client.SetMinFee(big.NewInt(100 * i))
+			client.SetMaxGasPrice(big.NewInt(1000 * i))
+		}()
+		go func() {
+			defer wg.Done()
+			assert.NotNil(t, client.GetMinFee())
+			assert.True(t, client.IsWithinMax(big.NewInt(1000)))
+		}()
+	}
+	wg.Wait()
+
+	client.SetMinFee(big.NewInt(42))
+	assert.Equal(t, big.NewInt(42), client.GetMinFee())
+
+	// a nil max gas price is no cap
+	client.SetMaxGasPrice(nil)
+	assert.Nil(t, client.GetMaxGasPrice())
+	assert.True(t, client.IsWithinMax(big.NewInt(1_000_000_000_000)))
+}
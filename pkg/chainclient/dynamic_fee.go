@@ -0,0 +1,164 @@
+package chainclient
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/speedrun-hq/speedrunner/pkg/metrics"
+)
+
+// baseFeeTimeout is the maximum duration of the request of the base fee for the fee cap of a transaction
+const baseFeeTimeout = 5 * time.Second
+
+// withDynamicFee returns a copy of opts sending a legacy transaction as a dynamic fee transaction on chains with
+// a base fee, so that the tip floor and the fee cap apply, the gas price is used as fee cap with the part above
+// the base fee as tip. opts is returned unchanged for dynamic fee transactions or if the chain has no base fee
+func (c *Client) withDynamicFee(opts *bind.TransactOpts) *bind.TransactOpts {
+	if opts.GasPrice == nil {
+		return opts
+	}
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, baseFeeTimeout)
+	defer cancel()
+
+	baseFee, err := c.latestBaseFee(ctx)
+	if err != nil {
+		c.logger.DebugWithChain(c.ChainID, "Sending legacy transaction: %v", err)
+		return opts
+	}
+
+	dynamic := *opts
+	dynamic.GasFeeCap = new(big.Int).Set(opts.GasPrice)
+	dynamic.GasTipCap = tipAbove(opts.GasPrice, baseFee)
+	dynamic.GasPrice = nil
+	return &dynamic
+}
+
+// tipAbove returns the part of the gas price above the base fee, zero if the gas price is below
+func tipAbove(gasPrice, baseFee *big.Int) *big.Int {
+	tip := new(big.Int).Sub(gasPrice, baseFee)
+	if tip.Sign() < 0 {
+		tip.SetInt64(0)
+	}
+	return tip
+}
+
+// withMinTip returns opts with the priority fee raised to MinTip for dynamic fee transactions, the fee cap is
+// raised to the tip if lower so that the transaction stays valid. Legacy transactions are returned unchanged.
+// The effective tip is recorded for the status and metrics
+func (c *Client) withMinTip(opts *bind.TransactOpts) *bind.TransactOpts {
+	if opts.GasTipCap == nil {
+		return opts
+	}
+
+	tipped := opts
+	if c.MinTip != nil && opts.GasTipCap.Cmp(c.MinTip) < 0 {
+		c.logger.DebugWithChain(c.ChainID, "Raising priority fee from %s to the tip floor %s",
+			opts.GasTipCap.String(), c.MinTip.String())
+		copied := *opts
+		copied.GasTipCap = new(big.Int).Set(c.MinTip)
+		if copied.GasFeeCap != nil && copied.GasFeeCap.Cmp(copied.GasTipCap) < 0 {
+			copied.GasFeeCap = new(big.Int).Set(copied.GasTipCap)
+		}
+		tipped = &copied
+	}
+
+	c.mu.Lock()
+	c.EffectiveTip = new(big.Int).Set(tipped.GasTipCap)
+	c.mu.Unlock()
+
+	tipGwei, _ := new(big.Float).Quo(new(big.Float).SetInt(tipped.GasTipCap), big.NewFloat(1e9)).Float64()
+	metrics.PriorityFee.WithLabelValues(strconv.Itoa(c.ChainID)).Set(tipGwei)
+	return tipped
+}
+
+// withFeeCap returns opts with the fee cap of dynamic fee transactions set to the base fee of the latest block
+// times BaseFeeMultiplier plus the tip, capped by the max gas price, the tip is lowered to the fee cap if above.
+// Legacy transactions are returned unchanged, the fee cap of opts is kept if the base fee can't be fetched
+func (c *Client) withFeeCap(opts *bind.TransactOpts) *bind.TransactOpts {
+	if opts.GasTipCap == nil {
+		return opts
+	}
+
+	capped := *opts
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, baseFeeTimeout)
+	defer cancel()
+
+	if baseFee, err := c.latestBaseFee(ctx); err != nil {
+		c.logger.DebugWithChain(c.ChainID, "Keeping the fee cap of the transaction: %v", err)
+	} else {
+		capped.GasFeeCap = feeCap(baseFee, c.BaseFeeMultiplier, capped.GasTipCap)
+	}
+
+	if maxGasPrice := c.GetMaxGasPrice(); maxGasPrice != nil && capped.GasFeeCap != nil && capped.GasFeeCap.Cmp(maxGasPrice) > 0 {
+		capped.GasFeeCap = new(big.Int).Set(maxGasPrice)
+	}
+	if capped.GasFeeCap != nil && capped.GasTipCap.Cmp(capped.GasFeeCap) > 0 {
+		capped.GasTipCap = new(big.Int).Set(capped.GasFeeCap)
+	}
+	return &capped
+}
+
+// feeCap returns the fee cap of a dynamic fee transaction, baseFee * multiplier + tip
+func feeCap(baseFee *big.Int, multiplier float64, tip *big.Int) *big.Int {
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	scaled, _ := new(big.Float).Mul(new(big.Float).SetInt(baseFee), big.NewFloat(multiplier)).Int(nil)
+	return scaled.Add(scaled, tip)
+}
+
+// dynamicFeeTip returns the priority fee of a dynamic fee transaction paying gasPrice, the part of the gas price
+// above the base fee of the latest block, or the tip suggested by the node capped by gasPrice if the base fee
+// can't be fetched
+func (c *Client) dynamicFeeTip(ctx context.Context, gasPrice *big.Int) (*big.Int, error) {
+	if baseFee, err := c.latestBaseFee(ctx); err == nil {
+		return tipAbove(gasPrice, baseFee), nil
+	}
+
+	client := c.ReadClient()
+	if client == nil {
+		return nil, fmt.Errorf("client not connected")
+	}
+	tip, err := rpcCall(ctx, c, "SuggestGasTipCap", func() (*big.Int, error) {
+		return client.SuggestGasTipCap(ctx)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the priority fee: %v", err)
+	}
+	if tip.Cmp(gasPrice) > 0 {
+		tip = new(big.Int).Set(gasPrice)
+	}
+	return tip, nil
+}
+
+// latestBaseFee returns the base fee of the latest block
+func (c *Client) latestBaseFee(ctx context.Context) (*big.Int, error) {
+	client := c.ReadClient()
+	if client == nil {
+		return nil, fmt.Errorf("client not connected")
+	}
+	header, err := rpcCall(ctx, c, "HeaderByNumber", func() (*types.Header, error) {
+		return client.HeaderByNumber(ctx, nil)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest block: %v", err)
+	}
+	if header.BaseFee == nil {
+		return nil, fmt.Errorf("no base fee in block %s", header.Number.String())
+	}
+	return header.BaseFee, nil
+}
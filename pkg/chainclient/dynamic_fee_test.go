@@ -0,0 +1,176 @@
+package chainclient
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/speedrun-hq/speedrunner/pkg/config"
+	"github.com/speedrun-hq/speedrunner/pkg/contracts"
+	"github.com/speedrun-hq/speedrunner/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWithMinTip verifies the priority fee of dynamic fee transactions is raised to the tip floor
+func TestWithMinTip(t *testing.T) {
+	t.Setenv("CHAIN_1_MIN_TIP", "2000000000")
+	minTip, err := config.GetEnvChainMinTip(1)
+	require.NoError(t, err)
+	c := &Client{ChainID: 1, MinTip: minTip, logger: &logger.EmptyLogger{}}
+
+	// legacy transactions are unchanged
+	legacy := &bind.TransactOpts{GasPrice: big.NewInt(1_000_000_000)}
+	assert.Same(t, legacy, c.withMinTip(legacy))
+	assert.Nil(t, c.GetEffectiveTip())
+
+	// the tip and the fee cap are raised to the floor
+	low := &bind.TransactOpts{GasTipCap: big.NewInt(1_000_000_000), GasFeeCap: big.NewInt(1_500_000_000)}
+	tipped := c.withMinTip(low)
+	assert.Equal(t, big.NewInt(2_000_000_000), tipped.GasTipCap)
+	assert.Equal(t, big.NewInt(2_000_000_000), tipped.GasFeeCap)
+	assert.Equal(t, big.NewInt(1_000_000_000), low.GasTipCap)
+	assert.Equal(t, big.NewInt(2_000_000_000), c.GetEffectiveTip())
+
+	// a tip above the floor is kept
+	high := &bind.TransactOpts{GasTipCap: big.NewInt(3_000_000_000), GasFeeCap: big.NewInt(5_000_000_000)}
+	assert.Same(t, high, c.withMinTip(high))
+	assert.Equal(t, big.NewInt(3_000_000_000), c.GetEffectiveTip())
+
+	t.Setenv("CHAIN_1_MIN_TIP", "-1")
+	_, err = config.GetEnvChainMinTip(1)
+	assert.Error(t, err)
+}
+
+// fakeBaseFeeService serves eth_getBlockByNumber returning a block with the given base fee
+type fakeBaseFeeService struct {
+	baseFee *big.Int
+}
+
+func (s *fakeBaseFeeService) GetBlockByNumber(_ string, _ bool) *types.Header {
+	return &types.Header{Number: big.NewInt(100), Difficulty: big.NewInt(0), BaseFee: s.baseFee}
+}
+
+// TestWithFeeCap verifies the fee cap of dynamic fee transactions follows the base fee multiplier and the max gas price
+func TestWithFeeCap(t *testing.T) {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", &fakeBaseFeeService{baseFee: big.NewInt(10_000_000_000)}))
+	t.Cleanup(server.Stop)
+	c := &Client{
+		ChainID:           1,
+		Client:            ethclient.NewClient(rpc.DialInProc(server)),
+		BaseFeeMultiplier: 2,
+		logger:            &logger.EmptyLogger{},
+	}
+
+	// legacy transactions are unchanged
+	legacy := &bind.TransactOpts{GasPrice: big.NewInt(1_000_000_000)}
+	assert.Same(t, legacy, c.withFeeCap(legacy))
+
+	// the fee cap is the base fee times the multiplier plus the tip
+	opts := &bind.TransactOpts{GasTipCap: big.NewInt(1_000_000_000), GasFeeCap: big.NewInt(100_000_000_000)}
+	capped := c.withFeeCap(opts)
+	assert.Equal(t, big.NewInt(21_000_000_000), capped.GasFeeCap)
+	assert.Equal(t, big.NewInt(1_000_000_000), capped.GasTipCap)
+	assert.Equal(t, big.NewInt(100_000_000_000), opts.GasFeeCap)
+
+	// the fee cap is clamped to the max gas price, the tip is lowered to the fee cap
+	c.SetMaxGasPrice(big.NewInt(15_000_000_000))
+	capped = c.withFeeCap(opts)
+	assert.Equal(t, big.NewInt(15_000_000_000), capped.GasFeeCap)
+	c.SetMaxGasPrice(big.NewInt(500_000_000))
+	capped = c.withFeeCap(opts)
+	assert.Equal(t, big.NewInt(500_000_000), capped.GasFeeCap)
+	assert.Equal(t, big.NewInt(500_000_000), capped.GasTipCap)
+
+	t.Setenv("CHAIN_1_BASEFEE_MULTIPLIER", "1.5")
+	multiplier, err := config.GetEnvChainBaseFeeMultiplier(1)
+	require.NoError(t, err)
+	assert.Equal(t, 1.5, multiplier)
+
+	t.Setenv("CHAIN_1_BASEFEE_MULTIPLIER", "0.5")
+	_, err = config.GetEnvChainBaseFeeMultiplier(1)
+	assert.Error(t, err)
+}
+
+// fakeTipService serves eth_maxPriorityFeePerGas returning the given tip
+type fakeTipService struct {
+	tip *big.Int
+}
+
+func (s *fakeTipService) MaxPriorityFeePerGas() *hexutil.Big {
+	return (*hexutil.Big)(s.tip)
+}
+
+// TestDynamicFeeTip verifies the tip of a legacy gas price is the part above the base fee, or the suggested tip
+func TestDynamicFeeTip(t *testing.T) {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", &fakeBaseFeeService{baseFee: big.NewInt(10_000_000_000)}))
+	t.Cleanup(server.Stop)
+	c := &Client{ChainID: 1, Client: ethclient.NewClient(rpc.DialInProc(server)), logger: &logger.EmptyLogger{}}
+
+	tip, err := c.dynamicFeeTip(context.Background(), big.NewInt(12_000_000_000))
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(2_000_000_000), tip)
+
+	// no tip below the base fee
+	tip, err = c.dynamicFeeTip(context.Background(), big.NewInt(8_000_000_000))
+	require.NoError(t, err)
+	assert.Zero(t, tip.Sign())
+
+	// without base fee the suggested tip is used, capped by the gas price
+	tipServer := rpc.NewServer()
+	require.NoError(t, tipServer.RegisterName("eth", &fakeTipService{tip: big.NewInt(3_000_000_000)}))
+	t.Cleanup(tipServer.Stop)
+	c.Client = ethclient.NewClient(rpc.DialInProc(tipServer))
+
+	tip, err = c.dynamicFeeTip(context.Background(), big.NewInt(5_000_000_000))
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(3_000_000_000), tip)
+	tip, err = c.dynamicFeeTip(context.Background(), big.NewInt(1_000_000_000))
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1_000_000_000), tip)
+}
+
+// TestFulfillDynamicFee verifies fulfillments with a legacy gas price are sent as dynamic fee transactions on chains
+// with a base fee, with the tip floor and the fee cap applied
+func TestFulfillDynamicFee(t *testing.T) {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", &fakeBaseFeeService{baseFee: big.NewInt(10_000_000_000)}))
+	t.Cleanup(server.Stop)
+	c := &Client{
+		ChainID:           1,
+		IntentAddress:     "0x999fce149FD078DCFaa2C681e060e00F528552f4",
+		Client:            ethclient.NewClient(rpc.DialInProc(server)),
+		BaseFeeMultiplier: 2,
+		MinTip:            big.NewInt(3_000_000_000),
+		logger:            &logger.EmptyLogger{},
+	}
+	intentABI, err := contracts.LoadIntentABI("")
+	require.NoError(t, err)
+	require.NoError(t, c.SetIntentABI(intentABI, config.DefaultIntentFulfillMethod))
+
+	opts := &bind.TransactOpts{
+		GasPrice: big.NewInt(12_000_000_000),
+		GasLimit: 100000,
+		Nonce:    big.NewInt(1),
+		NoSend:   true,
+		Signer: func(_ common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return tx, nil
+		},
+	}
+
+	tx, err := c.Fulfill(opts, common.HexToAddress(c.IntentAddress), [32]byte{1}, common.Address{}, big.NewInt(1), common.Address{})
+	require.NoError(t, err)
+	assert.Equal(t, uint8(types.DynamicFeeTxType), tx.Type())
+	// the 2 gwei tip above the base fee is raised to the floor, the fee cap is base fee * 2 + tip
+	assert.Equal(t, big.NewInt(3_000_000_000), tx.GasTipCap())
+	assert.Equal(t, big.NewInt(23_000_000_000), tx.GasFeeCap())
+	assert.Equal(t, big.NewInt(12_000_000_000), opts.GasPrice)
+}
@@ -10,17 +10,55 @@ import (
 	"sync"
 	"time"
 
+	"github.com/speedrun-hq/speedrunner/pkg/config"
 	"github.com/speedrun-hq/speedrunner/pkg/logger"
+	"github.com/speedrun-hq/speedrunner/pkg/registry"
+	"golang.org/x/sync/singleflight"
 )
 
+var (
+	// coinGeckoAPIURL is the base URL of the CoinGecko API used for token prices
+	coinGeckoAPIURL = "https://api.coingecko.com/api/v3"
+
+	// priceRequestGroup coalesces concurrent price requests for the same token ID
+	priceRequestGroup singleflight.Group
+
+	// coalescedPriceRequestTimeout bounds a coalesced price request and its retries, the request is shared by
+	// the callers so it doesn't run with the context of the first one
+	coalescedPriceRequestTimeout = 30 * time.Second
+
+	priceCoalescingMu      sync.RWMutex
+	priceCoalescingEnabled = true
+
+	priceRetryMu      sync.RWMutex
+	priceRetries      = config.DefaultPriceRequestRetries
+	priceRetryBackoff = config.DefaultPriceRequestBackoff * time.Second
+)
+
+// SetPriceRequestCoalescing enables or disables the coalescing of concurrent price requests for the same token
+func SetPriceRequestCoalescing(enabled bool) {
+	priceCoalescingMu.Lock()
+	defer priceCoalescingMu.Unlock()
+	priceCoalescingEnabled = enabled
+}
+
+// isPriceCoalescingEnabled returns whether concurrent price requests are coalesced
+func isPriceCoalescingEnabled() bool {
+	priceCoalescingMu.RLock()
+	defer priceCoalescingMu.RUnlock()
+	return priceCoalescingEnabled
+}
+
 // FeeUpdateRoutine manages the periodic updates of gas price, token price, and withdraw fee
 type FeeUpdateRoutine struct {
 	ctx      context.Context
 	client   *Client
 	interval time.Duration
 	stopChan chan struct{}
+	done     chan struct{}
 	mu       sync.RWMutex
 	running  bool
+	monitor  connectionMonitor
 	logger   logger.Logger
 }
 
@@ -46,12 +84,13 @@ func (r *FeeUpdateRoutine) Start() {
 	}
 
 	r.stopChan = make(chan struct{})
+	r.done = make(chan struct{})
 	r.running = true
 
-	go r.run()
+	go r.run(r.stopChan, r.done)
 }
 
-// Stop halts the periodic fee updates
+// Stop halts the periodic fee updates and waits for the goroutine to exit
 func (r *FeeUpdateRoutine) Stop() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -61,7 +100,9 @@ func (r *FeeUpdateRoutine) Stop() {
 	}
 
 	close(r.stopChan)
+	<-r.done
 	r.stopChan = nil
+	r.done = nil
 	r.running = false
 }
 
@@ -72,25 +113,28 @@ func (r *FeeUpdateRoutine) IsRunning() bool {
 	return r.running
 }
 
-// run is the main goroutine that performs periodic updates
-func (r *FeeUpdateRoutine) run() {
+// run is the main goroutine that performs periodic updates, done is closed when it exits
+func (r *FeeUpdateRoutine) run(stopChan <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
 	ticker := time.NewTicker(r.interval)
 	defer ticker.Stop()
 
-	// Perform initial update
+	// Perform initial update, failures are retried on the next tick
 	if err := r.updatePrices(); err != nil {
 		r.logger.ErrorWithChain(r.client.ChainID, "Failed to perform initial fee update: %v", err)
-		return
 	}
 
 	for {
 		select {
 		case <-ticker.C:
+			// Reconnect if the RPC connection dropped before updating
+			r.checkConnection()
+
 			if err := r.updatePrices(); err != nil {
-				r.logger.ErrorWithChain(r.client.ChainID, "Failed to perform initial fee update: %v", err)
-				return
+				r.logger.ErrorWithChain(r.client.ChainID, "Failed to perform fee update: %v", err)
 			}
-		case <-r.stopChan:
+		case <-stopChan:
 			return
 		}
 	}
@@ -105,26 +149,41 @@ func (r *FeeUpdateRoutine) updatePrices() error {
 	}
 
 	// Update token price
-	tokenPrice, err := getTokenPriceUSD(r.ctx, r.client.ChainID)
+	tokenPrice, err := r.client.gasTokenPriceUSD(r.ctx)
 	if err != nil {
 		return fmt.Errorf("failed to fetch token price for chain %d: %v", r.client.ChainID, err)
 	}
 
 	// Compute withdraw fee
-	withdrawFee := computeWithdrawFee(gasPrice, tokenPrice)
+	withdrawFee := computeWithdrawFee(gasPrice, r.client.WithdrawGas, tokenPrice)
+
+	// Add the L1 data fee paid by rollups, keep the previous estimate if the oracle can't be reached
+	l1FeeUSD := r.client.GetL1FeeUSD()
+	if r.client.IsRollup() {
+		l1Fee, err := r.client.EstimateL1Fee(r.ctx)
+		if err != nil {
+			r.logger.ErrorWithChain(r.client.ChainID, "Failed to estimate L1 data fee: %v", err)
+		} else {
+			l1FeeUSD = computeFeeUSD(l1Fee, r.client.nativeDecimals(), tokenPrice)
+		}
+		withdrawFee += l1FeeUSD
+	}
 
 	// Store the values in the client
 	r.client.mu.Lock()
 	r.client.CurrentGasPrice = gasPrice
 	r.client.TokenPriceUSD = tokenPrice
+	r.client.L1FeeUSD = l1FeeUSD
 	r.client.WithdrawFeeUSD = withdrawFee
+	r.client.FeeUpdatedAt = time.Now()
 	r.client.mu.Unlock()
 
 	// Log the updated values
 	r.logger.InfoWithChain(r.client.ChainID,
-		"Updated gas price: %s, Token price: $%.2f, Withdraw fee: $%.2f",
+		"Updated gas price: %s, Token price: $%.2f, L1 fee: $%.4f, Withdraw fee: $%.2f",
 		gasPrice.String(),
 		tokenPrice,
+		l1FeeUSD,
 		withdrawFee,
 	)
 
@@ -133,82 +192,175 @@ func (r *FeeUpdateRoutine) updatePrices() error {
 	return nil
 }
 
+// SetPriceRequestRetries sets the number of retries of failed token price requests and the delay before the first retry
+func SetPriceRequestRetries(retries int, backoff time.Duration) {
+	priceRetryMu.Lock()
+	defer priceRetryMu.Unlock()
+	priceRetries = retries
+	priceRetryBackoff = backoff
+}
+
+// priceRequestRetryPolicy returns the number of retries of failed token price requests and the initial backoff
+func priceRequestRetryPolicy() (int, time.Duration) {
+	priceRetryMu.RLock()
+	defer priceRetryMu.RUnlock()
+	return priceRetries, priceRetryBackoff
+}
+
+// priceIDValidationTimeout is the maximum duration of the gas token price check at startup
+const priceIDValidationTimeout = 15 * time.Second
+
+// gasTokenPriceUSD fetches the current USD price of the gas token of the chain, with PriceID if set
+func (c *Client) gasTokenPriceUSD(ctx context.Context) (float64, error) {
+	if c.PriceID != "" {
+		return getTokenPriceUSDByID(ctx, c.PriceID)
+	}
+	return getTokenPriceUSD(ctx, c.ChainID)
+}
+
+// validatePriceID checks the price ID of the gas token resolves to a price, logging an error if it doesn't
+// the price is cached for the first fee update
+func (c *Client) validatePriceID(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, priceIDValidationTimeout)
+	defer cancel()
+
+	if _, err := c.gasTokenPriceUSD(ctx); err != nil {
+		c.logger.ErrorWithChain(c.ChainID, "Gas token price ID %q does not resolve to a price: %v, "+
+			"set CHAIN_%d_PRICE_ID to the CoinGecko API id of the gas token", c.PriceID, err, c.ChainID)
+	}
+}
+
 // getTokenPriceUSD fetches the current USD price for the gas token of a specific chain
 func getTokenPriceUSD(ctx context.Context, chainID int) (float64, error) {
-	// Map chain IDs to CoinGecko token IDs
-	tokenIDs := map[int]string{
-		1:     "ethereum",      // Ethereum
-		137:   "matic-network", // Polygon
-		42161: "ethereum",      // Arbitrum (uses ETH)
-		8453:  "ethereum",      // Base (uses ETH)
-		56:    "binancecoin",   // BSC
-		43114: "avalanche-2",   // Avalanche
-		7000:  "zetachain",     // ZetaChain
-	}
-
-	tokenID, exists := tokenIDs[chainID]
-	if !exists {
+	tokenID := gasTokenPriceID(chainID)
+	if tokenID == "" {
 		return 0, fmt.Errorf("unsupported chain ID for price fetching: %d", chainID)
 	}
+	return getTokenPriceUSDByID(ctx, tokenID)
+}
 
+// gasTokenPriceID returns the CoinGecko API id of the gas token of the chain from the chain registry,
+// overridden by CHAIN_<ID>_PRICE_ID in the chain client
+func gasTokenPriceID(chainID int) string {
+	chain, exists := registry.Chains.Get(chainID)
+	if !exists {
+		return ""
+	}
+	return chain.PriceID
+}
+
+// getTokenPriceUSDByID fetches the current USD price of the token with the CoinGecko API id
+func getTokenPriceUSDByID(ctx context.Context, tokenID string) (float64, error) {
 	// Check cache first
 	cache := getOrCreateCache()
 	if cachedPrice, found := cache.Get(tokenID); found {
 		return cachedPrice, nil
 	}
 
-	// Fetch price from CoinGecko API
-	url := fmt.Sprintf("https://api.coingecko.com/api/v3/simple/price?ids=%s&vs_currencies=usd", tokenID)
+	if !isPriceCoalescingEnabled() {
+		return fetchTokenPriceUSD(ctx, tokenID, cache)
+	}
+
+	// Collapse concurrent cache misses for the same token into a single API call, the call is detached from the
+	// caller context so that a caller giving up doesn't fail the request of the others
+	result := priceRequestGroup.DoChan(tokenID, func() (interface{}, error) {
+		sharedCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), coalescedPriceRequestTimeout)
+		defer cancel()
+		return fetchTokenPriceUSD(sharedCtx, tokenID, cache)
+	})
+	select {
+	case <-ctx.Done():
+		return 0, fmt.Errorf("failed to get price of %s: %v", tokenID, ctx.Err())
+	case res := <-result:
+		if res.Err != nil {
+			return 0, res.Err
+		}
+		return res.Val.(float64), nil
+	}
+}
+
+// fetchTokenPriceUSD fetches the USD price of the token from the CoinGecko API and stores it in the cache
+// requests failing on rate limits, server or network errors are retried with an exponential backoff,
+// unless the context is done or its deadline would be exceeded before the retry
+func fetchTokenPriceUSD(ctx context.Context, tokenID string, cache *TokenPriceCache) (float64, error) {
+	retries, backoff := priceRequestRetryPolicy()
+	for attempt := 0; ; attempt++ {
+		price, retryable, err := requestTokenPriceUSD(ctx, tokenID)
+		if err == nil {
+			// Cache the price for future use
+			cache.Set(tokenID, price)
+			return price, nil
+		}
+		if !retryable || attempt >= retries {
+			return 0, err
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < backoff {
+			return 0, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// requestTokenPriceUSD requests the USD price of the token from the CoinGecko API,
+// it returns whether the request can be retried when it fails
+func requestTokenPriceUSD(ctx context.Context, tokenID string) (float64, bool, error) {
+	url := fmt.Sprintf("%s/simple/price?ids=%s&vs_currencies=usd", coinGeckoAPIURL, tokenID)
 
 	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(timeoutCtx, "GET", url, nil)
 	if err != nil {
-		return 0, fmt.Errorf("failed to create request: %v", err)
+		return 0, false, fmt.Errorf("failed to create request: %v", err)
 	}
 
 	httpClient := &http.Client{}
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return 0, fmt.Errorf("failed to fetch token price: %v", err)
+		// network errors are transient, unless the caller gave up
+		return 0, ctx.Err() == nil, fmt.Errorf("failed to fetch token price: %v", err)
 	}
 	defer func(Body io.ReadCloser) {
 		_ = Body.Close()
 	}(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("API request failed with status: %d", resp.StatusCode)
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+		return 0, retryable, fmt.Errorf("API request failed with status: %d", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return 0, fmt.Errorf("failed to read response body: %v", err)
+		return 0, true, fmt.Errorf("failed to read response body: %v", err)
 	}
 
 	var result map[string]map[string]float64
 	if err := json.Unmarshal(body, &result); err != nil {
-		return 0, fmt.Errorf("failed to parse JSON response: %v", err)
+		return 0, false, fmt.Errorf("failed to parse JSON response: %v", err)
 	}
 
 	tokenData, exists := result[tokenID]
 	if !exists {
-		return 0, fmt.Errorf("token data not found in response")
+		return 0, false, fmt.Errorf("token data not found in response")
 	}
 
 	price, exists := tokenData["usd"]
 	if !exists {
-		return 0, fmt.Errorf("USD price not found in response")
+		return 0, false, fmt.Errorf("USD price not found in response")
 	}
-
-	// Cache the price for future use
-	cache.Set(tokenID, price)
-
-	return price, nil
+	return price, false, nil
 }
 
-// computeWithdrawFee calculates the withdraw fee in USD using the formula: gasPrice * 100000
-func computeWithdrawFee(gasPrice *big.Int, tokenPriceUSD float64) float64 {
+// computeWithdrawFee calculates the withdraw fee in USD using the formula: gasPrice * gasUnits
+// The fee is paid in the gas token of the destination chain, on ZetaChain in ZETA rather than in the ZRC20 gas tokens
+// of the connected chains, so it is priced with the ZETA price and, ZetaChain not being a rollup, without L1 data fee
+func computeWithdrawFee(gasPrice *big.Int, gasUnits uint64, tokenPriceUSD float64) float64 {
 	// Handle nil gas price
 	if gasPrice == nil {
 		return 0.0
@@ -217,8 +369,8 @@ func computeWithdrawFee(gasPrice *big.Int, tokenPriceUSD float64) float64 {
 	// Convert gas price to float64 (assuming gas price is in wei)
 	gasPriceFloat := new(big.Float).SetInt(gasPrice)
 
-	// Calculate: gasPrice * 100000
-	multiplier := big.NewFloat(100000)
+	// Calculate: gasPrice * gasUnits
+	multiplier := new(big.Float).SetUint64(gasUnits)
 	result := new(big.Float).Mul(gasPriceFloat, multiplier)
 
 	// Convert to float64
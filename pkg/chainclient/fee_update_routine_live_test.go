@@ -125,7 +125,7 @@ func TestGetTokenPriceUSD_Cache_Live(t *testing.T) {
 		}
 
 		// Verify cache stats
-		count, ttl := GetGlobalCacheStats()
+		count, ttl, _ := GetGlobalCacheStats()
 		assert.Equal(t, 1, count, "Should only have 1 cached token (ethereum)")
 		assert.Equal(t, 30*time.Second, ttl)
 	})
@@ -150,7 +150,7 @@ func TestGetTokenPriceUSD_Cache_Live(t *testing.T) {
 		assert.NotEqual(t, ethPrice, maticPrice, "ETH and MATIC prices should be different")
 
 		// Verify cache stats
-		count, _ := GetGlobalCacheStats()
+		count, _, _ := GetGlobalCacheStats()
 		assert.Equal(t, 2, count, "Should have 2 cached tokens (ethereum and matic-network)")
 
 		t.Logf("Ethereum price: $%.2f", ethPrice)
@@ -170,7 +170,7 @@ func TestGetTokenPriceUSD_Cache_Live(t *testing.T) {
 		require.Greater(t, price1, 0.0)
 
 		// Verify cache hit
-		count1, _ := GetGlobalCacheStats()
+		count1, _, _ := GetGlobalCacheStats()
 		assert.Equal(t, 1, count1)
 
 		// Wait for TTL to expire
@@ -244,7 +244,7 @@ func TestGetTokenPriceUSD_Concurrent_Cache(t *testing.T) {
 		}
 
 		// Verify cache stats
-		count, _ := GetGlobalCacheStats()
+		count, _, _ := GetGlobalCacheStats()
 		assert.Equal(t, 1, count, "Should only have 1 cached token despite multiple requests")
 	})
 
@@ -286,7 +286,7 @@ func TestGetTokenPriceUSD_Concurrent_Cache(t *testing.T) {
 		}
 
 		// Verify cache stats
-		count, _ := GetGlobalCacheStats()
+		count, _, _ := GetGlobalCacheStats()
 		assert.Equal(t, 3, count, "Should have 3 cached tokens (ethereum, matic-network, binancecoin)")
 	})
 }
@@ -1,10 +1,23 @@
 package chainclient
 
 import (
+	"context"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/speedrun-hq/speedrunner/pkg/chains"
+	"github.com/speedrun-hq/speedrunner/pkg/config"
+	"github.com/speedrun-hq/speedrunner/pkg/logger"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestComputeWithdrawFee tests the ComputeWithdrawFee function with various inputs
@@ -12,6 +25,7 @@ func TestComputeWithdrawFee(t *testing.T) {
 	tests := []struct {
 		name           string
 		gasPrice       *big.Int
+		gasUnits       uint64
 		tokenPriceUSD  float64
 		expectedFeeUSD float64
 		description    string
@@ -19,27 +33,31 @@ func TestComputeWithdrawFee(t *testing.T) {
 		{
 			name:           "Low gas price, low token price",
 			gasPrice:       big.NewInt(20000000000), // 20 gwei
-			tokenPriceUSD:  1000.0,                  // $1000 per token
-			expectedFeeUSD: 2.0,                     // (20e9 * 100000) / 1e18 * 1000 = 2.0
+			gasUnits:       100000,
+			tokenPriceUSD:  1000.0, // $1000 per token
+			expectedFeeUSD: 2.0,    // (20e9 * 100000) / 1e18 * 1000 = 2.0
 			description:    "20 gwei gas price with $1000 token should result in $2.0 fee",
 		},
 		{
 			name:           "High gas price, high token price",
 			gasPrice:       big.NewInt(100000000000), // 100 gwei
-			tokenPriceUSD:  5000.0,                   // $5000 per token
-			expectedFeeUSD: 50.0,                     // (100e9 * 100000) / 1e18 * 5000 = 50.0
+			gasUnits:       100000,
+			tokenPriceUSD:  5000.0, // $5000 per token
+			expectedFeeUSD: 50.0,   // (100e9 * 100000) / 1e18 * 5000 = 50.0
 			description:    "100 gwei gas price with $5000 token should result in $50.0 fee",
 		},
 		{
 			name:           "Very low gas price",
 			gasPrice:       big.NewInt(1000000000), // 1 gwei
-			tokenPriceUSD:  1.0,                    // $1 per token
-			expectedFeeUSD: 0.0001,                 // (1e9 * 100000) / 1e18 * 1 = 0.0001
+			gasUnits:       100000,
+			tokenPriceUSD:  1.0,    // $1 per token
+			expectedFeeUSD: 0.0001, // (1e9 * 100000) / 1e18 * 1 = 0.0001
 			description:    "1 gwei gas price with $1 token should result in $0.0001 fee",
 		},
 		{
 			name:           "Zero gas price",
 			gasPrice:       big.NewInt(0),
+			gasUnits:       100000,
 			tokenPriceUSD:  1000.0,
 			expectedFeeUSD: 0.0,
 			description:    "Zero gas price should result in zero fee",
@@ -47,6 +65,7 @@ func TestComputeWithdrawFee(t *testing.T) {
 		{
 			name:           "Zero token price",
 			gasPrice:       big.NewInt(20000000000), // 20 gwei
+			gasUnits:       100000,
 			tokenPriceUSD:  0.0,
 			expectedFeeUSD: 0.0,
 			description:    "Zero token price should result in zero fee",
@@ -54,25 +73,280 @@ func TestComputeWithdrawFee(t *testing.T) {
 		{
 			name:           "Realistic Ethereum scenario",
 			gasPrice:       big.NewInt(25000000000), // 25 gwei
-			tokenPriceUSD:  3000.0,                  // $3000 per ETH
-			expectedFeeUSD: 7.5,                     // (25e9 * 100000) / 1e18 * 3000 = 7.5
+			gasUnits:       100000,
+			tokenPriceUSD:  3000.0, // $3000 per ETH
+			expectedFeeUSD: 7.5,    // (25e9 * 100000) / 1e18 * 3000 = 7.5
 			description:    "Realistic Ethereum gas price and token price scenario",
 		},
 		{
 			name:           "Very high gas price",
 			gasPrice:       big.NewInt(1000000000000), // 1000 gwei
-			tokenPriceUSD:  100.0,                     // $100 per token
-			expectedFeeUSD: 10.0,                      // (1000e9 * 100000) / 1e18 * 100 = 10.0
+			gasUnits:       100000,
+			tokenPriceUSD:  100.0, // $100 per token
+			expectedFeeUSD: 10.0,  // (1000e9 * 100000) / 1e18 * 100 = 10.0
 			description:    "Very high gas price scenario",
 		},
+		{
+			name:           "Higher gas units",
+			gasPrice:       big.NewInt(100000000), // 0.1 gwei
+			gasUnits:       300000,
+			tokenPriceUSD:  3000.0,
+			expectedFeeUSD: 0.09, // (0.1e9 * 300000) / 1e18 * 3000 = 0.09
+			description:    "Chains with higher withdraw gas units should have proportionally higher fees",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := computeWithdrawFee(tt.gasPrice, tt.tokenPriceUSD)
+			result := computeWithdrawFee(tt.gasPrice, tt.gasUnits, tt.tokenPriceUSD)
 
 			// Use approximate comparison for floating point values
 			assert.InDelta(t, tt.expectedFeeUSD, result, 0.0001, tt.description)
 		})
 	}
 }
+
+// fakeEthService serves the eth_gasPrice, eth_feeHistory, eth_blockNumber, eth_chainId and eth_getTransactionCount
+// RPC methods for in-process clients
+type fakeEthService struct{}
+
+func (s *fakeEthService) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(1))
+}
+
+func (s *fakeEthService) GasPrice() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(1000000000))
+}
+
+func (s *fakeEthService) BlockNumber() hexutil.Uint64 {
+	return 100
+}
+
+// fakeAccountNonce is the confirmed account nonce served by fakeEthService
+const fakeAccountNonce = 5
+
+// fakePendingTxs is the number of transactions pending in the mempool of fakeEthService
+const fakePendingTxs = 2
+
+func (s *fakeEthService) GetTransactionCount(_ common.Address, block string) hexutil.Uint64 {
+	if block == "pending" {
+		return fakeAccountNonce + fakePendingTxs
+	}
+	return fakeAccountNonce
+}
+
+// fakeFeeHistory is the eth_feeHistory result served by fakeEthService
+type fakeFeeHistory struct {
+	OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+	Reward       [][]*hexutil.Big `json:"reward"`
+	BaseFee      []*hexutil.Big   `json:"baseFeePerGas"`
+	GasUsedRatio []float64        `json:"gasUsedRatio"`
+}
+
+func (s *fakeEthService) FeeHistory(_ hexutil.Uint64, _ string, _ []float64) fakeFeeHistory {
+	gwei := func(n int64) *hexutil.Big {
+		return (*hexutil.Big)(big.NewInt(n * 1000000000))
+	}
+	return fakeFeeHistory{
+		OldestBlock:  (*hexutil.Big)(big.NewInt(100)),
+		Reward:       [][]*hexutil.Big{{gwei(1)}, {gwei(3)}},
+		BaseFee:      []*hexutil.Big{gwei(10), gwei(12), gwei(15)},
+		GasUsedRatio: []float64{0.5, 0.9},
+	}
+}
+
+// newFakeEthClient returns an ethclient connected to an in-process RPC server
+func newFakeEthClient(t *testing.T) *ethclient.Client {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", &fakeEthService{}))
+	t.Cleanup(server.Stop)
+	return ethclient.NewClient(rpc.DialInProc(server))
+}
+
+// TestClientCloseStopsFeeRoutine verifies the fee update goroutine exits when the client is closed
+func TestClientCloseStopsFeeRoutine(t *testing.T) {
+	ClearGlobalCache()
+	defer ClearGlobalCache()
+	getOrCreateCache().Set("ethereum", 3000.0)
+
+	client := &Client{
+		Ctx:           context.Background(),
+		ChainID:       1,
+		Client:        newFakeEthClient(t),
+		GasMultiplier: 1.0,
+		logger:        &logger.EmptyLogger{},
+	}
+
+	client.StartFeeUpdateRoutine(10 * time.Millisecond)
+	routine := client.feeRoutine
+	require.NotNil(t, routine)
+	done := routine.done
+
+	// Wait for at least one update so the routine is inside its loop
+	require.Eventually(t, func() bool {
+		return client.GetCurrentGasPrice() != nil
+	}, time.Second, 5*time.Millisecond)
+	assert.True(t, routine.IsRunning())
+
+	client.Close()
+
+	select {
+	case <-done:
+	default:
+		t.Fatal("fee update goroutine still running after Close")
+	}
+	assert.False(t, routine.IsRunning())
+	assert.Nil(t, client.feeRoutine)
+	assert.Nil(t, client.Client)
+
+	// Closing twice is a no-op
+	client.Close()
+}
+
+// TestGasSource verifies the gas price is read from the configured source
+func TestGasSource(t *testing.T) {
+	t.Run("suggested", func(t *testing.T) {
+		client := &Client{Client: newFakeEthClient(t), GasMultiplier: 1.0, GasSource: "suggested"}
+		gasPrice, err := client.EffectiveGasPrice(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(1000000000), gasPrice)
+	})
+
+	t.Run("feehistory", func(t *testing.T) {
+		client := &Client{Client: newFakeEthClient(t), GasMultiplier: 1.0, GasSource: "feehistory", GasPercentile: 50}
+		gasPrice, err := client.EffectiveGasPrice(context.Background())
+		require.NoError(t, err)
+		// next base fee 15 gwei + average tip 2 gwei
+		assert.Equal(t, big.NewInt(17000000000), gasPrice)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		client := &Client{Client: newFakeEthClient(t), GasMultiplier: 1.0, GasSource: "unknown"}
+		_, err := client.EffectiveGasPrice(context.Background())
+		assert.Error(t, err)
+	})
+}
+
+// TestMinGasPrice verifies a gas price below the floor from the gas source is raised to the floor
+func TestMinGasPrice(t *testing.T) {
+	log := logger.NewMemoryLogger()
+	client := &Client{
+		Client:        newFakeEthClient(t),
+		ChainID:       8453,
+		GasMultiplier: 1.5,
+		GasSource:     "suggested",
+		MinGasPrice:   big.NewInt(2000000000),
+		logger:        log,
+	}
+
+	gasPrice, err := client.UpdateGasPrice(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(3000000000), gasPrice)
+	assert.True(t, log.Contains(logger.NoticeLevel, "Gas price 1000000000 from the gas source is below the floor"))
+
+	// the price from the gas source is used above the floor
+	log.Reset()
+	client.MinGasPrice = big.NewInt(1000000)
+	gasPrice, err = client.EffectiveGasPrice(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1500000000), gasPrice)
+	assert.Empty(t, log.Entries())
+}
+
+// newFlakyPriceServer serves the failure statuses in order, then the ethereum price, and counts the requests
+func newFlakyPriceServer(t *testing.T, statuses ...int) *int32 {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&requests, 1))
+		if n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+		_, _ = w.Write([]byte(`{"ethereum":{"usd":3000}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	originalURL := coinGeckoAPIURL
+	coinGeckoAPIURL = server.URL
+	t.Cleanup(func() { coinGeckoAPIURL = originalURL })
+
+	retries, backoff := priceRequestRetryPolicy()
+	SetPriceRequestRetries(2, time.Millisecond)
+	t.Cleanup(func() { SetPriceRequestRetries(retries, backoff) })
+
+	ClearGlobalCache()
+	t.Cleanup(ClearGlobalCache)
+	return &requests
+}
+
+// TestGetTokenPriceUSDRetries verifies rate limited and server error responses are retried until the price is fetched
+func TestGetTokenPriceUSDRetries(t *testing.T) {
+	requests := newFlakyPriceServer(t, http.StatusTooManyRequests, http.StatusBadGateway)
+
+	price, err := getTokenPriceUSD(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3000.0, price)
+	assert.Equal(t, int32(3), atomic.LoadInt32(requests))
+}
+
+// TestGetTokenPriceUSDRetriesExhausted verifies the error is returned once the retries are exhausted
+func TestGetTokenPriceUSDRetriesExhausted(t *testing.T) {
+	requests := newFlakyPriceServer(t, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+
+	_, err := getTokenPriceUSD(context.Background(), 1)
+	assert.ErrorContains(t, err, "503")
+	assert.Equal(t, int32(3), atomic.LoadInt32(requests))
+}
+
+// TestGetTokenPriceUSDNoRetry verifies client errors are not retried, nor requests past the context deadline
+func TestGetTokenPriceUSDNoRetry(t *testing.T) {
+	requests := newFlakyPriceServer(t, http.StatusBadRequest)
+	_, err := getTokenPriceUSD(context.Background(), 1)
+	assert.ErrorContains(t, err, "400")
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+
+	requests = newFlakyPriceServer(t, http.StatusServiceUnavailable)
+	SetPriceRequestRetries(2, time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = getTokenPriceUSD(ctx, 1)
+	assert.ErrorContains(t, err, "503")
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+}
+
+// TestGasTokenPriceIDs verifies the gas token price of every supported chain can be looked up
+func TestGasTokenPriceIDs(t *testing.T) {
+	for _, chainID := range chains.ChainList() {
+		assert.NotEmpty(t, gasTokenPriceID(chainID), "chain %d", chainID)
+	}
+	assert.Equal(t, "zetachain", gasTokenPriceID(7000))
+}
+
+// TestGasTokenPriceID verifies the configured price ID overrides the built-in id of the chain
+func TestGasTokenPriceID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("ids") {
+		case "zetachain":
+			_, _ = w.Write([]byte(`{"zetachain":{"usd":0.5}}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	originalURL := coinGeckoAPIURL
+	coinGeckoAPIURL = server.URL
+	t.Cleanup(func() { coinGeckoAPIURL = originalURL })
+	ClearGlobalCache()
+	t.Cleanup(ClearGlobalCache)
+
+	client := &Client{ChainID: 7000, logger: &logger.EmptyLogger{}}
+	price, err := client.gasTokenPriceUSD(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, price)
+
+	t.Setenv("CHAIN_7000_PRICE_ID", "zeta")
+	client.PriceID = config.GetEnvChainPriceID(7000)
+	_, err = client.gasTokenPriceUSD(context.Background())
+	assert.ErrorContains(t, err, "token data not found")
+}
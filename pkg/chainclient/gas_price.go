@@ -0,0 +1,76 @@
+package chainclient
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/speedrun-hq/speedrunner/pkg/config"
+)
+
+// feeHistoryBlockCount is the number of recent blocks sampled by the fee history gas source
+const feeHistoryBlockCount = 5
+
+// fetchGasPrice returns the gas price of the network from the configured gas source, before the multiplier,
+// raised to MinGasPrice if lower
+func (c *Client) fetchGasPrice(ctx context.Context) (*big.Int, error) {
+	gasPrice, err := c.fetchSourceGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// A zero or near-zero price is a misbehaving node rather than the market price, transactions would be rejected
+	if c.MinGasPrice != nil && gasPrice.Cmp(c.MinGasPrice) < 0 {
+		c.logger.NoticeWithChain(c.ChainID, "Gas price %s from the gas source is below the floor, using min gas price %s",
+			gasPrice.String(), c.MinGasPrice.String())
+		return new(big.Int).Set(c.MinGasPrice), nil
+	}
+	return gasPrice, nil
+}
+
+// fetchSourceGasPrice returns the gas price from the configured gas source
+func (c *Client) fetchSourceGasPrice(ctx context.Context) (*big.Int, error) {
+	switch c.GasSource {
+	case config.GasSourceFeeHistory:
+		return c.feeHistoryGasPrice(ctx)
+	case config.GasSourceSuggested, "":
+		return rpcCall(ctx, c, "SuggestGasPrice", func() (*big.Int, error) {
+			return c.ReadClient().SuggestGasPrice(ctx)
+		})
+	default:
+		return nil, fmt.Errorf("unsupported gas source: %s", c.GasSource)
+	}
+}
+
+// feeHistoryGasPrice computes the gas price from eth_feeHistory as the base fee of the pending block
+// plus the average priority fee paid at the configured percentile over the recent blocks
+func (c *Client) feeHistoryGasPrice(ctx context.Context) (*big.Int, error) {
+	history, err := rpcCall(ctx, c, "FeeHistory", func() (*ethereum.FeeHistory, error) {
+		return c.ReadClient().FeeHistory(ctx, feeHistoryBlockCount, nil, []float64{c.GasPercentile})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee history: %v", err)
+	}
+	if len(history.BaseFee) == 0 {
+		return nil, fmt.Errorf("empty fee history")
+	}
+
+	// the last base fee is the one of the next block
+	baseFee := history.BaseFee[len(history.BaseFee)-1]
+
+	tipSum := big.NewInt(0)
+	tipCount := int64(0)
+	for _, rewards := range history.Reward {
+		if len(rewards) > 0 && rewards[0] != nil {
+			tipSum.Add(tipSum, rewards[0])
+			tipCount++
+		}
+	}
+
+	gasPrice := new(big.Int).Set(baseFee)
+	if tipCount > 0 {
+		gasPrice.Add(gasPrice, new(big.Int).Div(tipSum, big.NewInt(tipCount)))
+	}
+	return gasPrice, nil
+}
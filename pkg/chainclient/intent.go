@@ -0,0 +1,217 @@
+package chainclient
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/speedrun-hq/speedrunner/pkg/contracts"
+)
+
+// multicallMethod is the method of the Intent contract executing several calls in one transaction (OpenZeppelin Multicall)
+const multicallMethod = "multicall"
+
+// fulfillValues returns the values of the fulfill method inputs keyed by input name
+func fulfillValues(intentID [32]byte, asset common.Address, amount *big.Int, receiver common.Address) map[string]interface{} {
+	return map[string]interface{}{
+		"intentId": intentID,
+		"asset":    asset,
+		"amount":   amount,
+		"receiver": receiver,
+	}
+}
+
+// SetIntentABI binds the Intent contract with a custom ABI and selects the fulfill method by name
+// It returns an error if the method doesn't exist or has inputs that can't be provided
+func (c *Client) SetIntentABI(parsed abi.ABI, fulfillMethod string) error {
+	if _, err := contracts.FulfillArgs(parsed, fulfillMethod, fulfillValues([32]byte{}, common.Address{}, nil, common.Address{})); err != nil {
+		return fmt.Errorf("invalid fulfill method: %v", err)
+	}
+
+	contract, intentContracts, err := c.bindIntentContracts(parsed, c.ContractBackend())
+	if err != nil {
+		return fmt.Errorf("failed to initialize contract: %v", err)
+	}
+
+	c.IntentContract = contract
+	c.intentContracts = intentContracts
+	c.intentABI = parsed
+	c.fulfillMethod = fulfillMethod
+	return nil
+}
+
+// bindIntentContracts binds the default Intent contract and the additional ones with the ABI,
+// it returns the default binding and the bindings of all contracts by address
+func (c *Client) bindIntentContracts(
+	parsed abi.ABI,
+	backend bind.ContractBackend,
+) (*contracts.Intent, map[common.Address]*contracts.Intent, error) {
+	bindings := make(map[common.Address]*contracts.Intent)
+	for _, address := range c.intentAddresses() {
+		contract, err := contracts.NewIntentWithABI(address, parsed, backend)
+		if err != nil {
+			return nil, nil, fmt.Errorf("contract %s: %v", address.Hex(), err)
+		}
+		bindings[address] = contract
+	}
+	return bindings[common.HexToAddress(c.IntentAddress)], bindings, nil
+}
+
+// intentAddresses returns the addresses of the Intent contracts of the chain, the default one first
+func (c *Client) intentAddresses() []common.Address {
+	addresses := []common.Address{common.HexToAddress(c.IntentAddress)}
+	for _, address := range c.IntentAddresses {
+		addresses = append(addresses, common.HexToAddress(address))
+	}
+	return addresses
+}
+
+// HasIntentContract returns true if intents of the Intent contract at address can be fulfilled on the chain,
+// an empty address is the default contract
+func (c *Client) HasIntentContract(address string) bool {
+	_, err := c.ResolveIntentAddress(address)
+	return err == nil
+}
+
+// ResolveIntentAddress returns the address of the Intent contract an intent is fulfilled through,
+// its contract if set, otherwise the default contract of the chain
+func (c *Client) ResolveIntentAddress(contract string) (common.Address, error) {
+	if contract == "" {
+		return common.HexToAddress(c.IntentAddress), nil
+	}
+	if !common.IsHexAddress(contract) {
+		return common.Address{}, fmt.Errorf("invalid intent contract address: %s", contract)
+	}
+	address := common.HexToAddress(contract)
+	for _, known := range c.intentAddresses() {
+		if known == address {
+			return address, nil
+		}
+	}
+	return common.Address{}, fmt.Errorf("intent contract %s not configured on chain %d", address.Hex(), c.ChainID)
+}
+
+// Fulfill sends the fulfill transaction of an intent to the Intent contract at intentAddress,
+// arguments are ordered according to the configured ABI
+// For native token intents, the amount must be set as the value of opts
+func (c *Client) Fulfill(
+	opts *bind.TransactOpts,
+	intentAddress common.Address,
+	intentID [32]byte,
+	asset common.Address,
+	amount *big.Int,
+	receiver common.Address,
+) (*types.Transaction, error) {
+	// the contract would revert when receiving a value on a non-payable method
+	if opts.Value != nil && opts.Value.Sign() > 0 && !c.intentABI.Methods[c.fulfillMethod].IsPayable() {
+		return nil, fmt.Errorf("method %s is not payable, cannot send native token value", c.fulfillMethod)
+	}
+
+	args, err := contracts.FulfillArgs(c.intentABI, c.fulfillMethod, fulfillValues(intentID, asset, amount, receiver))
+	if err != nil {
+		return nil, err
+	}
+
+	contract, err := c.intentContract(intentAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = c.withDynamicFee(opts)
+	if c.UseAccessList {
+		input, err := c.intentABI.Pack(c.fulfillMethod, args...)
+		if err != nil {
+			return nil, err
+		}
+		opts = c.withAccessList(opts, intentAddress, input)
+	}
+	opts = c.withFeeCap(c.withMinTip(opts))
+	return contract.Transact(opts, c.fulfillMethod, args...)
+}
+
+// SupportsMulticall returns true if the Intent ABI has a multicall(bytes[]) method,
+// allowing the fulfillment of several intents in one transaction with FulfillBatch
+func (c *Client) SupportsMulticall() bool {
+	method, ok := c.intentABI.Methods[multicallMethod]
+	return ok && len(method.Inputs) == 1 && method.Inputs[0].Type.String() == "bytes[]"
+}
+
+// FulfillBatch sends one multicall transaction fulfilling the intents of intentIDs with the amounts of the same index,
+// all sent in asset to receiver through the Intent contract at intentAddress
+// Native token intents can't be batched as the value of the transaction would be shared by the calls
+func (c *Client) FulfillBatch(
+	opts *bind.TransactOpts,
+	intentAddress common.Address,
+	intentIDs [][32]byte,
+	asset common.Address,
+	amounts []*big.Int,
+	receiver common.Address,
+) (*types.Transaction, error) {
+	if !c.SupportsMulticall() {
+		return nil, fmt.Errorf("intent ABI has no %s(bytes[]) method", multicallMethod)
+	}
+	if opts.Value != nil && opts.Value.Sign() > 0 {
+		return nil, fmt.Errorf("cannot batch native token intents")
+	}
+
+	calls, err := c.batchCalls(intentIDs, asset, amounts, receiver)
+	if err != nil {
+		return nil, err
+	}
+
+	contract, err := c.intentContract(intentAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = c.withDynamicFee(opts)
+	if c.UseAccessList {
+		input, err := c.intentABI.Pack(multicallMethod, calls)
+		if err != nil {
+			return nil, err
+		}
+		opts = c.withAccessList(opts, intentAddress, input)
+	}
+	opts = c.withFeeCap(c.withMinTip(opts))
+	return contract.Transact(opts, multicallMethod, calls)
+}
+
+// batchCalls returns the input of the fulfill method call of each intent of a batch
+func (c *Client) batchCalls(
+	intentIDs [][32]byte,
+	asset common.Address,
+	amounts []*big.Int,
+	receiver common.Address,
+) ([][]byte, error) {
+	if len(intentIDs) != len(amounts) {
+		return nil, fmt.Errorf("%d intents for %d amounts", len(intentIDs), len(amounts))
+	}
+
+	calls := make([][]byte, 0, len(intentIDs))
+	for i, intentID := range intentIDs {
+		args, err := contracts.FulfillArgs(c.intentABI, c.fulfillMethod, fulfillValues(intentID, asset, amounts[i], receiver))
+		if err != nil {
+			return nil, err
+		}
+		input, err := c.intentABI.Pack(c.fulfillMethod, args...)
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, input)
+	}
+	return calls, nil
+}
+
+// intentContract returns the binding of the Intent contract at intentAddress
+func (c *Client) intentContract(intentAddress common.Address) (*contracts.Intent, error) {
+	c.mu.RLock()
+	contract, ok := c.intentContracts[intentAddress]
+	c.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no binding for intent contract %s on chain %d", intentAddress.Hex(), c.ChainID)
+	}
+	return contract, nil
+}
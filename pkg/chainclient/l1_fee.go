@@ -0,0 +1,159 @@
+package chainclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/speedrun-hq/speedrunner/pkg/registry"
+)
+
+var (
+	// arbGasInfoAddress is the ArbGasInfo precompile on Arbitrum chains
+	arbGasInfoAddress = common.HexToAddress("0x000000000000000000000000000000000000006C")
+
+	// gasPriceOracleAddress is the GasPriceOracle predeploy on OP stack chains
+	gasPriceOracleAddress = common.HexToAddress("0x420000000000000000000000000000000000000F")
+)
+
+const l1FeeABI = `[
+	{
+		"inputs": [],
+		"name": "getPricesInWei",
+		"outputs": [
+			{"internalType": "uint256", "name": "", "type": "uint256"},
+			{"internalType": "uint256", "name": "", "type": "uint256"},
+			{"internalType": "uint256", "name": "", "type": "uint256"},
+			{"internalType": "uint256", "name": "", "type": "uint256"},
+			{"internalType": "uint256", "name": "", "type": "uint256"},
+			{"internalType": "uint256", "name": "", "type": "uint256"}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [{"internalType": "bytes", "name": "_data", "type": "bytes"}],
+		"name": "getL1Fee",
+		"outputs": [{"internalType": "uint256", "name": "", "type": "uint256"}],
+		"stateMutability": "view",
+		"type": "function"
+	}
+]`
+
+// fulfillTxSize approximates the size in bytes of a signed fulfill transaction:
+// 132 bytes of calldata (selector and 4 words) plus the transaction envelope and signature
+const fulfillTxSize = 250
+
+// IsRollup returns true if the chain pays an L1 data fee on top of the L2 execution fee
+func (c *Client) IsRollup() bool {
+	return c.rollup() != ""
+}
+
+// rollup returns the rollup type of the chain from the chain registry, it identifies how the L1 data fee is computed
+func (c *Client) rollup() string {
+	chain, exists := registry.Chains.Get(c.ChainID)
+	if !exists {
+		return ""
+	}
+	return chain.Rollup
+}
+
+// EstimateL1Fee returns the estimated L1 data fee in wei for a fulfill transaction
+// returns zero for chains that are not rollups
+func (c *Client) EstimateL1Fee(ctx context.Context) (*big.Int, error) {
+	if c.RPCClient() == nil {
+		return nil, fmt.Errorf("client not connected")
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(l1FeeABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse L1 fee ABI: %v", err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	switch c.rollup() {
+	case registry.RollupArbitrum:
+		// L1 fee = price per L1 calldata byte * transaction size
+		out, err := c.callL1FeeContract(timeoutCtx, parsedABI, arbGasInfoAddress, "getPricesInWei")
+		if err != nil {
+			return nil, err
+		}
+		perL1CalldataByte, ok := out[1].(*big.Int)
+		if !ok {
+			return nil, fmt.Errorf("unexpected getPricesInWei result")
+		}
+		return new(big.Int).Mul(perL1CalldataByte, big.NewInt(fulfillTxSize)), nil
+	case registry.RollupOPStack:
+		// use non-zero bytes for a conservative estimate of the compressed size
+		sampleTx := bytes.Repeat([]byte{0xff}, fulfillTxSize)
+		out, err := c.callL1FeeContract(timeoutCtx, parsedABI, gasPriceOracleAddress, "getL1Fee", sampleTx)
+		if err != nil {
+			return nil, err
+		}
+		l1Fee, ok := out[0].(*big.Int)
+		if !ok {
+			return nil, fmt.Errorf("unexpected getL1Fee result")
+		}
+		return l1Fee, nil
+	default:
+		return big.NewInt(0), nil
+	}
+}
+
+// callL1FeeContract calls a read-only method of a fee oracle contract and unpacks the result
+func (c *Client) callL1FeeContract(
+	ctx context.Context,
+	parsedABI abi.ABI,
+	address common.Address,
+	method string,
+	args ...interface{},
+) ([]interface{}, error) {
+	data, err := parsedABI.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s call: %v", method, err)
+	}
+
+	client := c.ReadClient()
+	result, err := rpcCall(ctx, c, "CallContract", func() ([]byte, error) {
+		return client.CallContract(ctx, ethereum.CallMsg{To: &address, Data: data}, nil)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s: %v", method, err)
+	}
+
+	out, err := parsedABI.Unpack(method, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack %s result: %v", method, err)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("empty %s result", method)
+	}
+	return out, nil
+}
+
+// nativeDecimals returns the decimals of the gas token of the chain from the chain registry
+func (c *Client) nativeDecimals() int {
+	chain, exists := registry.Chains.Get(c.ChainID)
+	if !exists {
+		return registry.DefaultNativeDecimals
+	}
+	return chain.NativeDecimals
+}
+
+// computeFeeUSD converts a fee in the smallest unit of the gas token with the decimals to USD
+func computeFeeUSD(fee *big.Int, decimals int, tokenPriceUSD float64) float64 {
+	if fee == nil {
+		return 0.0
+	}
+	feeFloat, _ := new(big.Float).SetInt(fee).Float64()
+	return (feeFloat / math.Pow10(decimals)) * tokenPriceUSD
+}
@@ -0,0 +1,79 @@
+package chainclient
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/speedrun-hq/speedrunner/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOracleService serves eth_call returning the same uint256 words for any call
+type fakeOracleService struct {
+	words []*big.Int
+}
+
+func (s *fakeOracleService) Call(_ map[string]interface{}, _ string) hexutil.Bytes {
+	var out []byte
+	for _, word := range s.words {
+		out = append(out, math.U256Bytes(new(big.Int).Set(word))...)
+	}
+	return out
+}
+
+// newFakeOracleClient returns an ethclient whose eth_call always returns the given words
+func newFakeOracleClient(t *testing.T, words ...*big.Int) *ethclient.Client {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", &fakeOracleService{words: words}))
+	t.Cleanup(server.Stop)
+	return ethclient.NewClient(rpc.DialInProc(server))
+}
+
+// TestEstimateL1Fee tests the L1 data fee estimate for each rollup type
+func TestEstimateL1Fee(t *testing.T) {
+	t.Run("OP stack uses the oracle fee", func(t *testing.T) {
+		client := &Client{ChainID: 8453, Client: newFakeOracleClient(t, big.NewInt(123456))}
+		assert.True(t, client.IsRollup())
+
+		fee, err := client.EstimateL1Fee(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(123456), fee)
+	})
+
+	t.Run("Arbitrum uses the calldata byte price", func(t *testing.T) {
+		prices := []*big.Int{
+			big.NewInt(1), big.NewInt(1000), big.NewInt(3), big.NewInt(4), big.NewInt(5), big.NewInt(6),
+		}
+		client := &Client{ChainID: 42161, Client: newFakeOracleClient(t, prices...)}
+		assert.True(t, client.IsRollup())
+
+		fee, err := client.EstimateL1Fee(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(1000*fulfillTxSize), fee)
+	})
+
+	t.Run("Non rollup has no L1 fee", func(t *testing.T) {
+		client := &Client{ChainID: 1, Client: newFakeOracleClient(t)}
+		assert.False(t, client.IsRollup())
+
+		fee, err := client.EstimateL1Fee(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 0, fee.Sign())
+	})
+}
+
+// TestComputeFeeUSD tests the conversion of a fee in the gas token to USD with the decimals of the gas token
+func TestComputeFeeUSD(t *testing.T) {
+	assert.InDelta(t, 3.0, computeFeeUSD(big.NewInt(1e15), 18, 3000.0), 0.0001)
+	assert.InDelta(t, 3.0, computeFeeUSD(big.NewInt(1000), 6, 3000.0), 0.0001)
+	assert.Equal(t, 0.0, computeFeeUSD(nil, 18, 3000.0))
+
+	assert.Equal(t, 18, (&Client{ChainID: 42161}).nativeDecimals())
+	assert.Equal(t, registry.DefaultNativeDecimals, (&Client{ChainID: 999999}).nativeDecimals())
+}
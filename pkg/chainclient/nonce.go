@@ -0,0 +1,183 @@
+package chainclient
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/speedrun-hq/speedrunner/pkg/config"
+)
+
+var (
+	nonceRetryMu      sync.RWMutex
+	nonceRetries      = config.DefaultNonceSyncRetries
+	nonceRetryBackoff = config.DefaultNonceSyncBackoff * time.Millisecond
+)
+
+// SetNonceSyncRetries sets the number of retries of failed account nonce requests and the delay before the first retry
+func SetNonceSyncRetries(retries int, backoff time.Duration) {
+	nonceRetryMu.Lock()
+	defer nonceRetryMu.Unlock()
+	nonceRetries = retries
+	nonceRetryBackoff = backoff
+}
+
+// nonceSyncRetryPolicy returns the number of retries of failed account nonce requests and the initial backoff
+func nonceSyncRetryPolicy() (int, time.Duration) {
+	nonceRetryMu.RLock()
+	defer nonceRetryMu.RUnlock()
+	return nonceRetries, nonceRetryBackoff
+}
+
+// nonceRetryBackend retries the pending nonce requests made when sending transactions,
+// so that a transient RPC failure during the nonce acquisition doesn't fail the transaction
+type nonceRetryBackend struct {
+	bind.ContractBackend
+	client *Client
+}
+
+// PendingNonceAt returns the pending nonce of the account, retrying failed requests with an exponential backoff
+func (b *nonceRetryBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return retryNonceRequest(ctx, b.client, "PendingNonceAt", func() (uint64, error) {
+		return b.ContractBackend.PendingNonceAt(ctx, account)
+	})
+}
+
+// retryNonceRequest runs the nonce request, retrying failures with an exponential backoff until the retries are exhausted
+// or the context is done
+func retryNonceRequest(ctx context.Context, c *Client, method string, request func() (uint64, error)) (uint64, error) {
+	retries, backoff := nonceSyncRetryPolicy()
+
+	nonce, err := rpcCall(ctx, c, method, request)
+	for attempt := 1; err != nil && attempt <= retries; attempt++ {
+		c.logger.ErrorWithChain(c.ChainID, "Nonce request %s failed, retrying in %v (%d/%d): %v",
+			method, backoff, attempt, retries, err)
+
+		select {
+		case <-ctx.Done():
+			return 0, fmt.Errorf("%v, retries aborted: %v", err, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+
+		nonce, err = rpcCall(ctx, c, method, request)
+	}
+	return nonce, err
+}
+
+// releasedNonce is the nonce of a transaction that was not mined in time with the gas price it was sent with
+type releasedNonce struct {
+	nonce    uint64
+	gasPrice *big.Int
+}
+
+// ReleaseNonce marks the nonce of a transaction that was not mined in time as available with the gas price it was sent
+// with, its fee cap for dynamic fee transactions, the next transaction sent on the chain reuses it to replace the stuck
+// transaction and must pay more than this gas price to be accepted
+func (c *Client) ReleaseNonce(nonce uint64, gasPrice *big.Int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, released := range c.releasedNonces {
+		if released.nonce == nonce {
+			if gasPrice != nil && (released.gasPrice == nil || gasPrice.Cmp(released.gasPrice) > 0) {
+				c.releasedNonces[i].gasPrice = gasPrice
+			}
+			return
+		}
+	}
+	c.releasedNonces = append(c.releasedNonces, releasedNonce{nonce: nonce, gasPrice: gasPrice})
+	sort.Slice(c.releasedNonces, func(i, j int) bool {
+		return c.releasedNonces[i].nonce < c.releasedNonces[j].nonce
+	})
+}
+
+// TakeReleasedNonce returns the lowest released nonce with the gas price of the stuck transaction,
+// and removes it from the released set
+func (c *Client) TakeReleasedNonce() (uint64, *big.Int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.releasedNonces) == 0 {
+		return 0, nil, false
+	}
+	released := c.releasedNonces[0]
+	c.releasedNonces = c.releasedNonces[1:]
+	return released.nonce, released.gasPrice, true
+}
+
+// SyncNonces realigns the released nonces with the chain after a nonce error,
+// released nonces already used by mined transactions are dropped as they can't be replaced anymore
+func (c *Client) SyncNonces(ctx context.Context) error {
+	client, from, err := c.nonceAccount()
+	if err != nil {
+		return err
+	}
+
+	confirmed, err := retryNonceRequest(ctx, c, "NonceAt", func() (uint64, error) {
+		return client.NonceAt(ctx, from, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get account nonce: %v", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pending := c.releasedNonces[:0]
+	for _, released := range c.releasedNonces {
+		if released.nonce >= confirmed {
+			pending = append(pending, released)
+		}
+	}
+	if dropped := len(c.releasedNonces) - len(pending); dropped > 0 {
+		c.logger.InfoWithChain(c.ChainID, "Dropped %d released nonces already used on chain (account nonce: %d)", dropped, confirmed)
+	}
+	c.releasedNonces = pending
+	return nil
+}
+
+// PendingTxCount returns the number of transactions of the fulfiller sent but not mined yet on the chain
+func (c *Client) PendingTxCount(ctx context.Context) (uint64, error) {
+	client, from, err := c.nonceAccount()
+	if err != nil {
+		return 0, err
+	}
+
+	confirmed, err := rpcCall(ctx, c, "NonceAt", func() (uint64, error) {
+		return client.NonceAt(ctx, from, nil)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get account nonce: %v", err)
+	}
+	pending, err := rpcCall(ctx, c, "PendingNonceAt", func() (uint64, error) {
+		return client.PendingNonceAt(ctx, from)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get pending account nonce: %v", err)
+	}
+
+	if pending < confirmed {
+		return 0, nil
+	}
+	return pending - confirmed, nil
+}
+
+// nonceAccount returns the primary RPC client and the address of the transactor to request account nonces
+func (c *Client) nonceAccount() (*ethclient.Client, common.Address, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.Auth == nil {
+		return nil, common.Address{}, fmt.Errorf("no transactor configured")
+	}
+	if c.Client == nil {
+		return nil, common.Address{}, fmt.Errorf("client not connected")
+	}
+	return c.Client, c.Auth.From, nil
+}
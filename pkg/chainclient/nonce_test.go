@@ -0,0 +1,125 @@
+package chainclient
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/speedrun-hq/speedrunner/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReleasedNonces verifies released nonces are reused from the lowest with the highest gas price they were sent with
+func TestReleasedNonces(t *testing.T) {
+	c := &Client{}
+	c.ReleaseNonce(7, big.NewInt(100))
+	c.ReleaseNonce(3, big.NewInt(200))
+	c.ReleaseNonce(7, big.NewInt(150))
+	c.ReleaseNonce(7, big.NewInt(120))
+
+	nonce, gasPrice, ok := c.TakeReleasedNonce()
+	require.True(t, ok)
+	assert.Equal(t, uint64(3), nonce)
+	assert.Equal(t, big.NewInt(200), gasPrice)
+
+	nonce, gasPrice, ok = c.TakeReleasedNonce()
+	require.True(t, ok)
+	assert.Equal(t, uint64(7), nonce)
+	assert.Equal(t, big.NewInt(150), gasPrice)
+
+	_, _, ok = c.TakeReleasedNonce()
+	assert.False(t, ok)
+}
+
+// TestSyncNonces verifies released nonces below the account nonce are dropped
+func TestSyncNonces(t *testing.T) {
+	c := &Client{
+		Client: newFakeEthClient(t),
+		Auth:   &bind.TransactOpts{},
+		logger: &logger.EmptyLogger{},
+	}
+	c.ReleaseNonce(fakeAccountNonce-2, nil)
+	c.ReleaseNonce(fakeAccountNonce, nil)
+	c.ReleaseNonce(fakeAccountNonce+1, nil)
+
+	require.NoError(t, c.SyncNonces(context.Background()))
+
+	nonce, _, ok := c.TakeReleasedNonce()
+	require.True(t, ok)
+	assert.Equal(t, uint64(fakeAccountNonce), nonce)
+
+	nonce, _, ok = c.TakeReleasedNonce()
+	require.True(t, ok)
+	assert.Equal(t, uint64(fakeAccountNonce+1), nonce)
+}
+
+// TestPendingTxCount verifies the pending transactions are the difference between the pending and confirmed nonces
+func TestPendingTxCount(t *testing.T) {
+	c := &Client{
+		Client: newFakeEthClient(t),
+		Auth:   &bind.TransactOpts{},
+	}
+
+	pending, err := c.PendingTxCount(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(fakePendingTxs), pending)
+}
+
+// flakyNonceBackend fails the first pending nonce requests before serving the nonce
+type flakyNonceBackend struct {
+	bind.ContractBackend
+	failures int
+	calls    int
+}
+
+func (b *flakyNonceBackend) PendingNonceAt(_ context.Context, _ common.Address) (uint64, error) {
+	b.calls++
+	if b.calls <= b.failures {
+		return 0, errors.New("connection reset by peer")
+	}
+	return fakeAccountNonce, nil
+}
+
+// TestNonceRetryBackend verifies failed pending nonce requests are retried up to the configured retries
+func TestNonceRetryBackend(t *testing.T) {
+	retries, backoff := nonceSyncRetryPolicy()
+	SetNonceSyncRetries(2, time.Millisecond)
+	t.Cleanup(func() { SetNonceSyncRetries(retries, backoff) })
+
+	c := &Client{ChainID: 1, logger: &logger.EmptyLogger{}}
+
+	t.Run("recovers", func(t *testing.T) {
+		flaky := &flakyNonceBackend{failures: 2}
+		backend := &nonceRetryBackend{ContractBackend: flaky, client: c}
+
+		nonce, err := backend.PendingNonceAt(context.Background(), common.Address{})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(fakeAccountNonce), nonce)
+		assert.Equal(t, 3, flaky.calls)
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		flaky := &flakyNonceBackend{failures: 3}
+		backend := &nonceRetryBackend{ContractBackend: flaky, client: c}
+
+		_, err := backend.PendingNonceAt(context.Background(), common.Address{})
+		require.Error(t, err)
+		assert.Equal(t, 3, flaky.calls)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		flaky := &flakyNonceBackend{failures: 3}
+		backend := &nonceRetryBackend{ContractBackend: flaky, client: c}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := backend.PendingNonceAt(ctx, common.Address{})
+		require.Error(t, err)
+		assert.Equal(t, 1, flaky.calls)
+	})
+}
@@ -0,0 +1,146 @@
+package chainclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// healthCheckTimeout is the maximum duration of an RPC health check or reconnection
+const healthCheckTimeout = 10 * time.Second
+
+// connectionMonitor tracks consecutive failed RPC health checks and spaces reconnection attempts
+type connectionMonitor struct {
+	failures    int
+	backoff     time.Duration
+	nextAttempt time.Time
+}
+
+// Reconnect dials the RPC endpoints again and rebuilds the authenticator and the contract binding,
+// the previous connections are closed once replaced
+func (c *Client) Reconnect(ctx context.Context) error {
+	client, err := c.dialEndpoint(ctx, c.RPCURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to client: %v", err)
+	}
+
+	var submitClient, readClient *ethclient.Client
+	closeClients := func() {
+		for _, dialed := range []*ethclient.Client{client, submitClient, readClient} {
+			if dialed != nil {
+				dialed.Close()
+			}
+		}
+	}
+
+	// dialing HTTP endpoints doesn't open a connection, ensure the node responds
+	_, err = rpcCall(ctx, c, "BlockNumber", func() (uint64, error) {
+		return client.BlockNumber(ctx)
+	})
+	if err != nil {
+		closeClients()
+		return fmt.Errorf("failed to reach client: %v", err)
+	}
+
+	if c.SubmitRPCURL != "" {
+		submitClient, err = c.dialEndpoint(ctx, c.SubmitRPCURL)
+		if err != nil {
+			closeClients()
+			return fmt.Errorf("failed to connect to submission endpoint: %v", err)
+		}
+	}
+	if c.ReadRPCURL != "" {
+		readClient, err = c.dialEndpoint(ctx, c.ReadRPCURL)
+		if err != nil {
+			closeClients()
+			return fmt.Errorf("failed to connect to read endpoint: %v", err)
+		}
+	}
+
+	// the signer outlives the health check context the reconnection runs with
+	var auth *bind.TransactOpts
+	if c.txSigner != nil {
+		signerCtx := c.Ctx
+		if signerCtx == nil {
+			signerCtx = context.Background()
+		}
+		auth, err = createAuthenticator(ctx, signerCtx, client, c.txSigner)
+		if err != nil {
+			closeClients()
+			return fmt.Errorf("failed to create authenticator: %v", err)
+		}
+	}
+
+	contract, intentContracts, err := c.bindIntentContracts(c.intentABI, c.contractBackend(client, readClient, submitClient))
+	if err != nil {
+		closeClients()
+		return fmt.Errorf("failed to initialize contract: %v", err)
+	}
+
+	c.mu.Lock()
+	previous := []*ethclient.Client{c.Client, c.submitClient, c.readClient}
+	c.Client = client
+	c.submitClient = submitClient
+	c.readClient = readClient
+	if auth != nil {
+		c.Auth = auth
+	}
+	c.IntentContract = contract
+	c.intentContracts = intentContracts
+	c.mu.Unlock()
+
+	for _, closed := range previous {
+		if closed != nil {
+			closed.Close()
+		}
+	}
+	return nil
+}
+
+// checkConnection checks the RPC connection with a block number request, after ReconnectFailures
+// consecutive failures the client reconnects, failed attempts are retried with an exponential backoff
+func (r *FeeUpdateRoutine) checkConnection() {
+	ctx, cancel := context.WithTimeout(r.ctx, healthCheckTimeout)
+	defer cancel()
+
+	_, err := r.client.GetLatestBlockNumber(ctx)
+	if err == nil {
+		r.monitor = connectionMonitor{}
+		r.client.setDisconnected(false)
+		return
+	}
+	r.monitor.failures++
+	r.logger.ErrorWithChain(r.client.ChainID, "RPC health check failed (%d/%d): %v",
+		r.monitor.failures, r.client.ReconnectFailures, err)
+
+	if r.monitor.failures < r.client.ReconnectFailures {
+		return
+	}
+	r.client.setDisconnected(true)
+	if time.Now().Before(r.monitor.nextAttempt) {
+		return
+	}
+
+	r.logger.NoticeWithChain(r.client.ChainID, "Reconnecting to RPC %s", r.client.RPCURL)
+	if err := r.client.Reconnect(ctx); err != nil {
+		// double the delay before the next attempt up to the configured maximum
+		if r.monitor.backoff == 0 {
+			r.monitor.backoff = r.interval
+		} else {
+			r.monitor.backoff *= 2
+		}
+		if r.client.ReconnectMaxBackoff > 0 && r.monitor.backoff > r.client.ReconnectMaxBackoff {
+			r.monitor.backoff = r.client.ReconnectMaxBackoff
+		}
+		r.monitor.nextAttempt = time.Now().Add(r.monitor.backoff)
+		r.logger.ErrorWithChain(r.client.ChainID, "Failed to reconnect, next attempt in %v: %v", r.monitor.backoff, err)
+		return
+	}
+
+	r.logger.NoticeWithChain(r.client.ChainID, "Reconnected to RPC %s", r.client.RPCURL)
+	r.monitor = connectionMonitor{}
+	r.client.setDisconnected(false)
+}
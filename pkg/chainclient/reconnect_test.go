@@ -0,0 +1,160 @@
+package chainclient
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/speedrun-hq/speedrunner/pkg/contracts"
+	"github.com/speedrun-hq/speedrunner/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCheckConnectionReconnects verifies the client reconnects after consecutive failed health checks
+func TestCheckConnectionReconnects(t *testing.T) {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", &fakeEthService{}))
+	t.Cleanup(server.Stop)
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	// start with a dropped connection
+	dropped, err := ethclient.Dial("http://127.0.0.1:1")
+	require.NoError(t, err)
+
+	intentABI, err := contracts.LoadIntentABI("")
+	require.NoError(t, err)
+
+	client := &Client{
+		Ctx:                 context.Background(),
+		ChainID:             1,
+		RPCURL:              httpServer.URL,
+		Client:              dropped,
+		ReconnectFailures:   2,
+		ReconnectMaxBackoff: time.Minute,
+		intentABI:           intentABI,
+		logger:              &logger.EmptyLogger{},
+	}
+	routine := NewFeeUpdateRoutine(client, time.Second)
+
+	// below the failure threshold, the connection is kept
+	routine.checkConnection()
+	assert.Equal(t, 1, routine.monitor.failures)
+	assert.Equal(t, dropped, client.Client)
+	assert.True(t, client.IsConnected())
+
+	// the threshold is reached, the client reconnects
+	routine.checkConnection()
+	assert.NotEqual(t, dropped, client.Client)
+	assert.Equal(t, 0, routine.monitor.failures)
+	assert.NotNil(t, client.IntentContract)
+	assert.True(t, client.IsConnected())
+
+	blockNumber, err := client.GetLatestBlockNumber(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100), blockNumber)
+}
+
+// TestCheckConnectionBackoff verifies failed reconnections are spaced with an exponential backoff
+func TestCheckConnectionBackoff(t *testing.T) {
+	dropped, err := ethclient.Dial("http://127.0.0.1:1")
+	require.NoError(t, err)
+
+	client := &Client{
+		Ctx:                 context.Background(),
+		ChainID:             1,
+		RPCURL:              "http://127.0.0.1:1",
+		Client:              dropped,
+		ReconnectFailures:   1,
+		ReconnectMaxBackoff: 3 * time.Second,
+		logger:              &logger.EmptyLogger{},
+	}
+	routine := NewFeeUpdateRoutine(client, time.Second)
+
+	// the endpoint is unreachable, the next attempt is delayed by the routine interval
+	routine.checkConnection()
+	assert.Equal(t, time.Second, routine.monitor.backoff)
+	assert.Equal(t, dropped, client.Client)
+	assert.False(t, client.IsConnected())
+
+	// no attempt before the delay elapsed
+	nextAttempt := routine.monitor.nextAttempt
+	routine.checkConnection()
+	assert.Equal(t, nextAttempt, routine.monitor.nextAttempt)
+
+	// the delay doubles up to the maximum
+	routine.monitor.nextAttempt = time.Time{}
+	routine.checkConnection()
+	assert.Equal(t, 2*time.Second, routine.monitor.backoff)
+
+	routine.monitor.nextAttempt = time.Time{}
+	routine.checkConnection()
+	assert.Equal(t, 3*time.Second, routine.monitor.backoff)
+}
+
+// ctxSigner fails to sign once the context it was created with is done
+type ctxSigner struct{}
+
+func (ctxSigner) Address() common.Address {
+	return common.HexToAddress("0x1111111111111111111111111111111111111111")
+}
+
+func (ctxSigner) SignTx(ctx context.Context, tx *types.Transaction, _ *big.Int) (*types.Transaction, error) {
+	return tx, ctx.Err()
+}
+
+// TestReconnectRedialsEndpoints verifies the submission and read endpoints are dialed again on reconnection,
+// and the authenticator keeps signing after the reconnection context is done
+func TestReconnectRedialsEndpoints(t *testing.T) {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", &fakeEthService{}))
+	t.Cleanup(server.Stop)
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	dropped, err := ethclient.Dial("http://127.0.0.1:1")
+	require.NoError(t, err)
+	droppedSubmit, err := ethclient.Dial("http://127.0.0.1:1")
+	require.NoError(t, err)
+	droppedRead, err := ethclient.Dial("http://127.0.0.1:1")
+	require.NoError(t, err)
+
+	intentABI, err := contracts.LoadIntentABI("")
+	require.NoError(t, err)
+
+	client := &Client{
+		Ctx:          context.Background(),
+		ChainID:      1,
+		RPCURL:       httpServer.URL,
+		SubmitRPCURL: httpServer.URL,
+		ReadRPCURL:   httpServer.URL,
+		Client:       dropped,
+		submitClient: droppedSubmit,
+		readClient:   droppedRead,
+		txSigner:     ctxSigner{},
+		intentABI:    intentABI,
+		logger:       &logger.EmptyLogger{},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	require.NoError(t, client.Reconnect(ctx))
+	cancel()
+
+	assert.NotEqual(t, dropped, client.RPCClient())
+	assert.NotNil(t, client.submitClient)
+	assert.NotEqual(t, droppedSubmit, client.submitClient)
+	assert.NotNil(t, client.readClient)
+	assert.NotEqual(t, droppedRead, client.ReadClient())
+
+	opts, err := client.TransactOpts()
+	require.NoError(t, err)
+	_, err = opts.Signer(opts.From, types.NewTx(&types.LegacyTx{}))
+	assert.NoError(t, err)
+}
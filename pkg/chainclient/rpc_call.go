@@ -0,0 +1,68 @@
+package chainclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/speedrun-hq/speedrunner/pkg/config"
+	"github.com/speedrun-hq/speedrunner/pkg/metrics"
+)
+
+// rpcCall runs an RPC call to the chain with a slot of its call limiter and records its latency labeled by method
+func rpcCall[T any](ctx context.Context, c *Client, method string, call func() (T, error)) (T, error) {
+	release, err := c.callLimiter.acquire(ctx)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	defer release()
+
+	start := time.Now()
+	result, err := call()
+	metrics.RPCLatency.WithLabelValues(strconv.Itoa(c.ChainID), method).Observe(time.Since(start).Seconds())
+	return result, err
+}
+
+// TransactionReceipt returns the receipt of the transaction from the primary RPC endpoint
+func (c *Client) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	client := c.RPCClient()
+	if client == nil {
+		return nil, fmt.Errorf("client not connected")
+	}
+	return rpcCall(ctx, c, "TransactionReceipt", func() (*types.Receipt, error) {
+		return client.TransactionReceipt(ctx, txHash)
+	})
+}
+
+// WaitMined waits for the transaction to be mined and returns its receipt, the receipt is requested every
+// ReceiptPollInterval until it is found or the context is done
+func (c *Client) WaitMined(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+	interval := c.ReceiptPollInterval
+	if interval <= 0 {
+		interval = config.DefaultReceiptPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		receipt, err := c.TransactionReceipt(ctx, tx.Hash())
+		if err == nil {
+			return receipt, nil
+		}
+		if !errors.Is(err, ethereum.NotFound) && ctx.Err() == nil {
+			c.logger.DebugWithChain(c.ChainID, "Failed to get receipt of transaction %s: %v", tx.Hash().Hex(), err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
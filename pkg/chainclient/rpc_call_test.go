@@ -0,0 +1,90 @@
+package chainclient
+
+import (
+	"context"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/speedrun-hq/speedrunner/pkg/logger"
+	"github.com/speedrun-hq/speedrunner/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rpcLatencyCount returns the number of latency samples recorded for the chain and method
+func rpcLatencyCount(t *testing.T, chainID, method string) uint64 {
+	m := &dto.Metric{}
+	require.NoError(t, metrics.RPCLatency.WithLabelValues(chainID, method).(prometheus.Metric).Write(m))
+	return m.GetHistogram().GetSampleCount()
+}
+
+// TestRPCLatencyRecorded verifies RPC calls record their latency labeled by chain and method
+func TestRPCLatencyRecorded(t *testing.T) {
+	c := &Client{
+		ChainID: 99911,
+		Client:  newFakeEthClient(t),
+		Auth:    &bind.TransactOpts{},
+		logger:  &logger.EmptyLogger{},
+	}
+
+	_, err := c.GetLatestBlockNumber(context.Background())
+	require.NoError(t, err)
+	_, err = c.PendingTxCount(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(1), rpcLatencyCount(t, "99911", "BlockNumber"))
+	assert.Equal(t, uint64(1), rpcLatencyCount(t, "99911", "NonceAt"))
+	assert.Equal(t, uint64(1), rpcLatencyCount(t, "99911", "PendingNonceAt"))
+	assert.Equal(t, uint64(0), rpcLatencyCount(t, "99911", "WaitMined"))
+}
+
+// fakeReceiptService serves the receipt of a transaction once it was requested pendingPolls times
+type fakeReceiptService struct {
+	pendingPolls int32
+	polls        int32
+}
+
+func (s *fakeReceiptService) GetTransactionReceipt(hash common.Hash) *types.Receipt {
+	if atomic.AddInt32(&s.polls, 1) <= atomic.LoadInt32(&s.pendingPolls) {
+		return nil
+	}
+	return &types.Receipt{TxHash: hash, Status: types.ReceiptStatusSuccessful, GasUsed: 21000, Logs: []*types.Log{}}
+}
+
+// TestWaitMined verifies the receipt is polled at the receipt poll interval until found or the context is done
+func TestWaitMined(t *testing.T) {
+	service := &fakeReceiptService{pendingPolls: 2}
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", service))
+	t.Cleanup(server.Stop)
+
+	c := &Client{
+		ChainID:             99912,
+		Client:              ethclient.NewClient(rpc.DialInProc(server)),
+		ReceiptPollInterval: 10 * time.Millisecond,
+		logger:              &logger.EmptyLogger{},
+	}
+	tx := types.NewTx(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000})
+
+	receipt, err := c.WaitMined(context.Background(), tx)
+	require.NoError(t, err)
+	assert.Equal(t, tx.Hash(), receipt.TxHash)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&service.polls))
+	assert.Equal(t, uint64(3), rpcLatencyCount(t, "99912", "TransactionReceipt"))
+
+	// the wait stops at the deadline of the context
+	atomic.StoreInt32(&service.pendingPolls, 1000)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = c.WaitMined(ctx, tx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,278 @@
+package chainclient
+
+import (
+	"context"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	rpcLimitMu sync.RWMutex
+
+	// rpcSemaphore limits the concurrent RPC calls across all chains, nil for no limit
+	rpcSemaphore chan struct{}
+)
+
+// SetMaxConcurrentRPC sets the maximum number of concurrent RPC calls across all chains, 0 for no limit
+func SetMaxConcurrentRPC(maxConcurrent int) {
+	rpcLimitMu.Lock()
+	defer rpcLimitMu.Unlock()
+	if maxConcurrent <= 0 {
+		rpcSemaphore = nil
+		return
+	}
+	rpcSemaphore = make(chan struct{}, maxConcurrent)
+}
+
+// globalRPCSemaphore returns the semaphore limiting RPC calls across all chains
+func globalRPCSemaphore() chan struct{} {
+	rpcLimitMu.RLock()
+	defer rpcLimitMu.RUnlock()
+	return rpcSemaphore
+}
+
+// acquire takes a slot of the semaphore, a nil semaphore never blocks
+func acquire(ctx context.Context, sem chan struct{}) error {
+	if sem == nil {
+		return nil
+	}
+	select {
+	case sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees a slot of the semaphore
+func release(sem chan struct{}) {
+	if sem != nil {
+		<-sem
+	}
+}
+
+// newChainSemaphore returns the semaphore limiting the concurrent RPC calls to a chain to maxConcurrent,
+// shared by all the endpoints of the chain, nil for no limit
+func newChainSemaphore(maxConcurrent int) chan struct{} {
+	if maxConcurrent <= 0 {
+		return nil
+	}
+	return make(chan struct{}, maxConcurrent)
+}
+
+// rpcLimiter limits the concurrent calls to a chain with the global and the chain semaphores, for the chains with
+// an endpoint that isn't HTTP, whose requests can't be limited by the transport
+type rpcLimiter struct {
+	chainSem chan struct{}
+}
+
+// newCallLimiter returns the limiter of the calls to the chain if one of its RPC endpoints isn't HTTP, nil otherwise,
+// the calls are limited by the global semaphore and by the chain semaphore if not nil
+func newCallLimiter(chainSem chan struct{}, rpcURLs ...string) *rpcLimiter {
+	for _, rpcURL := range rpcURLs {
+		if rpcURL != "" && !isHTTP(rpcURL) {
+			return &rpcLimiter{chainSem: chainSem}
+		}
+	}
+	return nil
+}
+
+// acquire takes a slot of the global and the chain semaphores and returns the function freeing them,
+// a nil limiter never blocks
+func (l *rpcLimiter) acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	globalSem := globalRPCSemaphore()
+	if err := acquire(ctx, globalSem); err != nil {
+		return nil, err
+	}
+	if err := acquire(ctx, l.chainSem); err != nil {
+		release(globalSem)
+		return nil, err
+	}
+	return func() {
+		release(l.chainSem)
+		release(globalSem)
+	}, nil
+}
+
+// limitedTransport limits the concurrent HTTP requests to the RPC with the global and the chain semaphores,
+// slots are held until the response body is closed
+type limitedTransport struct {
+	base     http.RoundTripper
+	chainSem chan struct{}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	globalSem := globalRPCSemaphore()
+	if err := acquire(req.Context(), globalSem); err != nil {
+		return nil, err
+	}
+	if err := acquire(req.Context(), t.chainSem); err != nil {
+		release(globalSem)
+		return nil, err
+	}
+	releaseAll := func() {
+		release(t.chainSem)
+		release(globalSem)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		releaseAll()
+		return nil, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: releaseAll}
+	return resp, nil
+}
+
+// releasingBody frees the semaphore slots of a request once its response body is closed
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+// Close implements io.Closer
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// isHTTP returns true if the RPC URL is an HTTP endpoint
+func isHTTP(rpcURL string) bool {
+	return strings.HasPrefix(rpcURL, "http://") || strings.HasPrefix(rpcURL, "https://")
+}
+
+// dialRPC connects to the RPC, calls to HTTP endpoints are limited by the global semaphore
+// and by the chain semaphore if not nil
+func dialRPC(ctx context.Context, rpcURL string, chainSem chan struct{}) (*ethclient.Client, error) {
+	if !isHTTP(rpcURL) {
+		return ethclient.DialContext(ctx, rpcURL)
+	}
+
+	httpClient := &http.Client{
+		Transport: &limitedTransport{base: http.DefaultTransport, chainSem: chainSem},
+	}
+
+	client, err := rpc.DialOptions(ctx, rpcURL, rpc.WithHTTPClient(httpClient))
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(client), nil
+}
+
+// dialEndpoint connects to an RPC endpoint of the chain, the requests are limited by the transport of HTTP endpoints
+// unless the calls to the chain are limited by its call limiter
+func (c *Client) dialEndpoint(ctx context.Context, rpcURL string) (*ethclient.Client, error) {
+	if c.callLimiter != nil {
+		return ethclient.DialContext(ctx, rpcURL)
+	}
+	return dialRPC(ctx, rpcURL, c.chainSem)
+}
+
+// limitedBackend limits the concurrent contract calls and transactions with the call limiter of the chain,
+// the pending nonce requests are limited by the nonce retries running them through rpcCall
+type limitedBackend struct {
+	bind.ContractBackend
+	limiter *rpcLimiter
+}
+
+// limitCall runs the call of the backend with a slot of the limiter
+func limitCall[T any](ctx context.Context, limiter *rpcLimiter, call func() (T, error)) (T, error) {
+	release, err := limiter.acquire(ctx)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	defer release()
+	return call()
+}
+
+// CodeAt implements bind.ContractCaller
+func (b *limitedBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return limitCall(ctx, b.limiter, func() ([]byte, error) {
+		return b.ContractBackend.CodeAt(ctx, contract, blockNumber)
+	})
+}
+
+// CallContract implements bind.ContractCaller
+func (b *limitedBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return limitCall(ctx, b.limiter, func() ([]byte, error) {
+		return b.ContractBackend.CallContract(ctx, call, blockNumber)
+	})
+}
+
+// HeaderByNumber implements bind.ContractTransactor
+func (b *limitedBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return limitCall(ctx, b.limiter, func() (*types.Header, error) {
+		return b.ContractBackend.HeaderByNumber(ctx, number)
+	})
+}
+
+// PendingCodeAt implements bind.ContractTransactor
+func (b *limitedBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return limitCall(ctx, b.limiter, func() ([]byte, error) {
+		return b.ContractBackend.PendingCodeAt(ctx, account)
+	})
+}
+
+// SuggestGasPrice implements bind.ContractTransactor
+func (b *limitedBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return limitCall(ctx, b.limiter, func() (*big.Int, error) {
+		return b.ContractBackend.SuggestGasPrice(ctx)
+	})
+}
+
+// SuggestGasTipCap implements bind.ContractTransactor
+func (b *limitedBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return limitCall(ctx, b.limiter, func() (*big.Int, error) {
+		return b.ContractBackend.SuggestGasTipCap(ctx)
+	})
+}
+
+// EstimateGas implements bind.ContractTransactor
+func (b *limitedBackend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return limitCall(ctx, b.limiter, func() (uint64, error) {
+		return b.ContractBackend.EstimateGas(ctx, call)
+	})
+}
+
+// SendTransaction implements bind.ContractTransactor
+func (b *limitedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	_, err := limitCall(ctx, b.limiter, func() (struct{}, error) {
+		return struct{}{}, b.ContractBackend.SendTransaction(ctx, tx)
+	})
+	return err
+}
+
+// FilterLogs implements bind.ContractFilterer
+func (b *limitedBackend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return limitCall(ctx, b.limiter, func() ([]types.Log, error) {
+		return b.ContractBackend.FilterLogs(ctx, query)
+	})
+}
+
+// SubscribeFilterLogs implements bind.ContractFilterer, the slot is only held while subscribing
+func (b *limitedBackend) SubscribeFilterLogs(
+	ctx context.Context,
+	query ethereum.FilterQuery,
+	ch chan<- types.Log,
+) (ethereum.Subscription, error) {
+	return limitCall(ctx, b.limiter, func() (ethereum.Subscription, error) {
+		return b.ContractBackend.SubscribeFilterLogs(ctx, query, ch)
+	})
+}
@@ -0,0 +1,173 @@
+package chainclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// newSlowRPCServer returns an RPC server answering eth_blockNumber slowly and recording the peak of in-flight requests
+func newSlowRPCServer(t *testing.T) (*httptest.Server, *int32) {
+	handler, peak := newSlowRPCHandler()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server, peak
+}
+
+// newSlowRPCHandler returns a handler answering eth_blockNumber slowly and recording the peak of in-flight requests
+func newSlowRPCHandler() (http.Handler, *int32) {
+	var inFlight, peak int32
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if current <= p || atomic.CompareAndSwapInt32(&peak, p, current) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x64"}`))
+	}), &peak
+}
+
+// callConcurrently issues n concurrent block number calls through a client dialed with dialRPC
+func callConcurrently(t *testing.T, url string, maxConcurrent, n int) {
+	client, err := dialRPC(context.Background(), url, newChainSemaphore(maxConcurrent))
+	require.NoError(t, err)
+	defer client.Close()
+
+	callClientsConcurrently(t, []*ethclient.Client{client}, n)
+}
+
+// callClientsConcurrently issues n concurrent block number calls spread over the clients
+func callClientsConcurrently(t *testing.T, clients []*ethclient.Client, n int) {
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(client *ethclient.Client) {
+			defer wg.Done()
+			_, err := client.BlockNumber(context.Background())
+			require.NoError(t, err)
+		}(clients[i%len(clients)])
+	}
+	wg.Wait()
+}
+
+func TestDialRPCChainLimit(t *testing.T) {
+	SetMaxConcurrentRPC(0)
+
+	server, peak := newSlowRPCServer(t)
+	callConcurrently(t, server.URL, 2, 8)
+
+	require.LessOrEqual(t, atomic.LoadInt32(peak), int32(2))
+}
+
+func TestDialRPCChainLimitAcrossEndpoints(t *testing.T) {
+	SetMaxConcurrentRPC(0)
+
+	// the main, submission and read endpoints of the chain share its limit
+	handler, peak := newSlowRPCHandler()
+	c := &Client{MaxConcurrentRPC: 2}
+	c.chainSem = newChainSemaphore(c.MaxConcurrentRPC)
+	var clients []*ethclient.Client
+	for i := 0; i < 3; i++ {
+		server := httptest.NewServer(handler)
+		t.Cleanup(server.Close)
+		client, err := c.dialEndpoint(context.Background(), server.URL)
+		require.NoError(t, err)
+		defer client.Close()
+		clients = append(clients, client)
+	}
+	callClientsConcurrently(t, clients, 12)
+
+	require.LessOrEqual(t, atomic.LoadInt32(peak), int32(2))
+}
+
+func TestDialRPCGlobalLimit(t *testing.T) {
+	SetMaxConcurrentRPC(3)
+	defer SetMaxConcurrentRPC(0)
+
+	server, peak := newSlowRPCServer(t)
+	callConcurrently(t, server.URL, 0, 9)
+
+	require.LessOrEqual(t, atomic.LoadInt32(peak), int32(3))
+}
+
+func TestAcquireContextCanceled(t *testing.T) {
+	sem := make(chan struct{}, 1)
+	require.NoError(t, acquire(context.Background(), sem))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, acquire(ctx, sem), context.Canceled)
+
+	release(sem)
+	require.NoError(t, acquire(context.Background(), sem))
+}
+
+// slowBlockService answers eth_blockNumber slowly and records the peak of in-flight requests
+type slowBlockService struct {
+	inFlight, peak int32
+}
+
+func (s *slowBlockService) BlockNumber() hexutil.Uint64 {
+	current := atomic.AddInt32(&s.inFlight, 1)
+	defer atomic.AddInt32(&s.inFlight, -1)
+	for {
+		p := atomic.LoadInt32(&s.peak)
+		if current <= p || atomic.CompareAndSwapInt32(&s.peak, p, current) {
+			break
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	return 100
+}
+
+func TestCallLimiterWebsocket(t *testing.T) {
+	SetMaxConcurrentRPC(3)
+	defer SetMaxConcurrentRPC(0)
+
+	service := &slowBlockService{}
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", service))
+	t.Cleanup(server.Stop)
+	httpServer := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	t.Cleanup(httpServer.Close)
+	wsURL := "ws" + strings.TrimPrefix(httpServer.URL, "http")
+
+	require.Nil(t, newCallLimiter(newChainSemaphore(2), httpServer.URL, ""))
+
+	c := &Client{RPCURL: wsURL, MaxConcurrentRPC: 2}
+	c.chainSem = newChainSemaphore(c.MaxConcurrentRPC)
+	c.callLimiter = newCallLimiter(c.chainSem, c.RPCURL)
+	require.NotNil(t, c.callLimiter)
+	client, err := c.dialEndpoint(context.Background(), c.RPCURL)
+	require.NoError(t, err)
+	defer client.Close()
+	c.Client = client
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.GetLatestBlockNumber(context.Background())
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	require.LessOrEqual(t, atomic.LoadInt32(&service.peak), int32(2))
+}
@@ -0,0 +1,57 @@
+package chainclient
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// submissionBackend reads the chain state from one endpoint and sends transactions through another, such as
+// a private mempool or MEV protection RPC so that transactions are not visible in the public mempool,
+// or the primary RPC when reads are offloaded to a separate read endpoint
+type submissionBackend struct {
+	*ethclient.Client
+	submit *ethclient.Client
+}
+
+// SendTransaction sends the transaction through the submission endpoint
+func (b *submissionBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return b.submit.SendTransaction(ctx, tx)
+}
+
+// PendingNonceAt returns the pending nonce from the submission endpoint,
+// the read endpoint doesn't see the transactions pending in a private mempool
+func (b *submissionBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return b.submit.PendingNonceAt(ctx, account)
+}
+
+// contractBackend returns the backend of contract bindings for the RPC client, the state is read from the
+// read client and transactions are sent through the submit client if not nil, nonce requests are retried
+// and the calls are limited by the call limiter of the chain if any
+func (c *Client) contractBackend(client, readClient, submitClient *ethclient.Client) bind.ContractBackend {
+	read, submit := client, client
+	if readClient != nil {
+		read = readClient
+	}
+	if submitClient != nil {
+		submit = submitClient
+	}
+	var backend bind.ContractBackend = &nonceRetryBackend{ContractBackend: client, client: c}
+	if read != submit {
+		backend = &nonceRetryBackend{ContractBackend: &submissionBackend{Client: read, submit: submit}, client: c}
+	}
+	if c.callLimiter != nil {
+		backend = &limitedBackend{ContractBackend: backend, limiter: c.callLimiter}
+	}
+	return backend
+}
+
+// ContractBackend returns the backend to bind contracts sending transactions on the chain
+func (c *Client) ContractBackend() bind.ContractBackend {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.contractBackend(c.Client, c.readClient, c.submitClient)
+}
@@ -0,0 +1,97 @@
+package chainclient
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSubmitNonce is the pending account nonce served by fakeSubmitService
+const fakeSubmitNonce = 42
+
+// fakeSubmitService records the raw transactions sent to a submission endpoint
+type fakeSubmitService struct {
+	sent []hexutil.Bytes
+}
+
+func (s *fakeSubmitService) SendRawTransaction(raw hexutil.Bytes) common.Hash {
+	s.sent = append(s.sent, raw)
+	return common.Hash{}
+}
+
+func (s *fakeSubmitService) GetTransactionCount(_ common.Address, _ string) hexutil.Uint64 {
+	return fakeSubmitNonce
+}
+
+// TestSubmissionBackend verifies transactions and pending nonces go through the submission endpoint
+func TestSubmissionBackend(t *testing.T) {
+	submitService := &fakeSubmitService{}
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", submitService))
+	t.Cleanup(server.Stop)
+
+	c := &Client{
+		Client:       newFakeEthClient(t),
+		submitClient: ethclient.NewClient(rpc.DialInProc(server)),
+	}
+	backend := c.ContractBackend()
+
+	// state is read from the RPC
+	gasPrice, err := backend.SuggestGasPrice(context.Background())
+	require.NoError(t, err)
+	assert.Positive(t, gasPrice.Sign())
+
+	nonce, err := backend.PendingNonceAt(context.Background(), common.Address{})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(fakeSubmitNonce), nonce)
+
+	tx := types.NewTx(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000, V: big.NewInt(27), R: big.NewInt(1), S: big.NewInt(1)})
+	require.NoError(t, backend.SendTransaction(context.Background(), tx))
+	assert.Len(t, submitService.sent, 1)
+}
+
+// TestContractBackendWithoutSubmission verifies the RPC is used if no submission endpoint is configured
+func TestContractBackendWithoutSubmission(t *testing.T) {
+	client := newFakeEthClient(t)
+	c := &Client{Client: client}
+	backend, ok := c.ContractBackend().(*nonceRetryBackend)
+	require.True(t, ok)
+	assert.Equal(t, client, backend.ContractBackend)
+}
+
+// TestContractBackendReadEndpoint verifies the state is read from the read endpoint and transactions are sent through the RPC
+func TestContractBackendReadEndpoint(t *testing.T) {
+	writeService := &fakeSubmitService{}
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", writeService))
+	t.Cleanup(server.Stop)
+
+	readClient := newFakeEthClient(t)
+	c := &Client{
+		Client:     ethclient.NewClient(rpc.DialInProc(server)),
+		readClient: readClient,
+	}
+	assert.Equal(t, readClient, c.ReadClient())
+	backend := c.ContractBackend()
+
+	// the RPC doesn't serve gas prices, they come from the read endpoint
+	gasPrice, err := backend.SuggestGasPrice(context.Background())
+	require.NoError(t, err)
+	assert.Positive(t, gasPrice.Sign())
+
+	nonce, err := backend.PendingNonceAt(context.Background(), common.Address{})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(fakeSubmitNonce), nonce)
+
+	tx := types.NewTx(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000, V: big.NewInt(27), R: big.NewInt(1), S: big.NewInt(1)})
+	require.NoError(t, backend.SendTransaction(context.Background(), tx))
+	assert.Len(t, writeService.sent, 1)
+}
@@ -5,11 +5,17 @@ import (
 	"time"
 )
 
+// defaultCacheMaxEntries bounds the number of token prices held by a cache
+const defaultCacheMaxEntries = 1000
+
 // TokenPriceCache manages cached token prices to avoid duplicate API calls
+// expired entries are evicted on Set, and the oldest entry is evicted when the cache is full
 type TokenPriceCache struct {
-	mu       sync.RWMutex
-	cache    map[string]*cachedPrice
-	cacheTTL time.Duration
+	mu         sync.RWMutex
+	cache      map[string]*cachedPrice
+	cacheTTL   time.Duration
+	maxEntries int
+	evictions  uint64
 }
 
 // cachedPrice represents a cached token price with timestamp
@@ -20,9 +26,18 @@ type cachedPrice struct {
 
 // NewTokenPriceCache creates a new token price cache
 func NewTokenPriceCache(cacheTTL time.Duration) *TokenPriceCache {
+	return NewTokenPriceCacheWithLimit(cacheTTL, defaultCacheMaxEntries)
+}
+
+// NewTokenPriceCacheWithLimit creates a new token price cache holding at most maxEntries prices
+func NewTokenPriceCacheWithLimit(cacheTTL time.Duration, maxEntries int) *TokenPriceCache {
+	if maxEntries <= 0 {
+		maxEntries = defaultCacheMaxEntries
+	}
 	return &TokenPriceCache{
-		cache:    make(map[string]*cachedPrice),
-		cacheTTL: cacheTTL,
+		cache:      make(map[string]*cachedPrice),
+		cacheTTL:   cacheTTL,
+		maxEntries: maxEntries,
 	}
 }
 
@@ -49,12 +64,52 @@ func (c *TokenPriceCache) Set(tokenID string, price float64) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	c.evictExpired()
+
+	// Make room for a new entry by evicting the oldest one
+	if _, exists := c.cache[tokenID]; !exists && len(c.cache) >= c.maxEntries {
+		c.evictOldest()
+	}
+
 	c.cache[tokenID] = &cachedPrice{
 		price:     price,
 		timestamp: time.Now(),
 	}
 }
 
+// Evictions returns the number of entries evicted from the cache
+func (c *TokenPriceCache) Evictions() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.evictions
+}
+
+// evictExpired removes the entries past TTL, caller must hold the write lock
+func (c *TokenPriceCache) evictExpired() {
+	for tokenID, cached := range c.cache {
+		if time.Since(cached.timestamp) > c.cacheTTL {
+			delete(c.cache, tokenID)
+			c.evictions++
+		}
+	}
+}
+
+// evictOldest removes the entry with the oldest timestamp, caller must hold the write lock
+func (c *TokenPriceCache) evictOldest() {
+	var oldestID string
+	var oldest time.Time
+	for tokenID, cached := range c.cache {
+		if oldestID == "" || cached.timestamp.Before(oldest) {
+			oldestID = tokenID
+			oldest = cached.timestamp
+		}
+	}
+	if oldestID != "" {
+		delete(c.cache, oldestID)
+		c.evictions++
+	}
+}
+
 // Clear removes all cached entries
 func (c *TokenPriceCache) Clear() {
 	c.mu.Lock()
@@ -97,17 +152,17 @@ func ClearGlobalCache() {
 	}
 }
 
-// GetGlobalCacheStats returns basic statistics about the cache
-func GetGlobalCacheStats() (int, time.Duration) {
+// GetGlobalCacheStats returns basic statistics about the cache: entry count, TTL and number of evictions
+func GetGlobalCacheStats() (int, time.Duration, uint64) {
 	globalCacheMu.Lock()
 	defer globalCacheMu.Unlock()
 
 	if globalTokenPriceCache == nil {
-		return 0, 0
+		return 0, 0, 0
 	}
 
 	globalTokenPriceCache.mu.RLock()
 	defer globalTokenPriceCache.mu.RUnlock()
 
-	return len(globalTokenPriceCache.cache), globalTokenPriceCache.cacheTTL
+	return len(globalTokenPriceCache.cache), globalTokenPriceCache.cacheTTL, globalTokenPriceCache.evictions
 }
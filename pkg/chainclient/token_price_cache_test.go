@@ -1,7 +1,12 @@
 package chainclient
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -78,6 +83,46 @@ func TestTokenPriceCache(t *testing.T) {
 		assert.False(t, found)
 	})
 
+	t.Run("Evict expired on Set", func(t *testing.T) {
+		cache := NewTokenPriceCache(10 * time.Millisecond)
+
+		cache.Set("ethereum", 3000.0)
+		time.Sleep(20 * time.Millisecond)
+
+		// Setting another entry evicts the expired one
+		cache.Set("matic-network", 1.0)
+
+		cache.mu.RLock()
+		_, exists := cache.cache["ethereum"]
+		size := len(cache.cache)
+		cache.mu.RUnlock()
+		assert.False(t, exists)
+		assert.Equal(t, 1, size)
+		assert.Equal(t, uint64(1), cache.Evictions())
+	})
+
+	t.Run("Evict oldest when full", func(t *testing.T) {
+		cache := NewTokenPriceCacheWithLimit(1*time.Second, 2)
+
+		cache.Set("ethereum", 3000.0)
+		time.Sleep(1 * time.Millisecond)
+		cache.Set("matic-network", 1.0)
+		time.Sleep(1 * time.Millisecond)
+		cache.Set("binancecoin", 500.0)
+
+		_, found := cache.Get("ethereum")
+		assert.False(t, found)
+		_, found = cache.Get("matic-network")
+		assert.True(t, found)
+		_, found = cache.Get("binancecoin")
+		assert.True(t, found)
+		assert.Equal(t, uint64(1), cache.Evictions())
+
+		// Updating an existing entry doesn't evict
+		cache.Set("binancecoin", 600.0)
+		assert.Equal(t, uint64(1), cache.Evictions())
+	})
+
 	t.Run("Concurrent access", func(t *testing.T) {
 		cache := NewTokenPriceCache(1 * time.Second)
 		done := make(chan bool, 10)
@@ -119,7 +164,7 @@ func TestGlobalCacheFunctions(t *testing.T) {
 		SetGlobalCacheTTL(newTTL)
 
 		// Verify TTL was set
-		count, ttl := GetGlobalCacheStats()
+		count, ttl, _ := GetGlobalCacheStats()
 		assert.Equal(t, 0, count) // Should be empty
 		assert.Equal(t, newTTL, ttl)
 	})
@@ -131,14 +176,14 @@ func TestGlobalCacheFunctions(t *testing.T) {
 		cache.Set("matic-network", 1.0)
 
 		// Verify data exists
-		count, _ := GetGlobalCacheStats()
+		count, _, _ := GetGlobalCacheStats()
 		assert.Equal(t, 2, count)
 
 		// Clear cache
 		ClearGlobalCache()
 
 		// Verify cache is empty
-		count, _ = GetGlobalCacheStats()
+		count, _, _ = GetGlobalCacheStats()
 		assert.Equal(t, 0, count)
 	})
 
@@ -154,7 +199,7 @@ func TestGlobalCacheFunctions(t *testing.T) {
 		cache.Set("binancecoin", 500.0)
 
 		// Get stats
-		count, ttl := GetGlobalCacheStats()
+		count, ttl, _ := GetGlobalCacheStats()
 		assert.Equal(t, 3, count)
 		assert.Equal(t, 30*time.Second, ttl)
 	})
@@ -182,7 +227,7 @@ func TestCacheIntegration(t *testing.T) {
 		assert.Equal(t, 3000.0, price)
 
 		// Verify cache stats
-		count, _ := GetGlobalCacheStats()
+		count, _, _ := GetGlobalCacheStats()
 		assert.Equal(t, 1, count)
 	})
 
@@ -206,7 +251,7 @@ func TestCacheIntegration(t *testing.T) {
 		assert.Equal(t, 1.0, maticPrice)
 
 		// Verify cache stats
-		count, _ := GetGlobalCacheStats()
+		count, _, _ := GetGlobalCacheStats()
 		assert.Equal(t, 2, count)
 	})
 }
@@ -244,3 +289,70 @@ func BenchmarkTokenPriceCache(b *testing.B) {
 		}
 	})
 }
+
+// TestGetTokenPriceUSDCoalescing verifies concurrent cache misses for the same token result in a single API call
+func TestGetTokenPriceUSDCoalescing(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		// Keep the request in flight so concurrent callers overlap
+		time.Sleep(50 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"ethereum":{"usd":3000}}`))
+	}))
+	defer server.Close()
+
+	originalURL := coinGeckoAPIURL
+	coinGeckoAPIURL = server.URL
+	defer func() { coinGeckoAPIURL = originalURL }()
+
+	ClearGlobalCache()
+	defer ClearGlobalCache()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			price, err := getTokenPriceUSD(context.Background(), 1)
+			assert.NoError(t, err)
+			assert.Equal(t, 3000.0, price)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+// TestGetTokenPriceUSDCoalescingCanceledCaller verifies the caller starting a coalesced request giving up
+// doesn't fail the request shared with the other callers
+func TestGetTokenPriceUSDCoalescingCanceledCaller(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		time.Sleep(100 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"ethereum":{"usd":3000}}`))
+	}))
+	defer server.Close()
+
+	originalURL := coinGeckoAPIURL
+	coinGeckoAPIURL = server.URL
+	defer func() { coinGeckoAPIURL = originalURL }()
+
+	ClearGlobalCache()
+	defer ClearGlobalCache()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := getTokenPriceUSD(ctx, 1)
+		firstErr <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	price, err := getTokenPriceUSD(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3000.0, price)
+	assert.ErrorContains(t, <-firstErr, context.DeadlineExceeded.Error())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
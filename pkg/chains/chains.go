@@ -1,44 +1,75 @@
 package chains
 
-// ChainList contains the list of supported chain IDs
-var ChainList = []int{
-	1,     // Ethereum
-	137,   // Polygon
-	42161, // Arbitrum
-	43114, // Avalanche
-	56,    // Binance Smart Chain
-	7000,  // ZetaChain
-	8453,  // Base
-}
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/speedrun-hq/speedrunner/pkg/config"
+	"github.com/speedrun-hq/speedrunner/pkg/registry"
+)
 
-// chainNames maps chain IDs to their names
-var chainNames = map[int]string{
-	1:     "ETHEREUM",
-	137:   "POLYGON",
-	42161: "ARBITRUM",
-	43114: "AVALANCHE",
-	56:    "BSC",
-	7000:  "ZETACHAIN",
-	8453:  "BASE",
+// ChainList returns the IDs of the supported chains, the built-in chains followed by the custom chains
+func ChainList() []int {
+	return registry.Chains.IDs()
 }
 
-// WithdrawDefaultGasLimit is the default gas limit for withdrawal transactions per chain
-// Exposed for use by other packages
-var WithdrawDefaultGasLimit = map[int]uint64{
-	1:     400000,  // Ethereum
-	137:   400000,  // Polygon
-	42161: 1000000, // Arbitrum
-	43114: 400000,  // Avalanche
-	56:    400000,  // Binance Smart Chain
-	7000:  400000,  // ZetaChain
-	8453:  400000,  // Base
+// RegisterCustomChain adds a chain defined through the CHAINS configuration to the supported chains with its tokens
+// It must be called at startup, before the intents of the chain are processed
+func RegisterCustomChain(chain config.CustomChain) error {
+	tokens := make(map[string]registry.Token, len(chain.Tokens))
+	for _, token := range chain.Tokens {
+		if tokenType := TokenType(token.Type); tokenType != TokenTypeUSDC && tokenType != TokenTypeUSDT {
+			return fmt.Errorf("unsupported token type %s for chain %d", token.Type, chain.ChainID)
+		}
+		tokens[token.Type] = registry.Token{Address: token.Address, Decimals: token.Decimals}
+	}
+
+	if err := registry.Chains.Register(registry.Chain{
+		ID:              chain.ChainID,
+		Name:            chain.Name,
+		Color:           color.FgCyan,
+		PriceID:         chain.PriceID,
+		NativeAsset:     chain.NativeAsset,
+		NativeDecimals:  chain.NativeDecimals,
+		WithdrawGas:     chain.WithdrawGas,
+		MaxGasPrice:     chain.MaxGasPrice,
+		MinGasPrice:     chain.MinGasPrice,
+		ReorgCheckDepth: chain.ReorgCheckDepth,
+		Rollup:          chain.Rollup,
+		Tokens:          tokens,
+		Custom:          true,
+	}); err != nil {
+		return fmt.Errorf("chain %d is already supported", chain.ChainID)
+	}
+	return nil
 }
 
 // GetChainName returns the name of the chain for a given chain ID
 func GetChainName(chainID int) string {
-	name, exists := chainNames[chainID]
+	chain, exists := registry.Chains.Get(chainID)
 	if !exists {
 		return ""
 	}
-	return name
+	return chain.Name
+}
+
+// GetWithdrawGas returns the gas units used to estimate the withdraw fee on the chain, 0 if not supported
+func GetWithdrawGas(chainID int) uint64 {
+	chain, exists := registry.Chains.Get(chainID)
+	if !exists {
+		return 0
+	}
+	return chain.WithdrawGas
+}
+
+// IsSupportedChain returns true if the chain ID is in the list of supported chains
+func IsSupportedChain(chainID int) bool {
+	_, exists := registry.Chains.Get(chainID)
+	return exists
+}
+
+// SameNativeAsset returns true if the gas tokens of the chains are the same asset, so that a native token intent
+// from the source chain can be fulfilled in the gas token of the destination chain
+func SameNativeAsset(sourceChainID, destinationChainID int) bool {
+	return registry.Chains.SameNativeAsset(sourceChainID, destinationChainID)
 }
@@ -4,9 +4,11 @@ import (
 	"errors"
 	"math"
 	"math/big"
+	"strconv"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/speedrun-hq/speedrunner/pkg/registry"
 )
 
 // TokenType represents the type of token
@@ -17,109 +19,88 @@ const (
 	TokenTypeUSDC TokenType = "USDC"
 	// TokenTypeUSDT represents USDT token
 	TokenTypeUSDT TokenType = "USDT"
+	// TokenTypeNative represents the native gas token of the chain
+	TokenTypeNative TokenType = "NATIVE"
 )
 
+// defaultTokenDecimals is the number of decimals assumed for stablecoins when unknown for a chain
+const defaultTokenDecimals = 6
+
+// nativeTokenDecimals is the number of decimals of the native gas token on all supported chains
+const nativeTokenDecimals = 18
+
+// baseAmountPrecision is the float precision in bits used to convert standardized amounts to base units
+const baseAmountPrecision = 256
+
+// NativeTokenSentinel is the conventional address used to represent the native token
+// in addition to the zero address
+var NativeTokenSentinel = common.HexToAddress("0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE")
+
 // Tokenlist contains the supported token types
 var Tokenlist = []TokenType{
 	TokenTypeUSDC,
 	TokenTypeUSDT,
 }
 
-// TODO: create a generic structure that lists all tokens and their attributes
-
-// usdcAddresses maps chain IDs to USDC contract addresses
-var usdcAddresses = map[int]string{
-	1:     "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
-	137:   "0x3c499c542cEF5E3811e1192ce70d8cC03d5c3359",
-	42161: "0xaf88d065e77c8cC2239327C5EDb3A432268e5831",
-	43114: "0xb97ef9ef8734c71904d8002f8b6bc66dd9c48a6e",
-	56:    "0x8AC76a51cc950d9822D68b83fE1Ad97B32Cd580d",
-	7000:  "0x0cbe0dF132a6c6B4a2974Fa1b7Fb953CF0Cc798a",
-	8453:  "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913",
-}
-
-// usdcDecimals maps chain IDs to USDC token decimals
-var usdcDecimals = map[int]int{
-	1:     6,  // Ethereum
-	137:   6,  // Polygon
-	42161: 6,  // Arbitrum
-	43114: 6,  // Avalanche
-	56:    18, // Binance Smart Chain
-	7000:  6,  // ZetaChain
-	8453:  6,  // Base
-}
-
-// usdtAddresses maps chain IDs to USDT contract addresses
-var usdtAddresses = map[int]string{
-	1:     "0xdAC17F958D2ee523a2206206994597C13D831ec7",
-	137:   "0xc2132D05D31c914a87C6611C10748AEb04B58e8F",
-	42161: "0xFd086bC7CD5C481DCC9C85ebE478A1C0b69FCbb9",
-	43114: "0x9702230A8Ea53601f5cD2dc00fDBc13d4dF4A8c7",
-	56:    "0x55d398326f99059fF775485246999027B3197955",
-	7000:  "0x7c8dDa80bbBE1254a7aACf3219EBe1481c6E01d7",
-	8453:  "0x50c5725949A6F0c72E6C4a641F24049A917DB0Cb",
+// getToken returns the token of the type on the chain from the chain registry, empty if not found
+func getToken(chainID int, tokenType TokenType) registry.Token {
+	chain, exists := registry.Chains.Get(chainID)
+	if !exists {
+		return registry.Token{}
+	}
+	return chain.Tokens[string(tokenType)]
 }
 
-// usdtDecimals maps chain IDs to USDT token decimals
-var usdtDecimals = map[int]int{
-	1:     6,  // Ethereum
-	137:   6,  // Polygon
-	42161: 6,  // Arbitrum
-	43114: 6,  // Avalanche
-	56:    18, // Binance Smart Chain
-	7000:  6,  // ZetaChain
-	8453:  6,  // Base
+// GetUSDCDecimals returns the number of decimals for USDC on a given chain
+func GetUSDCDecimals(chainID int) int {
+	return getTokenDecimals(chainID, TokenTypeUSDC)
 }
 
-func getUSDCAddress(chainID int) string {
-	address, exists := usdcAddresses[chainID]
-	if !exists {
-		return ""
-	}
-	return address
+// GetUSDTDecimals returns the number of decimals for USDT on a given chain
+func GetUSDTDecimals(chainID int) int {
+	return getTokenDecimals(chainID, TokenTypeUSDT)
 }
 
-func getUSDTAddress(chainID int) string {
-	address, exists := usdtAddresses[chainID]
-	if !exists {
-		return ""
+// getTokenDecimals returns the decimals of a token from the chain registry, including the CHAIN_<ID>_<TOKEN>_DECIMALS
+// overrides applied at startup, or defaultTokenDecimals if the token is unknown on the chain
+func getTokenDecimals(chainID int, tokenType TokenType) int {
+	if token := getToken(chainID, tokenType); token.Decimals > 0 {
+		return token.Decimals
 	}
-	return address
+	return defaultTokenDecimals
 }
 
-// GetUSDCDecimals returns the number of decimals for USDC on a given chain
-func GetUSDCDecimals(chainID int) int {
-	decimals, exists := usdcDecimals[chainID]
-	if !exists {
-		return 6 // default to 6 decimals if not found
-	}
-	return decimals
+// SetTokenDecimals overrides the decimals of the token of the type on the chain
+// It must be called at startup, before the intents of the chain are processed
+func SetTokenDecimals(chainID int, tokenType TokenType, decimals int) error {
+	return registry.Chains.SetTokenDecimals(chainID, string(tokenType), decimals)
 }
 
-// GetUSDTDecimals returns the number of decimals for USDT on a given chain
-func GetUSDTDecimals(chainID int) int {
-	decimals, exists := usdtDecimals[chainID]
-	if !exists {
-		return 6 // default to 6 decimals if not found
+// IsNativeToken returns true if the address represents the native gas token (zero address or sentinel)
+func IsNativeToken(address string) bool {
+	if !common.IsHexAddress(address) {
+		return false
 	}
-	return decimals
+	tokenAddress := common.HexToAddress(address)
+	return tokenAddress == (common.Address{}) || tokenAddress == NativeTokenSentinel
 }
 
-// GetTokenType returns from the address the name of the token (USDC or USDT)
+// GetTokenType returns from the address the name of the token (USDC, USDT or NATIVE)
 // return an empty string if not found
 func GetTokenType(address string) TokenType {
+	if IsNativeToken(address) {
+		return TokenTypeNative
+	}
+
 	// convert address to lowercase for case-insensitive comparison
 	address = strings.ToLower(address)
 
-	for _, usdcAddress := range usdcAddresses {
-		if strings.ToLower(usdcAddress) == address {
-			return TokenTypeUSDC
-		}
-	}
-
-	for _, usdtAddress := range usdtAddresses {
-		if strings.ToLower(usdtAddress) == address {
-			return TokenTypeUSDT
+	for _, chain := range registry.Chains.All() {
+		for _, tokenType := range Tokenlist {
+			token, exists := chain.Tokens[string(tokenType)]
+			if exists && strings.ToLower(token.Address) == address {
+				return tokenType
+			}
 		}
 	}
 
@@ -128,14 +109,7 @@ func GetTokenType(address string) TokenType {
 
 // GetTokenAddress returns the contract address for a given token type and chain ID
 func GetTokenAddress(chainID int, tokenType TokenType) string {
-	switch tokenType {
-	case TokenTypeUSDC:
-		return getUSDCAddress(chainID)
-	case TokenTypeUSDT:
-		return getUSDTAddress(chainID)
-	default:
-		return ""
-	}
+	return getToken(chainID, tokenType).Address
 }
 
 // GetTokenEthAddress returns the Ethereum address for a given token type
@@ -147,21 +121,31 @@ func GetTokenEthAddress(chainID int, tokenType TokenType) common.Address {
 	return common.HexToAddress(address)
 }
 
+// GetDecimals returns the decimals of the token type on the chain
+func GetDecimals(chainID int, tokenType TokenType) (int, error) {
+	switch tokenType {
+	case TokenTypeUSDC:
+		return GetUSDCDecimals(chainID), nil
+	case TokenTypeUSDT:
+		return GetUSDTDecimals(chainID), nil
+	case TokenTypeNative:
+		return nativeTokenDecimals, nil
+	default:
+		return 0, errors.New("unsupported token type")
+	}
+}
+
 // GetStandardizedAmount returns a float representing the standardized amount for a given token type
 // 1000000 -> 1 USDC for Ethereum
+// Native token amounts are returned in token units and must be converted to USD with the token price
 func GetStandardizedAmount(baseAmount *big.Int, chainID int, tokenType TokenType) (float64, error) {
 	if baseAmount == nil || baseAmount.Sign() <= 0 {
 		return 0, errors.New("invalid base amount")
 	}
 
-	var decimals int
-	switch tokenType {
-	case TokenTypeUSDC:
-		decimals = GetUSDCDecimals(chainID)
-	case TokenTypeUSDT:
-		decimals = GetUSDTDecimals(chainID)
-	default:
-		return 0, errors.New("unsupported token type")
+	decimals, err := GetDecimals(chainID, tokenType)
+	if err != nil {
+		return 0, err
 	}
 
 	// Convert to float64 with appropriate scaling
@@ -170,3 +154,27 @@ func GetStandardizedAmount(baseAmount *big.Int, chainID int, tokenType TokenType
 	result, _ := scaledAmount.Float64()
 	return result, nil
 }
+
+// GetBaseAmount returns the amount in base units for a standardized amount of a given token type
+// 1 USDC -> 1000000 for Ethereum, the reverse of GetStandardizedAmount
+func GetBaseAmount(amount float64, chainID int, tokenType TokenType) (*big.Int, error) {
+	if amount < 0 || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return nil, errors.New("invalid amount")
+	}
+
+	decimals, err := GetDecimals(chainID, tokenType)
+	if err != nil {
+		return nil, err
+	}
+
+	// parse the shortest decimal representation with a high precision to avoid binary float errors at 18 decimals,
+	// then round to the nearest base unit (0.29 USDC would otherwise truncate to 289999)
+	scaled, _, err := big.ParseFloat(strconv.FormatFloat(amount, 'f', -1, 64), 10, baseAmountPrecision, big.ToNearestEven)
+	if err != nil {
+		return nil, err
+	}
+	scale := new(big.Float).SetPrec(baseAmountPrecision).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	scaled.Mul(scaled, scale).Add(scaled, big.NewFloat(0.5))
+	baseAmount, _ := scaled.Int(nil)
+	return baseAmount, nil
+}
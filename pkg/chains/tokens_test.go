@@ -1,6 +1,7 @@
 package chains
 
 import (
+	"github.com/speedrun-hq/speedrunner/pkg/config"
 	"github.com/stretchr/testify/require"
 	"math/big"
 	"testing"
@@ -198,3 +199,89 @@ func abs(x float64) float64 {
 	}
 	return x
 }
+
+func TestGetTokenTypeNative(t *testing.T) {
+	require.True(t, IsNativeToken("0x0000000000000000000000000000000000000000"))
+	require.True(t, IsNativeToken("0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"))
+	require.False(t, IsNativeToken("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"))
+	require.False(t, IsNativeToken(""))
+
+	require.Equal(t, TokenTypeNative, GetTokenType("0x0000000000000000000000000000000000000000"))
+	require.Equal(t, TokenTypeUSDC, GetTokenType("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"))
+
+	amount, err := GetStandardizedAmount(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil), 1, TokenTypeNative)
+	require.NoError(t, err)
+	require.Equal(t, 1.0, amount)
+}
+
+func TestTokenDecimalsOverride(t *testing.T) {
+	require.Equal(t, 18, GetUSDCDecimals(56))
+	require.Equal(t, 6, GetUSDTDecimals(99999))
+
+	require.NoError(t, SetTokenDecimals(1, TokenTypeUSDC, 8))
+	t.Cleanup(func() { require.NoError(t, SetTokenDecimals(1, TokenTypeUSDC, 6)) })
+	require.Equal(t, 8, GetUSDCDecimals(1))
+	require.Equal(t, 6, GetUSDTDecimals(1))
+
+	// only the tokens of registered chains can be overridden
+	require.Error(t, SetTokenDecimals(99999, TokenTypeUSDT, 18))
+	require.Equal(t, 6, GetUSDTDecimals(99999))
+}
+
+func TestGetBaseAmount(t *testing.T) {
+	amount, err := GetBaseAmount(0.29, 1, TokenTypeUSDC)
+	require.NoError(t, err)
+	require.Equal(t, "290000", amount.String())
+
+	// BSC uses 18 decimals
+	amount, err = GetBaseAmount(0.4, 56, TokenTypeUSDT)
+	require.NoError(t, err)
+	require.Equal(t, "400000000000000000", amount.String())
+
+	amount, err = GetBaseAmount(0.001, 42161, TokenTypeNative)
+	require.NoError(t, err)
+	require.Equal(t, "1000000000000000", amount.String())
+
+	_, err = GetBaseAmount(-1, 1, TokenTypeUSDC)
+	require.Error(t, err)
+
+	_, err = GetBaseAmount(1, 1, "")
+	require.Error(t, err)
+}
+
+func TestRegisterCustomChain(t *testing.T) {
+	usdc := "0x0b2C639c533813f4Aa9D7837CAf62653d097Ff85"
+	customChain := config.CustomChain{
+		ChainID:         999999,
+		Name:            "CUSTOM",
+		Tokens:          []config.CustomToken{{Type: "USDC", Address: usdc, Decimals: 18}},
+		WithdrawGas:     80000,
+		MaxGasPrice:     "5000000000",
+		ReorgCheckDepth: 10,
+	}
+
+	require.False(t, IsSupportedChain(999999))
+	require.NoError(t, RegisterCustomChain(customChain))
+	require.True(t, IsSupportedChain(999999))
+	require.Equal(t, "CUSTOM", GetChainName(999999))
+	require.Equal(t, TokenTypeUSDC, GetTokenType(usdc))
+	require.Equal(t, usdc, GetTokenAddress(999999, TokenTypeUSDC))
+	require.Equal(t, 18, GetUSDCDecimals(999999))
+	require.Empty(t, GetTokenAddress(999999, TokenTypeUSDT))
+	require.Equal(t, uint64(80000), GetWithdrawGas(999999))
+
+	// the chain defaults apply unless overridden by the CHAIN_<ID>_* variables
+	maxGasPrice, err := config.GetEnvChainMaxGasPrice(999999, big.NewInt(1))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(5000000000), maxGasPrice)
+	reorgCheckDepth, err := config.GetEnvChainReorgCheckDepth(999999)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), reorgCheckDepth)
+	t.Setenv("CHAIN_999999_REORG_CHECK_DEPTH", "0")
+	reorgCheckDepth, err = config.GetEnvChainReorgCheckDepth(999999)
+	require.NoError(t, err)
+	require.Zero(t, reorgCheckDepth)
+
+	require.ErrorContains(t, RegisterCustomChain(customChain), "already supported")
+	require.ErrorContains(t, RegisterCustomChain(config.CustomChain{ChainID: 8453}), "already supported")
+}
@@ -1,40 +1,70 @@
 package circuitbreaker
 
 import (
-	"github.com/speedrun-hq/speedrunner/pkg/logger"
+	"strconv"
 	"sync"
 	"time"
+
+	"github.com/speedrun-hq/speedrunner/pkg/clock"
+	"github.com/speedrun-hq/speedrunner/pkg/logger"
+	"github.com/speedrun-hq/speedrunner/pkg/metrics"
 )
 
 // CircuitBreaker implements the circuit breaker pattern
 type CircuitBreaker struct {
+	chainID       int
 	enabled       bool
 	failureCount  int
 	failureWindow time.Duration
 	failThreshold int
 	resetTimeout  time.Duration
+	warmup        time.Duration
+	warmupEnd     time.Time
+	cooldown      time.Duration
+	cooldownEnd   time.Time
 	lastFailure   time.Time
 	tripped       bool
 	tripTime      time.Time
 	mu            sync.Mutex
+	clock         clock.Clock
 	logger        logger.Logger
 }
 
 // NewCircuitBreaker creates a new circuit breaker
+// failures during the warmup period following the creation don't count toward the threshold,
+// the circuit is in cooldown for the cooldown period after it closes
 func NewCircuitBreaker(
+	chainID int,
 	enabled bool,
 	threshold int,
 	window time.Duration,
 	resetTimeout time.Duration,
+	warmup time.Duration,
+	cooldown time.Duration,
 	logger logger.Logger,
 ) *CircuitBreaker {
-	return &CircuitBreaker{
+	cb := &CircuitBreaker{
+		chainID:       chainID,
 		enabled:       enabled,
 		failThreshold: threshold,
 		failureWindow: window,
 		resetTimeout:  resetTimeout,
+		warmup:        warmup,
+		warmupEnd:     time.Now().Add(warmup),
+		cooldown:      cooldown,
+		clock:         clock.Real{},
 		logger:        logger,
 	}
+	cb.setOpenMetric(false)
+	return cb
+}
+
+// SetClock replaces the clock of the circuit breaker, the warmup period restarts from the current time of the clock
+func (cb *CircuitBreaker) SetClock(c clock.Clock) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.clock = c
+	cb.warmupEnd = c.Now().Add(cb.warmup)
 }
 
 // RecordFailure records a failure and trips the circuit if threshold is exceeded
@@ -46,21 +76,26 @@ func (cb *CircuitBreaker) RecordFailure() bool {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 
-	now := time.Now()
+	now := cb.clock.Now()
+
+	// Ignore failures while the service warms up, transient RPC failures are expected on startup
+	if now.Before(cb.warmupEnd) {
+		cb.logger.Info("Circuit breaker: Failure ignored during warm-up period")
+		return false
+	}
 
 	// If the circuit is already tripped, check if it's time to try again
 	if cb.tripped {
-		if time.Since(cb.tripTime) > cb.resetTimeout {
+		if now.Sub(cb.tripTime) > cb.resetTimeout {
 			cb.logger.Info("Circuit breaker: Attempting to reset after timeout")
-			cb.tripped = false
-			cb.failureCount = 0
+			cb.closeCircuit(now)
 		} else {
 			return true // Still tripped
 		}
 	}
 
 	// Reset failure count if outside window
-	if time.Since(cb.lastFailure) > cb.failureWindow {
+	if now.Sub(cb.lastFailure) > cb.failureWindow {
 		cb.failureCount = 0
 	}
 
@@ -72,6 +107,8 @@ func (cb *CircuitBreaker) RecordFailure() bool {
 	if cb.failureCount >= cb.failThreshold {
 		cb.tripped = true
 		cb.tripTime = now
+		cb.setOpenMetric(true)
+		metrics.CircuitBreakerTrips.WithLabelValues(strconv.Itoa(cb.chainID)).Inc()
 		cb.logger.Info("Circuit breaker tripped: %d failures in window", cb.failureCount)
 		return true
 	}
@@ -89,22 +126,52 @@ func (cb *CircuitBreaker) IsOpen() bool {
 	defer cb.mu.Unlock()
 
 	// If tripped but reset timeout has passed, try again
-	if cb.tripped && time.Since(cb.tripTime) > cb.resetTimeout {
-		cb.tripped = false
-		cb.failureCount = 0
+	if cb.tripped && cb.clock.Now().Sub(cb.tripTime) > cb.resetTimeout {
+		cb.closeCircuit(cb.clock.Now())
 		return false
 	}
 
 	return cb.tripped
 }
 
+// InCooldown returns true if the circuit closed less than the cooldown period ago,
+// the concurrency of the chain should be limited until the cooldown ends
+func (cb *CircuitBreaker) InCooldown() bool {
+	if !cb.enabled || cb.cooldown <= 0 {
+		return false
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	now := cb.clock.Now()
+	if cb.tripped {
+		if now.Sub(cb.tripTime) <= cb.resetTimeout {
+			return false
+		}
+		cb.closeCircuit(now)
+	}
+	return now.Before(cb.cooldownEnd)
+}
+
 // Reset manually resets the circuit breaker
 func (cb *CircuitBreaker) Reset() {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 
+	if cb.tripped {
+		cb.closeCircuit(cb.clock.Now())
+		return
+	}
+	cb.failureCount = 0
+}
+
+// closeCircuit closes the tripped circuit and starts the cooldown, caller must hold the lock
+func (cb *CircuitBreaker) closeCircuit(now time.Time) {
 	cb.tripped = false
 	cb.failureCount = 0
+	cb.cooldownEnd = now.Add(cb.cooldown)
+	cb.setOpenMetric(false)
 }
 
 // GetState returns the current state of the circuit breaker
@@ -127,3 +194,12 @@ func (cb *CircuitBreaker) IsEnabled() bool {
 	defer cb.mu.Unlock()
 	return cb.enabled
 }
+
+// setOpenMetric publishes the open/closed state of the circuit breaker, caller must hold the lock
+func (cb *CircuitBreaker) setOpenMetric(open bool) {
+	value := 0.0
+	if open {
+		value = 1.0
+	}
+	metrics.CircuitBreakerOpen.WithLabelValues(strconv.Itoa(cb.chainID)).Set(value)
+}
@@ -0,0 +1,100 @@
+package circuitbreaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/speedrun-hq/speedrunner/pkg/clock"
+	"github.com/speedrun-hq/speedrunner/pkg/logger"
+	"github.com/speedrun-hq/speedrunner/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCircuitBreakerMetrics verifies the open gauge and trip counter follow breaker transitions
+func TestCircuitBreakerMetrics(t *testing.T) {
+	cb := NewCircuitBreaker(99901, true, 2, time.Minute, time.Hour, 0, 0, &logger.EmptyLogger{})
+	open := metrics.CircuitBreakerOpen.WithLabelValues("99901")
+	trips := metrics.CircuitBreakerTrips.WithLabelValues("99901")
+
+	assert.Equal(t, 0.0, testutil.ToFloat64(open))
+
+	// first failure is below threshold
+	assert.False(t, cb.RecordFailure())
+	assert.Equal(t, 0.0, testutil.ToFloat64(open))
+
+	// second failure trips the breaker
+	assert.True(t, cb.RecordFailure())
+	assert.Equal(t, 1.0, testutil.ToFloat64(open))
+	assert.Equal(t, 1.0, testutil.ToFloat64(trips))
+
+	// manual reset closes it
+	cb.Reset()
+	assert.Equal(t, 0.0, testutil.ToFloat64(open))
+}
+
+// TestCircuitBreakerHalfOpenMetric verifies the gauge is cleared when the reset timeout elapses
+func TestCircuitBreakerHalfOpenMetric(t *testing.T) {
+	cb := NewCircuitBreaker(99902, true, 1, time.Minute, time.Minute, 0, 0, &logger.EmptyLogger{})
+	fake := clock.NewFake(time.Now())
+	cb.SetClock(fake)
+	open := metrics.CircuitBreakerOpen.WithLabelValues("99902")
+
+	assert.True(t, cb.RecordFailure())
+	assert.Equal(t, 1.0, testutil.ToFloat64(open))
+
+	fake.Advance(time.Minute)
+	assert.True(t, cb.IsOpen())
+
+	fake.Advance(time.Second)
+
+	assert.False(t, cb.IsOpen())
+	assert.Equal(t, 0.0, testutil.ToFloat64(open))
+}
+
+// TestCircuitBreakerWarmup verifies failures during the warm-up period don't count toward the threshold
+func TestCircuitBreakerWarmup(t *testing.T) {
+	cb := NewCircuitBreaker(99903, true, 1, time.Minute, time.Hour, time.Minute, 0, &logger.EmptyLogger{})
+	fake := clock.NewFake(time.Now())
+	cb.SetClock(fake)
+
+	assert.False(t, cb.RecordFailure())
+	assert.False(t, cb.RecordFailure())
+	assert.False(t, cb.IsOpen())
+	failures, _, _, _ := cb.GetState()
+	assert.Equal(t, 0, failures)
+
+	fake.Advance(time.Minute)
+
+	assert.True(t, cb.RecordFailure())
+	assert.True(t, cb.IsOpen())
+}
+
+// TestCircuitBreakerCooldown verifies the cooldown starts when the circuit closes, after the reset timeout or a manual reset
+func TestCircuitBreakerCooldown(t *testing.T) {
+	cb := NewCircuitBreaker(99904, true, 1, time.Minute, time.Minute, 0, 2*time.Minute, &logger.EmptyLogger{})
+	fake := clock.NewFake(time.Now())
+	cb.SetClock(fake)
+
+	// no cooldown before the circuit trips
+	assert.False(t, cb.InCooldown())
+
+	assert.True(t, cb.RecordFailure())
+	assert.False(t, cb.InCooldown())
+
+	// the circuit closes after the reset timeout
+	fake.Advance(time.Minute + time.Second)
+	assert.True(t, cb.InCooldown())
+	assert.False(t, cb.IsOpen())
+
+	fake.Advance(time.Minute)
+	assert.True(t, cb.InCooldown())
+	fake.Advance(time.Minute)
+	assert.False(t, cb.InCooldown())
+
+	// manual reset of a tripped circuit
+	assert.True(t, cb.RecordFailure())
+	cb.Reset()
+	assert.False(t, cb.IsOpen())
+	assert.True(t, cb.InCooldown())
+}
@@ -9,21 +9,117 @@ import (
 
 	"github.com/joho/godotenv"
 	"github.com/speedrun-hq/speedrunner/pkg/logger"
+	"github.com/speedrun-hq/speedrunner/pkg/registry"
 )
 
 // Config holds the configuration for the fulfiller service
 type Config struct {
-	APIEndpoint      string
-	PollingInterval  time.Duration
-	FulfillerAddress string
-	PrivateKey       string
-	Chains           map[int]ChainConfig
-	WorkerCount      int
-	MetricsPort      string
-	CircuitBreaker   CircuitBreakerConfig
-	MaxRetries       int
-	MaxGasPrice      *big.Int
-	LoggerConfig     LoggerConfig
+	APIEndpoint        string
+	APIIntentsPath     string
+	PollingInterval    time.Duration
+	PollingJitter      time.Duration
+	FulfillerAddress   string
+	PrivateKey         string
+	Signer             SignerConfig
+	IntentABIPath      string
+	FulfillMethod      string
+	Chains             map[int]ChainConfig
+	CustomChains       []CustomChain
+	WorkerCount        int
+	MaxIntentsPerCycle int
+	MetricsHost        string
+	MetricsPort        string
+	CircuitBreaker     CircuitBreakerConfig
+	MaxRetries         int
+	RetryPolicies      map[string]RetryPolicy
+	FulfillTimeout     time.Duration
+	MaxGasPrice        *big.Int
+	GasBumpPercent     float64
+	MaxExposureUSD     float64
+	LoggerConfig       LoggerConfig
+
+	// TokenDecimals are the CHAIN_<ID>_<TOKEN>_DECIMALS overrides of the token decimals by chain ID and token type
+	TokenDecimals map[int]map[string]int
+
+	// MaxConcurrentRPC limits the number of concurrent RPC calls across all chains, 0 for no limit
+	MaxConcurrentRPC int
+
+	// ChainConnectConcurrency is the number of chains connected to concurrently at startup
+	ChainConnectConcurrency int
+
+	// MetricsConcurrency is the number of chains whose balance and gas price metrics are updated concurrently
+	MetricsConcurrency int
+
+	// AllowedSourceChains restricts the source chains of fulfilled intents, nil to allow all chains
+	// BlockedSourceChains are never fulfilled from, even if allowed
+	AllowedSourceChains []int
+	BlockedSourceChains []int
+
+	// AllowSameChain fulfills intents with the same source and destination chain, e.g. rebalancing intents
+	AllowSameChain bool
+
+	// IntentClaiming claims intents through the API before fulfillment to avoid double-fulfillment across instances
+	IntentClaiming bool
+
+	// VerifyIntentStatus checks the status of intents through the API before fulfillment,
+	// skipping the intents that are no longer pending
+	VerifyIntentStatus bool
+
+	// ReportFulfillment reports the transaction hash of fulfilled intents to the API to track their settlement
+	ReportFulfillment bool
+
+	// LogTxDetailsOnError logs the parameters of failed fulfill transactions (intent, token, amount, receiver, gas, nonce)
+	LogTxDetailsOnError bool
+
+	// FulfilledLogPath is the file persisting recently fulfilled intents across restarts, empty to disable
+	FulfilledLogPath string
+	FulfilledLogTTL  time.Duration
+
+	// FulfillmentLogPath is the append-only export of every fulfillment for accounting, empty to disable
+	FulfillmentLogPath   string
+	FulfillmentLogFormat string
+
+	// PriceRequestCoalescing collapses concurrent token price requests for the same token into one
+	PriceRequestCoalescing bool
+
+	// PriceRequestRetries is the number of retries of token price requests failing on rate limits, server or network errors
+	PriceRequestRetries int
+	PriceRequestBackoff time.Duration
+
+	// NonceSyncRetries is the number of retries of account nonce requests failing before sending a transaction
+	NonceSyncRetries int
+	NonceSyncBackoff time.Duration
+
+	// RetryMaxAge is the age of an intent after which its retries are dropped regardless of the retry count, 0 for no limit
+	RetryMaxAge time.Duration
+
+	// RetryJitter randomizes the backoff of each retry between 0 and the policy backoff (full jitter),
+	// so that intents failing together are not retried at the same instant
+	RetryJitter bool
+
+	// PendingQueueSize is the number of viable intents queued for the workers, polling blocks when it is full
+	PendingQueueSize int
+	// RetryQueueSize is the number of intents queued for retry, new retries are dropped when it is full
+	// unless RetryEviction evicts a queued retry to make room
+	RetryQueueSize int
+	RetryEviction  string
+	// RetryQueuePath is the file the retry queue is saved to on shutdown and reloaded from on startup, empty to disable
+	RetryQueuePath string
+
+	// BatchFulfillSize is the maximum number of intents to the same receiver, token and destination fulfilled
+	// in one multicall transaction of the Intent contract, 1 disables batching
+	BatchFulfillSize int
+
+	// MaxConcurrentApprovals limits the approval transactions in flight per chain, separately from the workers,
+	// so that approvals of new tokens don't block all the workers, 0 for no limit
+	MaxConcurrentApprovals int
+
+	// FeeSafetyMargin multiplies the withdraw fee the intent fee must exceed, to absorb fee changes until the fulfillment
+	FeeSafetyMargin float64
+
+	// GasUsedAlertRatio is the ratio of the gas used by a fulfillment to the withdraw gas estimate of the chain
+	// above which a warning is logged and an anomaly recorded, 0 to disable the check
+	GasUsedAlertRatio float64
 }
 
 // CircuitBreakerConfig holds circuit breaker configuration
@@ -32,6 +128,29 @@ type CircuitBreakerConfig struct {
 	Threshold      int
 	WindowDuration time.Duration
 	ResetTimeout   time.Duration
+	Warmup         time.Duration
+
+	// Cooldown is the period after the circuit breaker closes during which at most CooldownConcurrency intents
+	// of the chain are processed concurrently, so that a recovering chain isn't overwhelmed at once
+	Cooldown            time.Duration
+	CooldownConcurrency int
+}
+
+// RetryPolicy holds the retry configuration of an error type
+// the backoff doubles on each retry up to MaxBackoff
+type RetryPolicy struct {
+	MaxRetries int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// SignerConfig holds the configuration of the transaction signer
+type SignerConfig struct {
+	Type             string
+	URL              string
+	Address          string
+	KeystorePath     string
+	KeystorePassword string
 }
 
 // LoggerConfig holds the configuration for logging
@@ -55,16 +174,63 @@ func LoadConfig() (*Config, error) {
 		log.Printf("Warning: .env file not found, using environment variables")
 	}
 
+	// Load the settings of the configuration file not set in the environment
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := LoadConfigFile(path); err != nil {
+			return nil, err
+		}
+	}
+
 	pollingInterval, err := GetEnvPollingInterval()
 	if err != nil {
 		return nil, err
 	}
 
+	pollingJitter, err := GetEnvPollingJitter()
+	if err != nil {
+		return nil, err
+	}
+
 	workerCount, err := GetEnvWorkerCount()
 	if err != nil {
 		return nil, err
 	}
 
+	maxConcurrentApprovals, err := GetEnvMaxConcurrentApprovals()
+	if err != nil {
+		return nil, err
+	}
+
+	maxIntentsPerCycle, err := GetEnvMaxIntentsPerCycle()
+	if err != nil {
+		return nil, err
+	}
+
+	pendingQueueSize, err := GetEnvPendingQueueSize()
+	if err != nil {
+		return nil, err
+	}
+
+	retryQueueSize, err := GetEnvRetryQueueSize()
+	if err != nil {
+		return nil, err
+	}
+
+	batchFulfillSize, err := GetEnvBatchFulfillSize()
+	if err != nil {
+		return nil, err
+	}
+
+	retryEviction, err := GetEnvRetryEviction()
+	if err != nil {
+		return nil, err
+	}
+
+	metricsHost, err := GetEnvMetricsHost()
+	if err != nil {
+		return nil, err
+	}
+
 	metricsPort, err := GetEnvMetricsPort()
 	if err != nil {
 		return nil, err
@@ -95,21 +261,96 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	cbWarmup, err := GetEnvCircuitBreakerWarmup()
+	if err != nil {
+		return nil, err
+	}
+
+	cbCooldown, err := GetEnvCircuitBreakerCooldown()
+	if err != nil {
+		return nil, err
+	}
+
+	cbCooldownConcurrency, err := GetEnvCircuitBreakerCooldownConcurrency()
+	if err != nil {
+		return nil, err
+	}
+
 	maxRetries, err := GetEnvMaxRetries()
 	if err != nil {
 		return nil, err
 	}
 
+	retryPolicies, err := GetEnvRetryPolicies(maxRetries)
+	if err != nil {
+		return nil, err
+	}
+
+	retryMaxAge, err := GetEnvRetryMaxAge()
+	if err != nil {
+		return nil, err
+	}
+
+	retryJitter, err := GetEnvRetryJitter()
+	if err != nil {
+		return nil, err
+	}
+
+	fulfillTimeout, err := GetEnvFulfillTimeout()
+	if err != nil {
+		return nil, err
+	}
+
 	maxGasPrice, err := GetEnvMaxGasPrice()
 	if err != nil {
 		return nil, err
 	}
 
+	gasBumpPercent, err := GetEnvGasBumpPercent()
+	if err != nil {
+		return nil, err
+	}
+
+	feeSafetyMargin, err := GetEnvFeeSafetyMargin()
+	if err != nil {
+		return nil, err
+	}
+
+	gasUsedAlertRatio, err := GetEnvGasUsedAlertRatio()
+	if err != nil {
+		return nil, err
+	}
+
+	maxExposureUSD, err := GetEnvMaxExposureUSD()
+	if err != nil {
+		return nil, err
+	}
+
+	allowedSourceChains, err := GetEnvSourceChains("ALLOWED_SOURCE_CHAINS")
+	if err != nil {
+		return nil, err
+	}
+
+	blockedSourceChains, err := GetEnvSourceChains("BLOCKED_SOURCE_CHAINS")
+	if err != nil {
+		return nil, err
+	}
+
+	allowSameChain, err := GetEnvAllowSameChain()
+	if err != nil {
+		return nil, err
+	}
+
 	apiEndpoint, err := GetEnvAPIEndpoint()
 	if err != nil {
 		return nil, err
 	}
 
+	apiIntentsPath, err := GetEnvAPIIntentsPath()
+	if err != nil {
+		return nil, err
+	}
+
 	logLever, err := GetEnvLogLevel()
 	if err != nil {
 		return nil, err
@@ -120,6 +361,101 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	signerType, err := GetEnvSignerType()
+	if err != nil {
+		return nil, err
+	}
+
+	signerURL, err := GetEnvSignerURL()
+	if err != nil {
+		return nil, err
+	}
+
+	signerAddress, err := GetEnvSignerAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	keystorePassword, err := GetEnvKeystorePassword()
+	if err != nil {
+		return nil, err
+	}
+
+	intentABIPath, err := GetEnvIntentABIPath()
+	if err != nil {
+		return nil, err
+	}
+
+	maxConcurrentRPC, err := GetEnvMaxConcurrentRPC()
+	if err != nil {
+		return nil, err
+	}
+
+	chainConnectConcurrency, err := GetEnvChainConnectConcurrency()
+	if err != nil {
+		return nil, err
+	}
+
+	metricsConcurrency, err := GetEnvMetricsConcurrency()
+	if err != nil {
+		return nil, err
+	}
+
+	fulfilledLogTTL, err := GetEnvFulfilledLogTTL()
+	if err != nil {
+		return nil, err
+	}
+
+	fulfillmentLogFormat, err := GetEnvFulfillmentLogFormat()
+	if err != nil {
+		return nil, err
+	}
+
+	intentClaiming, err := GetEnvIntentClaiming()
+	if err != nil {
+		return nil, err
+	}
+
+	verifyIntentStatus, err := GetEnvVerifyIntentStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	reportFulfillment, err := GetEnvReportFulfillment()
+	if err != nil {
+		return nil, err
+	}
+
+	logTxDetailsOnError, err := GetEnvLogTxDetailsOnError()
+	if err != nil {
+		return nil, err
+	}
+
+	priceRequestCoalescing, err := GetEnvPriceRequestCoalescing()
+	if err != nil {
+		return nil, err
+	}
+
+	priceRequestRetries, err := GetEnvPriceRequestRetries()
+	if err != nil {
+		return nil, err
+	}
+
+	priceRequestBackoff, err := GetEnvPriceRequestBackoff()
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSyncRetries, err := GetEnvNonceSyncRetries()
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSyncBackoff, err := GetEnvNonceSyncBackoff()
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize chain configurations
 	chainConfigs := make(map[int]ChainConfig)
 	chainConfigList, err := GetEnvChainConfigs(mainnet)
@@ -130,26 +466,95 @@ func LoadConfig() (*Config, error) {
 		chainConfigs[chainConfig.ChainID] = chainConfig
 	}
 
+	// Custom chains are registered by the fulfiller with their tokens
+	customChains, err := GetEnvCustomChains()
+	if err != nil {
+		return nil, err
+	}
+
+	// Token decimals overrides of the built-in and custom chains, applied to the registry by the fulfiller
+	chainIDs := registry.Chains.IDs()
+	for _, customChain := range customChains {
+		chainIDs = append(chainIDs, customChain.ChainID)
+	}
+	tokenDecimals, err := GetEnvTokenDecimals(chainIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &Config{
 		APIEndpoint:      apiEndpoint,
+		APIIntentsPath:   apiIntentsPath,
 		PollingInterval:  pollingInterval,
+		PollingJitter:    pollingJitter,
 		FulfillerAddress: fulfillerAddress,
 		PrivateKey:       os.Getenv("PRIVATE_KEY"),
-		Chains:           chainConfigs,
-		WorkerCount:      workerCount,
-		MetricsPort:      metricsPort,
+		Signer: SignerConfig{
+			Type:             signerType,
+			URL:              signerURL,
+			Address:          signerAddress,
+			KeystorePath:     os.Getenv("KEYSTORE_PATH"),
+			KeystorePassword: keystorePassword,
+		},
+		IntentABIPath:      intentABIPath,
+		FulfillMethod:      GetEnvIntentFulfillMethod(),
+		Chains:             chainConfigs,
+		CustomChains:       customChains,
+		TokenDecimals:      tokenDecimals,
+		WorkerCount:        workerCount,
+		MaxIntentsPerCycle: maxIntentsPerCycle,
+		MetricsHost:        metricsHost,
+		MetricsPort:        metricsPort,
 		CircuitBreaker: CircuitBreakerConfig{
 			Enabled:        cbEnabled,
 			Threshold:      cbThreshold,
 			WindowDuration: cbWindow,
 			ResetTimeout:   cbReset,
+			Warmup:         cbWarmup,
+
+			Cooldown:            cbCooldown,
+			CooldownConcurrency: cbCooldownConcurrency,
 		},
 		LoggerConfig: LoggerConfig{
 			Level:    logLever,
 			Coloring: logColoring,
 		},
-		MaxRetries:  maxRetries,
-		MaxGasPrice: maxGasPrice,
+		MaxRetries:             maxRetries,
+		RetryPolicies:          retryPolicies,
+		FulfillTimeout:         fulfillTimeout,
+		MaxGasPrice:            maxGasPrice,
+		GasBumpPercent:         gasBumpPercent,
+		MaxExposureUSD:         maxExposureUSD,
+		MaxConcurrentRPC:       maxConcurrentRPC,
+		AllowedSourceChains:    allowedSourceChains,
+		BlockedSourceChains:    blockedSourceChains,
+		AllowSameChain:         allowSameChain,
+		IntentClaiming:         intentClaiming,
+		VerifyIntentStatus:     verifyIntentStatus,
+		ReportFulfillment:      reportFulfillment,
+		LogTxDetailsOnError:    logTxDetailsOnError,
+		FulfilledLogPath:       GetEnvFulfilledLogPath(),
+		FulfilledLogTTL:        fulfilledLogTTL,
+		PriceRequestCoalescing: priceRequestCoalescing,
+
+		ChainConnectConcurrency: chainConnectConcurrency,
+		MetricsConcurrency:      metricsConcurrency,
+		FulfillmentLogPath:      GetEnvFulfillmentLogPath(),
+		FulfillmentLogFormat:    fulfillmentLogFormat,
+		PriceRequestRetries:     priceRequestRetries,
+		PriceRequestBackoff:     priceRequestBackoff,
+		NonceSyncRetries:        nonceSyncRetries,
+		NonceSyncBackoff:        nonceSyncBackoff,
+		RetryMaxAge:             retryMaxAge,
+		RetryJitter:             retryJitter,
+		PendingQueueSize:        pendingQueueSize,
+		RetryQueueSize:          retryQueueSize,
+		RetryEviction:           retryEviction,
+		RetryQueuePath:          GetEnvRetryQueuePath(),
+		BatchFulfillSize:        batchFulfillSize,
+		MaxConcurrentApprovals:  maxConcurrentApprovals,
+		FeeSafetyMargin:         feeSafetyMargin,
+		GasUsedAlertRatio:       gasUsedAlertRatio,
 	}
 
 	// Validate required environment variables
@@ -162,11 +567,26 @@ func LoadConfig() (*Config, error) {
 
 // validateConfig validates the configuration
 func validateConfig(cfg *Config) error {
-	if cfg.PrivateKey == "" {
-		return fmt.Errorf("PRIVATE_KEY environment variable is required")
+	switch cfg.Signer.Type {
+	case SignerTypeLocal:
+		if cfg.PrivateKey == "" {
+			return fmt.Errorf("PRIVATE_KEY environment variable is required")
+		}
+	case SignerTypeKeystore:
+		if cfg.Signer.KeystorePath == "" {
+			return fmt.Errorf("KEYSTORE_PATH environment variable is required for signer type %s", cfg.Signer.Type)
+		}
+		// the keystore must hold the key of the configured fulfiller, which can only be checked with its address
+		if cfg.FulfillerAddress == DefaultFulfillerAddress {
+			return fmt.Errorf("FULFILLER_ADDRESS environment variable is required for signer type %s", cfg.Signer.Type)
+		}
+	default:
+		if cfg.Signer.URL == "" {
+			return fmt.Errorf("SIGNER_URL environment variable is required for signer type %s", cfg.Signer.Type)
+		}
 	}
 	if len(cfg.Chains) == 0 {
-		return fmt.Errorf("at least one chain configuration is required")
+		return fmt.Errorf("at least one chain configuration is required, all chains are disabled with CHAIN_<ID>_ENABLED")
 	}
 	for chainID, chainConfig := range cfg.Chains {
 		if chainConfig.IntentAddress == "" {
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/speedrun-hq/speedrunner/pkg/registry"
+)
+
+// CustomChain is an EVM chain defined entirely through the CHAINS variable, in addition to the built-in chains
+// The per-chain variables (CHAIN_<ID>_*) apply to custom chains like to the built-in ones
+type CustomChain struct {
+	ChainID       int           `json:"chainId"`
+	Name          string        `json:"name"`
+	RPCURL        string        `json:"rpc"`
+	IntentAddress string        `json:"intentAddress"`
+	MinFee        string        `json:"minFee"`
+	PriceID       string        `json:"priceId"`
+	NativeAsset   string        `json:"nativeAsset"`
+	Tokens        []CustomToken `json:"tokens"`
+	// NativeDecimals is the decimals of the gas token, 18 if not set
+	NativeDecimals int `json:"nativeDecimals"`
+	// Optional defaults of the chain, overridden by the CHAIN_<ID>_* variables
+	WithdrawGas     uint64 `json:"withdrawGas"`
+	MaxGasPrice     string `json:"maxGasPrice"`
+	MinGasPrice     string `json:"minGasPrice"`
+	ReorgCheckDepth uint64 `json:"reorgCheckDepth"`
+	Rollup          string `json:"rollup"`
+}
+
+// CustomToken is a token of a custom chain
+type CustomToken struct {
+	Type     string `json:"type"`
+	Address  string `json:"address"`
+	Decimals int    `json:"decimals"`
+}
+
+// customTokenTypes are the token types of custom chain tokens
+var customTokenTypes = []string{"USDC", "USDT"}
+
+// customRollupTypes are the rollup types of custom chains paying an L1 data fee
+var customRollupTypes = []string{registry.RollupArbitrum, registry.RollupOPStack}
+
+// GetEnvCustomChains returns the custom chains of the CHAINS variable, a JSON array of chain definitions, e.g.
+// [{"chainId": 10, "name": "OPTIMISM", "rpc": "https://mainnet.optimism.io", "intentAddress": "0x...",
+// "minFee": "100000", "priceId": "ethereum", "nativeAsset": "ETH", "tokens": [{"type": "USDC", "address": "0x...", "decimals": 6}],
+// "withdrawGas": 80000, "maxGasPrice": "5000000000", "rollup": "opstack"}]
+func GetEnvCustomChains() ([]CustomChain, error) {
+	chainsJSON := os.Getenv("CHAINS")
+	if chainsJSON == "" {
+		return nil, nil
+	}
+
+	var customChains []CustomChain
+	if err := json.Unmarshal([]byte(chainsJSON), &customChains); err != nil {
+		return nil, fmt.Errorf("invalid CHAINS value: %v", err)
+	}
+
+	seen := make(map[int]bool)
+	for i := range customChains {
+		if err := normalizeCustomChain(&customChains[i]); err != nil {
+			return nil, fmt.Errorf("invalid CHAINS entry %d: %v", i, err)
+		}
+		if seen[customChains[i].ChainID] {
+			return nil, fmt.Errorf("invalid CHAINS entry %d: chain %d defined twice", i, customChains[i].ChainID)
+		}
+		seen[customChains[i].ChainID] = true
+	}
+	return customChains, nil
+}
+
+// normalizeCustomChain validates the definition of a custom chain, upper-cases its name and token types
+// and checksums its addresses
+func normalizeCustomChain(chain *CustomChain) error {
+	if chain.ChainID <= 0 {
+		return fmt.Errorf("chainId must be greater than 0")
+	}
+	if prefix, builtIn := chainEnvPrefix(chain.ChainID); builtIn {
+		return fmt.Errorf("chain %d is built-in, configure it with the %s_* variables", chain.ChainID, prefix)
+	}
+
+	chain.Name = strings.ToUpper(strings.TrimSpace(chain.Name))
+	if chain.Name == "" {
+		return fmt.Errorf("no name for chain %d", chain.ChainID)
+	}
+	if _, err := url.ParseRequestURI(chain.RPCURL); err != nil {
+		return fmt.Errorf("invalid rpc for chain %d: %v", chain.ChainID, err)
+	}
+	if !common.IsHexAddress(chain.IntentAddress) {
+		return fmt.Errorf("invalid intentAddress for chain %d: %q is not a hex address", chain.ChainID, chain.IntentAddress)
+	}
+	chain.IntentAddress = common.HexToAddress(chain.IntentAddress).Hex()
+
+	if minFee, ok := new(big.Int).SetString(chain.MinFee, 10); !ok || minFee.Sign() < 0 {
+		return fmt.Errorf("invalid minFee for chain %d: %q", chain.ChainID, chain.MinFee)
+	}
+	if chain.PriceID == "" {
+		return fmt.Errorf("no priceId (CoinGecko API id of the gas token) for chain %d", chain.ChainID)
+	}
+	// native token intents are only fulfilled between chains with the same native asset
+	chain.NativeAsset = strings.ToUpper(strings.TrimSpace(chain.NativeAsset))
+	if chain.NativeDecimals < 0 || chain.NativeDecimals > 77 {
+		return fmt.Errorf("nativeDecimals for chain %d must be between 1 and 77 if set", chain.ChainID)
+	}
+
+	if err := checkCustomGasPrice("maxGasPrice", chain.ChainID, chain.MaxGasPrice); err != nil {
+		return err
+	}
+	if err := checkCustomGasPrice("minGasPrice", chain.ChainID, chain.MinGasPrice); err != nil {
+		return err
+	}
+	chain.Rollup = strings.ToLower(strings.TrimSpace(chain.Rollup))
+	if chain.Rollup != "" && !slices.Contains(customRollupTypes, chain.Rollup) {
+		return fmt.Errorf("invalid rollup %q for chain %d, must be one of %s",
+			chain.Rollup, chain.ChainID, strings.Join(customRollupTypes, ", "))
+	}
+
+	for i := range chain.Tokens {
+		token := &chain.Tokens[i]
+		token.Type = strings.ToUpper(token.Type)
+		if !slices.Contains(customTokenTypes, token.Type) {
+			return fmt.Errorf("invalid token type %q for chain %d, must be one of %s",
+				token.Type, chain.ChainID, strings.Join(customTokenTypes, ", "))
+		}
+		if !common.IsHexAddress(token.Address) {
+			return fmt.Errorf("invalid %s address for chain %d: %q is not a hex address", token.Type, chain.ChainID, token.Address)
+		}
+		token.Address = common.HexToAddress(token.Address).Hex()
+		if token.Decimals < 1 || token.Decimals > 77 {
+			return fmt.Errorf("%s decimals for chain %d must be between 1 and 77", token.Type, chain.ChainID)
+		}
+	}
+	return nil
+}
+
+// checkCustomGasPrice checks the optional gas price of a custom chain is a positive amount of wei
+func checkCustomGasPrice(name string, chainID int, gasPrice string) error {
+	if gasPrice == "" {
+		return nil
+	}
+	if parsed, ok := new(big.Int).SetString(gasPrice, 10); !ok || parsed.Sign() <= 0 {
+		return fmt.Errorf("invalid %s for chain %d: %q", name, chainID, gasPrice)
+	}
+	return nil
+}
+
+// ChainConfig returns the chain configuration of the custom chain
+func (c CustomChain) ChainConfig() ChainConfig {
+	return ChainConfig{
+		ChainID:       c.ChainID,
+		RPCURL:        c.RPCURL,
+		IntentAddress: c.IntentAddress,
+		MinFee:        c.MinFee,
+	}
+}
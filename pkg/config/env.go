@@ -3,13 +3,16 @@ package config
 import (
 	"fmt"
 	"math/big"
+	"net"
 	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/speedrun-hq/speedrunner/pkg/logger"
+	"github.com/speedrun-hq/speedrunner/pkg/registry"
 )
 
 const (
@@ -22,12 +25,45 @@ const (
 	// DefaultPollingInterval defines the default polling interval in seconds
 	DefaultPollingInterval = 5
 
+	// DefaultPollingJitter defines the default maximum random delay in seconds added to the polling interval
+	DefaultPollingJitter = 0
+
 	// DefaultWorkerCount defines the default number of workers to process intents
 	DefaultWorkerCount = 5
 
+	// DefaultMaxConcurrentApprovals defines the maximum number of approval transactions in flight per chain, 0 for no limit
+	DefaultMaxConcurrentApprovals = 0
+
+	// DefaultMaxIntentsPerCycle defines the maximum number of intents evaluated per polling cycle
+	DefaultMaxIntentsPerCycle = 100
+
+	// DefaultPendingQueueSize defines the number of viable intents queued for the workers
+	DefaultPendingQueueSize = 100
+
+	// DefaultRetryQueueSize defines the number of intents queued for retry
+	DefaultRetryQueueSize = 100
+
+	// DefaultBatchFulfillSize defines the maximum number of intents fulfilled in one transaction, 1 disables batching
+	DefaultBatchFulfillSize = 1
+
+	// RetryEvictionNone rejects new retries when the retry queue is full
+	RetryEvictionNone = "none"
+
+	// RetryEvictionOldest evicts the retry of the oldest intent when the retry queue is full
+	RetryEvictionOldest = "oldest"
+
+	// RetryEvictionLowestFee evicts the retry of the intent with the lowest fee in USD when the retry queue is full
+	RetryEvictionLowestFee = "lowest_fee"
+
+	// DefaultRetryEviction defines the default policy applied when the retry queue is full
+	DefaultRetryEviction = RetryEvictionNone
+
 	// DefaultMetricsPort defines the default port for the metrics server
 	DefaultMetricsPort = "8080"
 
+	// DefaultMetricsHost defines the default bind address for the metrics server, empty listens on all interfaces
+	DefaultMetricsHost = ""
+
 	// DefaultFulfillerAddress defines the default fulfiller address
 	DefaultFulfillerAddress = "0x0000000000000000000000000000000000000000"
 
@@ -43,30 +79,170 @@ const (
 	// DefaultCircuitBreakerReset defines the reset timeout for the circuit breaker
 	DefaultCircuitBreakerReset = 15
 
+	// DefaultCircuitBreakerWarmup defines the period after start during which failures don't count toward the threshold
+	DefaultCircuitBreakerWarmup = 0
+
+	// DefaultCircuitBreakerCooldown defines the period after the circuit breaker closes during which
+	// the concurrency of the chain is limited, 0 to resume at full concurrency
+	DefaultCircuitBreakerCooldown = 0
+
+	// DefaultCircuitBreakerCooldownConcurrency defines the number of intents of a chain processed concurrently
+	// during the cooldown
+	DefaultCircuitBreakerCooldownConcurrency = 1
+
 	// DefaultMaxRetries defines the maximum number of retries for failed operations
 	DefaultMaxRetries = 10
 
+	// DefaultRetryMaxAge defines the maximum age in minutes of an intent being retried, 0 for no limit
+	DefaultRetryMaxAge = 30
+
+	// DefaultRetryJitter defines whether the backoff of retries is randomized so that intents failing together
+	// don't retry at the same instant
+	DefaultRetryJitter = false
+
+	// DefaultFulfillTimeout defines the maximum time in seconds to process a single intent fulfillment
+	DefaultFulfillTimeout = 180
+
+	// DefaultGasBumpPercent defines the gas price increase in percent applied per retry of gas errors and stuck transactions
+	DefaultGasBumpPercent = 12.5
+
+	// DefaultFeeSafetyMargin defines the multiplier applied to the withdraw fee when checking the intent fee covers it
+	DefaultFeeSafetyMargin = 1.0
+
+	// DefaultGasUsedAlertRatio defines the ratio of the gas used by a fulfillment to the withdraw gas estimate
+	// above which an anomaly is reported, 0 to disable the check
+	DefaultGasUsedAlertRatio = 3.0
+
+	// DefaultMaxExposureUSD defines the maximum USD value committed to intents in flight across all chains, 0 for no limit
+	DefaultMaxExposureUSD = 0
+
 	// DefaultMaxGasPrice defines the maximum gas price for transactions
 	DefaultMaxGasPrice = "1000000000" // 1 Gwei
 
 	// DefaultAPIEndpoint defines the default API endpoint for the Speedrun service
 	DefaultAPIEndpoint = "https://api.speedrun.exchange"
 
+	// DefaultAPIIntentsPath defines the path and query of pending intents relative to the API endpoint
+	DefaultAPIIntentsPath = "/api/v1/intents?status=pending"
+
+	// DefaultIntentFulfillMethod is the name of the fulfill method of the Intent contract
+	DefaultIntentFulfillMethod = "fulfill"
+
+	// SignerTypeLocal signs transactions with the PRIVATE_KEY held in memory
+	SignerTypeLocal = "local"
+
+	// SignerTypeKeystore signs transactions with a key decrypted from a Web3 Secret Storage keystore file
+	SignerTypeKeystore = "keystore"
+
+	// SignerTypeClef signs transactions through a clef instance
+	SignerTypeClef = "clef"
+
+	// SignerTypeWeb3Signer signs transactions through a Web3Signer HTTP endpoint
+	SignerTypeWeb3Signer = "web3signer"
+
+	// DefaultSignerType defines the default signer used for transactions
+	DefaultSignerType = SignerTypeLocal
+
+	// DefaultIntentClaiming defines whether intents are claimed through the API before fulfillment
+	DefaultIntentClaiming = false
+
+	// DefaultVerifyIntentStatus defines whether the status of intents is checked through the API before fulfillment
+	DefaultVerifyIntentStatus = false
+
+	// DefaultReportFulfillment defines whether fulfillment transactions are reported to the API
+	DefaultReportFulfillment = false
+
+	// DefaultLogTxDetailsOnError defines whether the parameters of failed fulfill transactions are logged
+	DefaultLogTxDetailsOnError = false
+
+	// DefaultAllowSameChain defines whether intents with the same source and destination chain are fulfilled
+	DefaultAllowSameChain = false
+
+	// DefaultFulfilledLogTTL defines the time in seconds a fulfilled intent is kept in the fulfilled intent log
+	DefaultFulfilledLogTTL = 600
+
+	// FulfillmentLogFormatJSON writes the fulfillment export as JSON lines
+	FulfillmentLogFormatJSON = "json"
+
+	// FulfillmentLogFormatCSV writes the fulfillment export as CSV with a header row
+	FulfillmentLogFormatCSV = "csv"
+
+	// DefaultFulfillmentLogFormat defines the default format of the fulfillment export
+	DefaultFulfillmentLogFormat = FulfillmentLogFormatJSON
+
+	// DefaultUseAccessList defines whether fulfill transactions are sent with an access list created by the node
+	DefaultUseAccessList = false
+
+	// DefaultRPCReconnectFailures defines the number of consecutive failed RPC health checks before reconnecting
+	DefaultRPCReconnectFailures = 3
+
+	// DefaultRPCReconnectMaxBackoff defines the maximum delay in seconds between RPC reconnection attempts
+	DefaultRPCReconnectMaxBackoff = 300
+
+	// DefaultChainConnectConcurrency defines the number of chains connected to concurrently at startup
+	DefaultChainConnectConcurrency = 4
+
+	// DefaultMetricsConcurrency defines the number of chains whose balance and gas price metrics are updated concurrently
+	DefaultMetricsConcurrency = 4
+
+	// DefaultApprovalGasMultiplier defines the multiplier applied on the fulfill gas price for approval transactions
+	DefaultApprovalGasMultiplier = 1.0
+
+	// DefaultBaseFeeMultiplier defines the multiplier of the base fee in the fee cap of dynamic fee transactions,
+	// leaving headroom for a couple of base fee increases
+	DefaultBaseFeeMultiplier = 2.0
+
+	// DefaultMaxConcurrentRPC defines the maximum number of concurrent RPC calls across all chains, 0 for no limit
+	DefaultMaxConcurrentRPC = 0
+
+	// DefaultPriceRequestRetries defines the number of retries of a failed token price request
+	DefaultPriceRequestRetries = 3
+
+	// DefaultPriceRequestBackoff defines the delay in seconds before the first retry of a token price request,
+	// doubled on each retry
+	DefaultPriceRequestBackoff = 1
+
+	// DefaultNonceSyncRetries defines the number of retries of a failed account nonce request before sending a transaction
+	DefaultNonceSyncRetries = 3
+
+	// DefaultNonceSyncBackoff defines the delay in milliseconds before the first retry of an account nonce request,
+	// doubled on each retry
+	DefaultNonceSyncBackoff = 500
+
+	// DefaultPriceRequestCoalescing defines whether concurrent token price requests are coalesced into one
+	DefaultPriceRequestCoalescing = true
+
+	// GasSourceSuggested uses eth_gasPrice as the gas price source
+	GasSourceSuggested = "suggested"
+
+	// GasSourceFeeHistory uses eth_feeHistory at a percentile as the gas price source
+	GasSourceFeeHistory = "feehistory"
+
+	// DefaultGasSource defines the default gas price source
+	DefaultGasSource = GasSourceSuggested
+
+	// DefaultGasPercentile defines the default priority fee percentile used with the fee history gas source
+	DefaultGasPercentile = 50.0
+
 	// logging default options
 
 	DefaultLogLevel    = logger.DebugLevel
-	DefaultLogColoring = true
+	DefaultLogColoring = LogColoringAuto
+
+	// LogColoringAuto enables coloring in the logs only when they are written to a terminal
+	LogColoringAuto = "auto"
 
 	// Network specific values
 	// Note: intent address values are not prefixed with "Default"
 	// These are the values to use but can still be overridden by environment variables for debugging purposes
 
 	// Min fee is the minimum fee in base value for each network for the intent to be picked up
-	// For now these values represent base amount in USDC and USDT
+	// For now these values represent base amount in USDC and USDT, with 6 decimals on all chains but BSC (18 decimals),
+	// ZetaChain included: its ZRC20 USDC and USDT use 6 decimals, so its min fee is 0.1 USD like the other chains
 
 	// Base
 
-	BaseMainnetChainID       = 8453
+	BaseMainnetChainID       = registry.BaseChainID
 	BaseMainnetIntentAddress = "0x999fce149FD078DCFaa2C681e060e00F528552f4"
 
 	DefaultBaseRPCURL        = "https://mainnet.base.org"
@@ -74,7 +250,7 @@ const (
 
 	// Arbitrum
 
-	ArbitrumMainnetChainID       = 42161
+	ArbitrumMainnetChainID       = registry.ArbitrumChainID
 	ArbitrumMainnetIntentAddress = "0xD6B0E2a8D115cCA2823c5F80F8416644F3970dD2"
 
 	DefaultArbitrumMainnetRPCURL = "https://arb1.arbitrum.io/rpc"
@@ -82,7 +258,7 @@ const (
 
 	// Polygon
 
-	PolygonMainnetChainID       = 137
+	PolygonMainnetChainID       = registry.PolygonChainID
 	PolygonMainnetIntentAddress = "0x4017717c550E4B6E61048D412a718D6A8078d264"
 
 	DefaultPolygonMainnetRPCURL = "https://polygon-mainnet.public.blastapi.io"
@@ -90,7 +266,7 @@ const (
 
 	// Ethereum
 
-	EthereumMainnetChainID       = 1
+	EthereumMainnetChainID       = registry.EthereumChainID
 	EthereumMainnetIntentAddress = "0x951AB2A5417a51eB5810aC44BC1fC716995C1CAB"
 
 	DefaultEthereumMainnetRPCURL = "https://eth.llamarpc.com"
@@ -98,7 +274,7 @@ const (
 
 	// Avalanche
 
-	AvalancheMainnetChainID       = 43114
+	AvalancheMainnetChainID       = registry.AvalancheChainID
 	AvalancheMainnetIntentAddress = "0x9a22A7d337aF1801BEEcDBE7f4f04BbD09F9E5bb"
 
 	DefaultAvalancheMainnetRPCURL = "https://avalanche-c-chain-rpc.publicnode.com"
@@ -106,7 +282,7 @@ const (
 
 	// Binance Smart Chain (BSC)
 
-	BSCMainnetChainID       = 56
+	BSCMainnetChainID       = registry.BSCChainID
 	BSCMainnetIntentAddress = "0x68282fa70a32E52711d437b6c5984B714Eec3ED0"
 
 	DefaultBSCMainnetRPCURL = "https://bsc-dataseed.bnbchain.org"
@@ -114,23 +290,33 @@ const (
 
 	// ZetaChain
 
-	ZetaChainMainnetChainID       = 7000
+	ZetaChainMainnetChainID       = registry.ZetaChainChainID
 	ZetaChainMainnetIntentAddress = "0x986e2db1aF08688dD3C9311016026daD15969e09"
 
 	DefaultZetaChainMainnetRPCURL = "https://zetachain-evm.blockpi.network/v1/rpc/public"
 	DefaultZetaChainMainnetMinFee = "100000"
 )
 
-// DefaultChainMaxGasPrice holds starting per-chain gas price caps in wei
-var DefaultChainMaxGasPrice = map[int]string{
-	1:     "10000000000", // Ethereum: 10 gwei
-	137:   "50000000000", // Polygon: 50 gwei
-	42161: "5000000000",  // Arbitrum: 5 gwei
-	8453:  "5000000000",  // Base: 5 gwei
-	56:    "10000000000", // BSC: 10 gwei
-	43114: "10000000000", // Avalanche: 10 gwei
-	7000:  "10000000000", // ZetaChain: 10 gwei
-}
+// DefaultMinGasPrice is the gas price floor in wei of chains without a floor in the chain registry, 0.001 gwei
+const DefaultMinGasPrice = "1000000"
+
+// DefaultMaxPendingTx is the number of transactions of the fulfiller pending in the mempool of a chain
+// above which new fulfillments on the chain are paused
+const DefaultMaxPendingTx uint64 = 10
+
+// DefaultConfirmations is the number of blocks including the transaction block before a transaction is considered successful
+const DefaultConfirmations uint64 = 1
+
+// DefaultReceiptPollInterval is the interval between receipt requests while waiting for a transaction to be mined,
+// the cadence of the go-ethereum bind.WaitMined
+const DefaultReceiptPollInterval = time.Second
+
+// DefaultReorgCheckDepth is the number of blocks after which fulfillments are re-checked for reorgs,
+// 0 disables the check on chains without a depth in the chain registry
+const DefaultReorgCheckDepth uint64 = 0
+
+// DefaultWithdrawGas is the gas units used to estimate the withdraw fee on chains without a specific default
+const DefaultWithdrawGas = registry.DefaultWithdrawGas
 
 // GetEnvNetwork returns the configured network from environment variables or defaults to mainnet
 func GetEnvNetwork() (string, error) {
@@ -164,6 +350,24 @@ func GetEnvPollingInterval() (time.Duration, error) {
 	return time.Duration(interval) * time.Second, nil
 }
 
+// GetEnvPollingJitter returns the maximum random delay added to each polling interval from environment variables
+func GetEnvPollingJitter() (time.Duration, error) {
+	jitter := os.Getenv("POLLING_JITTER")
+	if jitter == "" {
+		return DefaultPollingJitter * time.Second, nil
+	}
+
+	// Validate duration format
+	parsed, err := time.ParseDuration(jitter)
+	if err != nil {
+		return 0, fmt.Errorf("invalid POLLING_JITTER value: %s, must be a valid duration string", jitter)
+	}
+	if parsed < 0 {
+		return 0, fmt.Errorf("POLLING_JITTER must not be negative")
+	}
+	return parsed, nil
+}
+
 // GetEnvWorkerCount returns the number of workers from environment variables
 func GetEnvWorkerCount() (int, error) {
 	workerCount := os.Getenv("WORKER_COUNT")
@@ -182,6 +386,133 @@ func GetEnvWorkerCount() (int, error) {
 	return count, nil
 }
 
+// GetEnvMaxConcurrentApprovals returns the maximum number of approval transactions in flight per chain, 0 for no limit
+func GetEnvMaxConcurrentApprovals() (int, error) {
+	maxConcurrent := os.Getenv("MAX_CONCURRENT_APPROVALS")
+	if maxConcurrent == "" {
+		return DefaultMaxConcurrentApprovals, nil
+	}
+
+	count, err := strconv.Atoi(maxConcurrent)
+	if err != nil {
+		return 0, fmt.Errorf("invalid MAX_CONCURRENT_APPROVALS value: %s, must be an integer", maxConcurrent)
+	}
+	if count < 0 {
+		return 0, fmt.Errorf("MAX_CONCURRENT_APPROVALS must not be negative")
+	}
+	return count, nil
+}
+
+// GetEnvPendingQueueSize returns the number of viable intents queued for the workers from environment variables
+func GetEnvPendingQueueSize() (int, error) {
+	return getEnvQueueSize("PENDING_QUEUE_SIZE", DefaultPendingQueueSize)
+}
+
+// GetEnvRetryQueueSize returns the number of intents queued for retry from environment variables
+func GetEnvRetryQueueSize() (int, error) {
+	return getEnvQueueSize("RETRY_QUEUE_SIZE", DefaultRetryQueueSize)
+}
+
+// GetEnvRetryEviction returns the policy applied when the retry queue is full from environment variables
+func GetEnvRetryEviction() (string, error) {
+	eviction := os.Getenv("RETRY_EVICTION")
+	if eviction == "" {
+		return DefaultRetryEviction, nil
+	}
+
+	switch eviction {
+	case RetryEvictionNone, RetryEvictionOldest, RetryEvictionLowestFee:
+		return eviction, nil
+	}
+
+	return "", fmt.Errorf("invalid RETRY_EVICTION value: %s, must be 'none', 'oldest' or 'lowest_fee'", eviction)
+}
+
+// GetEnvBatchFulfillSize returns the maximum number of intents to the same receiver fulfilled in one transaction
+// from environment variables
+func GetEnvBatchFulfillSize() (int, error) {
+	size := os.Getenv("BATCH_FULFILL_SIZE")
+	if size == "" {
+		return DefaultBatchFulfillSize, nil
+	}
+
+	count, err := strconv.Atoi(size)
+	if err != nil {
+		return 0, fmt.Errorf("invalid BATCH_FULFILL_SIZE value: %s, must be an integer", size)
+	}
+	if count <= 0 {
+		return 0, fmt.Errorf("BATCH_FULFILL_SIZE must be greater than 0")
+	}
+	return count, nil
+}
+
+// getEnvQueueSize returns the positive queue size of the environment variable, or the default if unset
+func getEnvQueueSize(name string, defaultSize int) (int, error) {
+	size := os.Getenv(name)
+	if size == "" {
+		return defaultSize, nil
+	}
+
+	count, err := strconv.Atoi(size)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s value: %s, must be an integer", name, size)
+	}
+	if count <= 0 {
+		return 0, fmt.Errorf("%s must be greater than 0", name)
+	}
+	return count, nil
+}
+
+// GetEnvMaxIntentsPerCycle returns the maximum number of intents evaluated per polling cycle from environment variables
+func GetEnvMaxIntentsPerCycle() (int, error) {
+	maxIntents := os.Getenv("MAX_INTENTS_PER_CYCLE")
+	if maxIntents == "" {
+		return DefaultMaxIntentsPerCycle, nil
+	}
+
+	count, err := strconv.Atoi(maxIntents)
+	if err != nil {
+		return 0, fmt.Errorf("invalid MAX_INTENTS_PER_CYCLE value: %s, must be an integer", maxIntents)
+	}
+	if count <= 0 {
+		return 0, fmt.Errorf("MAX_INTENTS_PER_CYCLE must be greater than 0")
+	}
+	return count, nil
+}
+
+// GetEnvSourceChains returns the chain IDs listed in the source chain filter variable (e.g. ALLOWED_SOURCE_CHAINS=1,8453),
+// or nil if not set
+func GetEnvSourceChains(name string) ([]int, error) {
+	chainsStr := os.Getenv(name)
+	if chainsStr == "" {
+		return nil, nil
+	}
+
+	var chainIDs []int
+	for _, chainStr := range strings.Split(chainsStr, ",") {
+		chainID, err := strconv.Atoi(strings.TrimSpace(chainStr))
+		if err != nil || chainID <= 0 {
+			return nil, fmt.Errorf("invalid %s value: %s, must be a list of chain IDs", name, chainsStr)
+		}
+		chainIDs = append(chainIDs, chainID)
+	}
+	return chainIDs, nil
+}
+
+// GetEnvMetricsHost returns the metrics server bind address from environment variables
+func GetEnvMetricsHost() (string, error) {
+	metricsHost := os.Getenv("METRICS_HOST")
+	if metricsHost == "" {
+		return DefaultMetricsHost, nil
+	}
+
+	// Validate host format, the port is set with METRICS_PORT
+	if strings.Contains(metricsHost, ":") && net.ParseIP(metricsHost) == nil {
+		return "", fmt.Errorf("invalid METRICS_HOST value: %s, must be a host or IP address without port", metricsHost)
+	}
+	return metricsHost, nil
+}
+
 // GetEnvMetricsPort returns the metrics server port from environment variables
 func GetEnvMetricsPort() (string, error) {
 	metricsPort := os.Getenv("METRICS_PORT")
@@ -274,6 +605,61 @@ func GetEnvCircuitBreakerReset() (time.Duration, error) {
 	return parsed, nil
 }
 
+// GetEnvCircuitBreakerWarmup returns the circuit breaker warm-up period from environment variables
+func GetEnvCircuitBreakerWarmup() (time.Duration, error) {
+	warmup := os.Getenv("CIRCUIT_BREAKER_WARMUP")
+	if warmup == "" {
+		return DefaultCircuitBreakerWarmup * time.Second, nil
+	}
+
+	// Validate duration format
+	parsed, err := time.ParseDuration(warmup)
+	if err != nil {
+		return 0, fmt.Errorf("invalid CIRCUIT_BREAKER_WARMUP value: %s, must be a valid duration string", warmup)
+	}
+	if parsed < 0 {
+		return 0, fmt.Errorf("CIRCUIT_BREAKER_WARMUP must not be negative")
+	}
+	return parsed, nil
+}
+
+// GetEnvCircuitBreakerCooldown returns the period of limited concurrency after the circuit breaker closes
+// from environment variables
+func GetEnvCircuitBreakerCooldown() (time.Duration, error) {
+	cooldown := os.Getenv("CIRCUIT_BREAKER_COOLDOWN")
+	if cooldown == "" {
+		return DefaultCircuitBreakerCooldown * time.Second, nil
+	}
+
+	// Validate duration format
+	parsed, err := time.ParseDuration(cooldown)
+	if err != nil {
+		return 0, fmt.Errorf("invalid CIRCUIT_BREAKER_COOLDOWN value: %s, must be a valid duration string", cooldown)
+	}
+	if parsed < 0 {
+		return 0, fmt.Errorf("CIRCUIT_BREAKER_COOLDOWN must not be negative")
+	}
+	return parsed, nil
+}
+
+// GetEnvCircuitBreakerCooldownConcurrency returns the number of intents of a chain processed concurrently
+// during the cooldown from environment variables
+func GetEnvCircuitBreakerCooldownConcurrency() (int, error) {
+	concurrency := os.Getenv("CIRCUIT_BREAKER_COOLDOWN_CONCURRENCY")
+	if concurrency == "" {
+		return DefaultCircuitBreakerCooldownConcurrency, nil
+	}
+
+	concurrencyInt, err := strconv.Atoi(concurrency)
+	if err != nil {
+		return 0, fmt.Errorf("invalid CIRCUIT_BREAKER_COOLDOWN_CONCURRENCY value: %s, must be an integer", concurrency)
+	}
+	if concurrencyInt <= 0 {
+		return 0, fmt.Errorf("CIRCUIT_BREAKER_COOLDOWN_CONCURRENCY must be greater than 0")
+	}
+	return concurrencyInt, nil
+}
+
 // GetEnvMaxRetries returns the maximum number of retries from environment variables
 func GetEnvMaxRetries() (int, error) {
 	maxRetries := os.Getenv("MAX_RETRIES")
@@ -291,6 +677,186 @@ func GetEnvMaxRetries() (int, error) {
 	return maxRetriesInt, nil
 }
 
+// GetEnvRetryMaxAge returns the maximum age of an intent being retried from environment variables, 0 for no limit
+func GetEnvRetryMaxAge() (time.Duration, error) {
+	maxAge := os.Getenv("RETRY_MAX_AGE")
+	if maxAge == "" {
+		return DefaultRetryMaxAge * time.Minute, nil
+	}
+
+	parsed, err := time.ParseDuration(maxAge)
+	if err != nil {
+		return 0, fmt.Errorf("invalid RETRY_MAX_AGE value: %s, must be a valid duration string", maxAge)
+	}
+	if parsed < 0 {
+		return 0, fmt.Errorf("RETRY_MAX_AGE must not be negative")
+	}
+	return parsed, nil
+}
+
+// GetEnvRetryJitter returns whether the backoff of retries is randomized from environment variables
+func GetEnvRetryJitter() (bool, error) {
+	jitter := os.Getenv("RETRY_JITTER")
+	if jitter == "" {
+		return DefaultRetryJitter, nil
+	}
+
+	switch jitter {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+
+	return false, fmt.Errorf("invalid RETRY_JITTER value: %s, must be 'true' or 'false'", jitter)
+}
+
+// DefaultRetryPolicy is the retry policy of error types without a specific policy
+var DefaultRetryPolicy = RetryPolicy{MaxRetries: 3, Backoff: 10 * time.Second, MaxBackoff: 2 * time.Minute}
+
+// DefaultRetryPolicies defines the retry policies of the retryable error types
+// network errors are retried fast while node state and gas errors need time to resolve
+var DefaultRetryPolicies = map[string]RetryPolicy{
+	"network_error":    {MaxRetries: 5, Backoff: 5 * time.Second, MaxBackoff: time.Minute},
+	"node_state_error": {MaxRetries: 3, Backoff: 30 * time.Second, MaxBackoff: 5 * time.Minute},
+	"gas_error":        {MaxRetries: 3, Backoff: 30 * time.Second, MaxBackoff: 5 * time.Minute},
+	"nonce_error":      {MaxRetries: 3, Backoff: 5 * time.Second, MaxBackoff: time.Minute},
+	"timeout":          DefaultRetryPolicy,
+	"unknown_error":    DefaultRetryPolicy,
+}
+
+// GetEnvRetryPolicies returns the retry policies of the error types in DefaultRetryPolicies,
+// using env overrides RETRY_<TYPE>_MAX_RETRIES, RETRY_<TYPE>_BACKOFF and RETRY_<TYPE>_MAX_BACKOFF
+// (e.g. RETRY_NETWORK_ERROR_BACKOFF=5s), the default max retries of the error types are capped at maxRetries
+// (MAX_RETRIES) while RETRY_<TYPE>_MAX_RETRIES can set any limit
+func GetEnvRetryPolicies(maxRetries int) (map[string]RetryPolicy, error) {
+	policies := make(map[string]RetryPolicy, len(DefaultRetryPolicies))
+	for errorType, policy := range DefaultRetryPolicies {
+		prefix := "RETRY_" + strings.ToUpper(errorType)
+
+		policy.MaxRetries = min(policy.MaxRetries, maxRetries)
+		if val := os.Getenv(prefix + "_MAX_RETRIES"); val != "" {
+			maxRetries, err := strconv.Atoi(val)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s_MAX_RETRIES value: %s, must be an integer", prefix, val)
+			}
+			if maxRetries < 0 {
+				return nil, fmt.Errorf("%s_MAX_RETRIES must be greater than or equal to 0", prefix)
+			}
+			policy.MaxRetries = maxRetries
+		}
+
+		if val := os.Getenv(prefix + "_BACKOFF"); val != "" {
+			backoff, err := time.ParseDuration(val)
+			if err != nil || backoff <= 0 {
+				return nil, fmt.Errorf("invalid %s_BACKOFF value: %s, must be a positive duration string", prefix, val)
+			}
+			policy.Backoff = backoff
+		}
+
+		if val := os.Getenv(prefix + "_MAX_BACKOFF"); val != "" {
+			maxBackoff, err := time.ParseDuration(val)
+			if err != nil || maxBackoff <= 0 {
+				return nil, fmt.Errorf("invalid %s_MAX_BACKOFF value: %s, must be a positive duration string", prefix, val)
+			}
+			policy.MaxBackoff = maxBackoff
+		}
+
+		if policy.MaxBackoff < policy.Backoff {
+			return nil, fmt.Errorf("%s_MAX_BACKOFF must be greater than or equal to %s_BACKOFF", prefix, prefix)
+		}
+		policies[errorType] = policy
+	}
+	return policies, nil
+}
+
+// GetEnvFulfillTimeout returns the timeout of a single intent fulfillment from environment variables
+func GetEnvFulfillTimeout() (time.Duration, error) {
+	timeout := os.Getenv("FULFILL_TIMEOUT")
+	if timeout == "" {
+		return DefaultFulfillTimeout * time.Second, nil
+	}
+
+	// Validate duration format
+	parsed, err := time.ParseDuration(timeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid FULFILL_TIMEOUT value: %s, must be a valid duration string", timeout)
+	}
+	if parsed <= 0 {
+		return 0, fmt.Errorf("FULFILL_TIMEOUT must be greater than 0")
+	}
+	return parsed, nil
+}
+
+// GetEnvGasBumpPercent returns the gas price increase in percent per retry from environment variables
+func GetEnvGasBumpPercent() (float64, error) {
+	bump := os.Getenv("GAS_BUMP_PERCENT")
+	if bump == "" {
+		return DefaultGasBumpPercent, nil
+	}
+
+	bumpFloat, err := strconv.ParseFloat(bump, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid GAS_BUMP_PERCENT value: %s, must be a number", bump)
+	}
+	if bumpFloat < 0 {
+		return 0, fmt.Errorf("GAS_BUMP_PERCENT must be greater than or equal to 0")
+	}
+	return bumpFloat, nil
+}
+
+// GetEnvFeeSafetyMargin returns the multiplier applied to the withdraw fee in the profitability check from environment variables
+func GetEnvFeeSafetyMargin() (float64, error) {
+	margin := os.Getenv("FEE_SAFETY_MARGIN")
+	if margin == "" {
+		return DefaultFeeSafetyMargin, nil
+	}
+
+	marginFloat, err := strconv.ParseFloat(margin, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid FEE_SAFETY_MARGIN value: %s, must be a number", margin)
+	}
+	if marginFloat < 1 {
+		return 0, fmt.Errorf("FEE_SAFETY_MARGIN must be greater than or equal to 1")
+	}
+	return marginFloat, nil
+}
+
+// GetEnvGasUsedAlertRatio returns the ratio of the gas used by a fulfillment to the withdraw gas estimate
+// above which an anomaly is reported from environment variables
+func GetEnvGasUsedAlertRatio() (float64, error) {
+	ratio := os.Getenv("GAS_USED_ALERT_RATIO")
+	if ratio == "" {
+		return DefaultGasUsedAlertRatio, nil
+	}
+
+	ratioFloat, err := strconv.ParseFloat(ratio, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid GAS_USED_ALERT_RATIO value: %s, must be a number", ratio)
+	}
+	if ratioFloat != 0 && ratioFloat < 1 {
+		return 0, fmt.Errorf("GAS_USED_ALERT_RATIO must be 0 or greater than or equal to 1")
+	}
+	return ratioFloat, nil
+}
+
+// GetEnvMaxExposureUSD returns the maximum USD value committed to intents in flight from environment variables
+func GetEnvMaxExposureUSD() (float64, error) {
+	maxExposure := os.Getenv("MAX_EXPOSURE_USD")
+	if maxExposure == "" {
+		return DefaultMaxExposureUSD, nil
+	}
+
+	maxExposureFloat, err := strconv.ParseFloat(maxExposure, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid MAX_EXPOSURE_USD value: %s, must be a number", maxExposure)
+	}
+	if maxExposureFloat < 0 {
+		return 0, fmt.Errorf("MAX_EXPOSURE_USD must be greater than or equal to 0")
+	}
+	return maxExposureFloat, nil
+}
+
 // GetEnvMaxGasPrice returns the maximum gas price from environment variables
 func GetEnvMaxGasPrice() (*big.Int, error) {
 	maxGasPrice := os.Getenv("MAX_GAS_PRICE")
@@ -323,11 +889,107 @@ func GetEnvAPIEndpoint() (string, error) {
 	return apiEndpoint, nil
 }
 
+// GetEnvAPIIntentsPath returns the path and query of pending intents relative to the API endpoint
+func GetEnvAPIIntentsPath() (string, error) {
+	path := os.Getenv("API_INTENTS_PATH")
+	if path == "" {
+		return DefaultAPIIntentsPath, nil
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		return "", fmt.Errorf("invalid API_INTENTS_PATH value: %s, must start with /", path)
+	}
+	if _, err := url.ParseRequestURI(path); err != nil {
+		return "", fmt.Errorf("invalid API_INTENTS_PATH value: %s, must be a valid path", path)
+	}
+	return path, nil
+}
+
 // GetEnvMetricsAPIKey returns the API key required to access metrics, or empty if not set
 func GetEnvMetricsAPIKey() string {
 	return os.Getenv("METRICS_API_KEY")
 }
 
+// GetEnvIntentABIPath returns the path of the Intent contract ABI file, or empty to use the baked-in ABI
+func GetEnvIntentABIPath() (string, error) {
+	path := os.Getenv("INTENT_ABI_PATH")
+	if path == "" {
+		return "", nil
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("invalid INTENT_ABI_PATH value: %s, %v", path, err)
+	}
+	return path, nil
+}
+
+// GetEnvIntentFulfillMethod returns the name of the fulfill method of the Intent contract
+func GetEnvIntentFulfillMethod() string {
+	method := os.Getenv("INTENT_FULFILL_METHOD")
+	if method == "" {
+		return DefaultIntentFulfillMethod
+	}
+	return method
+}
+
+// GetEnvSignerType returns the signer type from environment variables
+func GetEnvSignerType() (string, error) {
+	signerType := os.Getenv("SIGNER_TYPE")
+	if signerType == "" {
+		return DefaultSignerType, nil
+	}
+
+	switch signerType {
+	case SignerTypeLocal, SignerTypeKeystore, SignerTypeClef, SignerTypeWeb3Signer:
+		return signerType, nil
+	}
+
+	return "", fmt.Errorf("invalid SIGNER_TYPE value: %s, must be 'local', 'keystore', 'clef' or 'web3signer'", signerType)
+}
+
+// GetEnvSignerURL returns the remote signer endpoint from environment variables
+func GetEnvSignerURL() (string, error) {
+	signerURL := os.Getenv("SIGNER_URL")
+	if signerURL == "" {
+		return "", nil
+	}
+
+	// Validate URL format
+	if _, err := url.ParseRequestURI(signerURL); err != nil {
+		return "", fmt.Errorf("invalid SIGNER_URL value: %s, must be a valid URL", signerURL)
+	}
+	return signerURL, nil
+}
+
+// GetEnvSignerAddress returns the address of the remote signer account from environment variables
+func GetEnvSignerAddress() (string, error) {
+	signerAddress := os.Getenv("SIGNER_ADDRESS")
+	if signerAddress == "" {
+		return "", nil
+	}
+
+	// Validate Ethereum address format
+	if !common.IsHexAddress(signerAddress) {
+		return "", fmt.Errorf("invalid SIGNER_ADDRESS value: %s, must be a valid Ethereum address", signerAddress)
+	}
+	return signerAddress, nil
+}
+
+// GetEnvKeystorePassword returns the keystore password from KEYSTORE_PASSWORD,
+// or reads it from the file at KEYSTORE_PASSWORD_FILE if set
+func GetEnvKeystorePassword() (string, error) {
+	passwordFile := os.Getenv("KEYSTORE_PASSWORD_FILE")
+	if passwordFile == "" {
+		return os.Getenv("KEYSTORE_PASSWORD"), nil
+	}
+
+	content, err := os.ReadFile(passwordFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read KEYSTORE_PASSWORD_FILE %s: %v", passwordFile, err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
 // GetEnvChainGasMultiplier returns CHAIN_<ID>_GAS_MULTIPLIER if set, otherwise a sane default (1.1)
 func GetEnvChainGasMultiplier(chainID int) (float64, error) {
 	gasMultiplierStr := os.Getenv(fmt.Sprintf("CHAIN_%d_GAS_MULTIPLIER", chainID))
@@ -344,6 +1006,648 @@ func GetEnvChainGasMultiplier(chainID int) (float64, error) {
 	return parsedMultiplier, nil
 }
 
+// GetEnvChainApprovalGasMultiplier returns CHAIN_<ID>_APPROVAL_GAS_MULTIPLIER, the multiplier applied on the
+// fulfill gas price for approval transactions, if set, otherwise DefaultApprovalGasMultiplier
+func GetEnvChainApprovalGasMultiplier(chainID int) (float64, error) {
+	multiplierStr := os.Getenv(fmt.Sprintf("CHAIN_%d_APPROVAL_GAS_MULTIPLIER", chainID))
+	if multiplierStr == "" {
+		return DefaultApprovalGasMultiplier, nil
+	}
+	parsedMultiplier, err := strconv.ParseFloat(multiplierStr, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid CHAIN_%d_APPROVAL_GAS_MULTIPLIER value: %s", chainID, multiplierStr)
+	}
+	if parsedMultiplier <= 0 {
+		return 0, fmt.Errorf("CHAIN_%d_APPROVAL_GAS_MULTIPLIER must be greater than 0", chainID)
+	}
+	return parsedMultiplier, nil
+}
+
+// GetEnvChainBaseFeeMultiplier returns CHAIN_<ID>_BASEFEE_MULTIPLIER, the multiplier of the base fee in the fee cap
+// of dynamic fee transactions, if set, otherwise DefaultBaseFeeMultiplier
+func GetEnvChainBaseFeeMultiplier(chainID int) (float64, error) {
+	multiplierStr := os.Getenv(fmt.Sprintf("CHAIN_%d_BASEFEE_MULTIPLIER", chainID))
+	if multiplierStr == "" {
+		return DefaultBaseFeeMultiplier, nil
+	}
+	parsedMultiplier, err := strconv.ParseFloat(multiplierStr, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid CHAIN_%d_BASEFEE_MULTIPLIER value: %s", chainID, multiplierStr)
+	}
+	if parsedMultiplier < 1 {
+		return 0, fmt.Errorf("CHAIN_%d_BASEFEE_MULTIPLIER must be at least 1", chainID)
+	}
+	return parsedMultiplier, nil
+}
+
+// GetEnvUseAccessList returns whether fulfill transactions are sent with an access list from environment variables
+func GetEnvUseAccessList() (bool, error) {
+	useAccessList := os.Getenv("USE_ACCESS_LIST")
+	if useAccessList == "" {
+		return DefaultUseAccessList, nil
+	}
+
+	switch useAccessList {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+
+	return false, fmt.Errorf("invalid USE_ACCESS_LIST value: %s, must be 'true' or 'false'", useAccessList)
+}
+
+// GetEnvRPCReconnectFailures returns the number of consecutive failed RPC health checks before reconnecting
+func GetEnvRPCReconnectFailures() (int, error) {
+	failures := os.Getenv("RPC_RECONNECT_FAILURES")
+	if failures == "" {
+		return DefaultRPCReconnectFailures, nil
+	}
+
+	count, err := strconv.Atoi(failures)
+	if err != nil {
+		return 0, fmt.Errorf("invalid RPC_RECONNECT_FAILURES value: %s, must be an integer", failures)
+	}
+	if count <= 0 {
+		return 0, fmt.Errorf("RPC_RECONNECT_FAILURES must be greater than 0")
+	}
+	return count, nil
+}
+
+// GetEnvRPCReconnectMaxBackoff returns the maximum delay between RPC reconnection attempts
+func GetEnvRPCReconnectMaxBackoff() (time.Duration, error) {
+	backoff := os.Getenv("RPC_RECONNECT_MAX_BACKOFF")
+	if backoff == "" {
+		return DefaultRPCReconnectMaxBackoff * time.Second, nil
+	}
+
+	// Validate duration format
+	parsed, err := time.ParseDuration(backoff)
+	if err != nil {
+		return 0, fmt.Errorf("invalid RPC_RECONNECT_MAX_BACKOFF value: %s, must be a valid duration string", backoff)
+	}
+	if parsed <= 0 {
+		return 0, fmt.Errorf("RPC_RECONNECT_MAX_BACKOFF must be greater than 0")
+	}
+	return parsed, nil
+}
+
+// GetEnvChainConnectConcurrency returns the number of chains connected to concurrently at startup from environment variables
+func GetEnvChainConnectConcurrency() (int, error) {
+	concurrency := os.Getenv("CHAIN_CONNECT_CONCURRENCY")
+	if concurrency == "" {
+		return DefaultChainConnectConcurrency, nil
+	}
+
+	count, err := strconv.Atoi(concurrency)
+	if err != nil {
+		return 0, fmt.Errorf("invalid CHAIN_CONNECT_CONCURRENCY value: %s, must be an integer", concurrency)
+	}
+	if count <= 0 {
+		return 0, fmt.Errorf("CHAIN_CONNECT_CONCURRENCY must be greater than 0")
+	}
+	return count, nil
+}
+
+// GetEnvMetricsConcurrency returns the number of chains whose metrics are updated concurrently from environment variables
+func GetEnvMetricsConcurrency() (int, error) {
+	concurrency := os.Getenv("METRICS_CONCURRENCY")
+	if concurrency == "" {
+		return DefaultMetricsConcurrency, nil
+	}
+
+	count, err := strconv.Atoi(concurrency)
+	if err != nil {
+		return 0, fmt.Errorf("invalid METRICS_CONCURRENCY value: %s, must be an integer", concurrency)
+	}
+	if count <= 0 {
+		return 0, fmt.Errorf("METRICS_CONCURRENCY must be greater than 0")
+	}
+	return count, nil
+}
+
+// GetEnvMaxConcurrentRPC returns the maximum number of concurrent RPC calls across all chains, 0 for no limit
+func GetEnvMaxConcurrentRPC() (int, error) {
+	maxConcurrent := os.Getenv("MAX_CONCURRENT_RPC")
+	if maxConcurrent == "" {
+		return DefaultMaxConcurrentRPC, nil
+	}
+
+	count, err := strconv.Atoi(maxConcurrent)
+	if err != nil {
+		return 0, fmt.Errorf("invalid MAX_CONCURRENT_RPC value: %s, must be an integer", maxConcurrent)
+	}
+	if count < 0 {
+		return 0, fmt.Errorf("MAX_CONCURRENT_RPC must not be negative")
+	}
+	return count, nil
+}
+
+// GetEnvFulfilledLogPath returns the path of the fulfilled intent log file, or empty if disabled
+func GetEnvFulfilledLogPath() string {
+	return os.Getenv("FULFILLED_LOG_PATH")
+}
+
+// GetEnvRetryQueuePath returns the path of the file persisting the retry queue across restarts, or empty if disabled
+func GetEnvRetryQueuePath() string {
+	return os.Getenv("RETRY_QUEUE_PATH")
+}
+
+// GetEnvFulfillmentLogPath returns the path of the fulfillment export file, or empty if disabled
+func GetEnvFulfillmentLogPath() string {
+	return os.Getenv("FULFILLMENT_LOG_PATH")
+}
+
+// GetEnvFulfillmentLogFormat returns the format of the fulfillment export from environment variables
+func GetEnvFulfillmentLogFormat() (string, error) {
+	format := os.Getenv("FULFILLMENT_LOG_FORMAT")
+	if format == "" {
+		return DefaultFulfillmentLogFormat, nil
+	}
+
+	switch format {
+	case FulfillmentLogFormatJSON, FulfillmentLogFormatCSV:
+		return format, nil
+	}
+
+	return "", fmt.Errorf("invalid FULFILLMENT_LOG_FORMAT value: %s, must be 'json' or 'csv'", format)
+}
+
+// GetEnvFulfilledLogTTL returns the time a fulfilled intent is kept in the fulfilled intent log
+func GetEnvFulfilledLogTTL() (time.Duration, error) {
+	ttl := os.Getenv("FULFILLED_LOG_TTL")
+	if ttl == "" {
+		return DefaultFulfilledLogTTL * time.Second, nil
+	}
+
+	parsed, err := time.ParseDuration(ttl)
+	if err != nil {
+		return 0, fmt.Errorf("invalid FULFILLED_LOG_TTL value: %s, must be a valid duration string", ttl)
+	}
+	if parsed <= 0 {
+		return 0, fmt.Errorf("FULFILLED_LOG_TTL must be greater than 0")
+	}
+	return parsed, nil
+}
+
+// GetEnvAllowSameChain returns whether intents with the same source and destination chain are fulfilled
+func GetEnvAllowSameChain() (bool, error) {
+	allow := os.Getenv("ALLOW_SAME_CHAIN")
+	if allow == "" {
+		return DefaultAllowSameChain, nil
+	}
+
+	switch allow {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+
+	return false, fmt.Errorf("invalid ALLOW_SAME_CHAIN value: %s, must be 'true' or 'false'", allow)
+}
+
+// GetEnvIntentClaiming returns whether intents are claimed through the API before fulfillment
+func GetEnvIntentClaiming() (bool, error) {
+	claiming := os.Getenv("INTENT_CLAIMING")
+	if claiming == "" {
+		return DefaultIntentClaiming, nil
+	}
+
+	switch claiming {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+
+	return false, fmt.Errorf("invalid INTENT_CLAIMING value: %s, must be 'true' or 'false'", claiming)
+}
+
+// GetEnvVerifyIntentStatus returns whether the status of intents is checked through the API before fulfillment
+func GetEnvVerifyIntentStatus() (bool, error) {
+	verify := os.Getenv("VERIFY_INTENT_STATUS")
+	if verify == "" {
+		return DefaultVerifyIntentStatus, nil
+	}
+
+	switch verify {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+
+	return false, fmt.Errorf("invalid VERIFY_INTENT_STATUS value: %s, must be 'true' or 'false'", verify)
+}
+
+// GetEnvReportFulfillment returns whether fulfillment transactions are reported to the API
+func GetEnvReportFulfillment() (bool, error) {
+	report := os.Getenv("REPORT_FULFILLMENT")
+	if report == "" {
+		return DefaultReportFulfillment, nil
+	}
+
+	switch report {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+
+	return false, fmt.Errorf("invalid REPORT_FULFILLMENT value: %s, must be 'true' or 'false'", report)
+}
+
+// GetEnvLogTxDetailsOnError returns whether the parameters of failed fulfill transactions are logged
+func GetEnvLogTxDetailsOnError() (bool, error) {
+	logDetails := os.Getenv("LOG_TX_DETAILS_ON_ERROR")
+	if logDetails == "" {
+		return DefaultLogTxDetailsOnError, nil
+	}
+
+	switch logDetails {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+
+	return false, fmt.Errorf("invalid LOG_TX_DETAILS_ON_ERROR value: %s, must be 'true' or 'false'", logDetails)
+}
+
+// GetEnvPriceRequestRetries returns the number of retries of a failed token price request from environment variables
+func GetEnvPriceRequestRetries() (int, error) {
+	retries := os.Getenv("PRICE_REQUEST_RETRIES")
+	if retries == "" {
+		return DefaultPriceRequestRetries, nil
+	}
+
+	count, err := strconv.Atoi(retries)
+	if err != nil {
+		return 0, fmt.Errorf("invalid PRICE_REQUEST_RETRIES value: %s, must be an integer", retries)
+	}
+	if count < 0 {
+		return 0, fmt.Errorf("PRICE_REQUEST_RETRIES must not be negative")
+	}
+	return count, nil
+}
+
+// GetEnvPriceRequestBackoff returns the delay before the first retry of a token price request from environment variables
+func GetEnvPriceRequestBackoff() (time.Duration, error) {
+	backoff := os.Getenv("PRICE_REQUEST_BACKOFF")
+	if backoff == "" {
+		return DefaultPriceRequestBackoff * time.Second, nil
+	}
+
+	parsed, err := time.ParseDuration(backoff)
+	if err != nil {
+		return 0, fmt.Errorf("invalid PRICE_REQUEST_BACKOFF value: %s, must be a valid duration string", backoff)
+	}
+	if parsed <= 0 {
+		return 0, fmt.Errorf("PRICE_REQUEST_BACKOFF must be greater than 0")
+	}
+	return parsed, nil
+}
+
+// GetEnvNonceSyncRetries returns the number of retries of a failed account nonce request from environment variables
+func GetEnvNonceSyncRetries() (int, error) {
+	retries := os.Getenv("NONCE_SYNC_RETRIES")
+	if retries == "" {
+		return DefaultNonceSyncRetries, nil
+	}
+
+	count, err := strconv.Atoi(retries)
+	if err != nil {
+		return 0, fmt.Errorf("invalid NONCE_SYNC_RETRIES value: %s, must be an integer", retries)
+	}
+	if count < 0 {
+		return 0, fmt.Errorf("NONCE_SYNC_RETRIES must not be negative")
+	}
+	return count, nil
+}
+
+// GetEnvNonceSyncBackoff returns the delay before the first retry of an account nonce request from environment variables
+func GetEnvNonceSyncBackoff() (time.Duration, error) {
+	backoff := os.Getenv("NONCE_SYNC_BACKOFF")
+	if backoff == "" {
+		return DefaultNonceSyncBackoff * time.Millisecond, nil
+	}
+
+	parsed, err := time.ParseDuration(backoff)
+	if err != nil {
+		return 0, fmt.Errorf("invalid NONCE_SYNC_BACKOFF value: %s, must be a valid duration string", backoff)
+	}
+	if parsed <= 0 {
+		return 0, fmt.Errorf("NONCE_SYNC_BACKOFF must be greater than 0")
+	}
+	return parsed, nil
+}
+
+// GetEnvPriceRequestCoalescing returns whether concurrent token price requests are coalesced from environment variables
+func GetEnvPriceRequestCoalescing() (bool, error) {
+	coalescing := os.Getenv("PRICE_REQUEST_COALESCING")
+	if coalescing == "" {
+		return DefaultPriceRequestCoalescing, nil
+	}
+
+	switch coalescing {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+
+	return false, fmt.Errorf("invalid PRICE_REQUEST_COALESCING value: %s, must be 'true' or 'false'", coalescing)
+}
+
+// GetEnvChainGasSource returns CHAIN_<ID>_GAS_SOURCE if set, otherwise the default gas source
+func GetEnvChainGasSource(chainID int) (string, error) {
+	gasSource := os.Getenv(fmt.Sprintf("CHAIN_%d_GAS_SOURCE", chainID))
+	if gasSource == "" {
+		return DefaultGasSource, nil
+	}
+
+	switch gasSource {
+	case GasSourceSuggested, GasSourceFeeHistory:
+		return gasSource, nil
+	}
+
+	return "", fmt.Errorf("invalid CHAIN_%d_GAS_SOURCE value: %s, must be 'suggested' or 'feehistory'", chainID, gasSource)
+}
+
+// GetEnvChainGasPercentile returns CHAIN_<ID>_GAS_PERCENTILE if set, otherwise the default percentile
+func GetEnvChainGasPercentile(chainID int) (float64, error) {
+	percentileStr := os.Getenv(fmt.Sprintf("CHAIN_%d_GAS_PERCENTILE", chainID))
+	if percentileStr == "" {
+		return DefaultGasPercentile, nil
+	}
+	percentile, err := strconv.ParseFloat(percentileStr, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid CHAIN_%d_GAS_PERCENTILE value: %s", chainID, percentileStr)
+	}
+	if percentile < 0 || percentile > 100 {
+		return 0, fmt.Errorf("CHAIN_%d_GAS_PERCENTILE must be between 0 and 100", chainID)
+	}
+	return percentile, nil
+}
+
+// GetEnvChainTokenDecimals returns CHAIN_<ID>_<TOKEN>_DECIMALS if set, otherwise 0
+func GetEnvChainTokenDecimals(chainID int, token string) (int, error) {
+	name := fmt.Sprintf("CHAIN_%d_%s_DECIMALS", chainID, strings.ToUpper(token))
+	decimalsStr := os.Getenv(name)
+	if decimalsStr == "" {
+		return 0, nil
+	}
+	decimals, err := strconv.Atoi(decimalsStr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s value: %s", name, decimalsStr)
+	}
+	if decimals < 1 || decimals > 77 {
+		return 0, fmt.Errorf("%s must be between 1 and 77", name)
+	}
+	return decimals, nil
+}
+
+// GetEnvTokenDecimals returns the CHAIN_<ID>_USDC_DECIMALS and CHAIN_<ID>_USDT_DECIMALS overrides of the chains
+// by chain ID and token type, the chains without override are omitted
+func GetEnvTokenDecimals(chainIDs []int) (map[int]map[string]int, error) {
+	overrides := make(map[int]map[string]int)
+	for _, chainID := range chainIDs {
+		for _, tokenType := range customTokenTypes {
+			decimals, err := GetEnvChainTokenDecimals(chainID, tokenType)
+			if err != nil {
+				return nil, err
+			}
+			if decimals == 0 {
+				continue
+			}
+			if overrides[chainID] == nil {
+				overrides[chainID] = make(map[string]int)
+			}
+			overrides[chainID][tokenType] = decimals
+		}
+	}
+	return overrides, nil
+}
+
+// GetEnvChainMinFeeUSD returns CHAIN_<ID>_MIN_FEE_USD if set, otherwise 0 to use the min fee in base units
+func GetEnvChainMinFeeUSD(chainID int) (float64, error) {
+	minFeeStr := os.Getenv(fmt.Sprintf("CHAIN_%d_MIN_FEE_USD", chainID))
+	if minFeeStr == "" {
+		return 0, nil
+	}
+	minFee, err := strconv.ParseFloat(minFeeStr, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid CHAIN_%d_MIN_FEE_USD value: %s", chainID, minFeeStr)
+	}
+	if minFee < 0 {
+		return 0, fmt.Errorf("CHAIN_%d_MIN_FEE_USD must not be negative", chainID)
+	}
+	return minFee, nil
+}
+
+// allowedTokenTypes are the token types accepted in CHAIN_<ID>_ALLOWED_TOKENS, matching chains.TokenType
+var allowedTokenTypes = map[string]bool{"USDC": true, "USDT": true, "NATIVE": true}
+
+// GetEnvChainAllowedTokens returns the token types listed in CHAIN_<ID>_ALLOWED_TOKENS (e.g. USDC,NATIVE),
+// or nil if not set to allow all tokens
+func GetEnvChainAllowedTokens(chainID int) ([]string, error) {
+	name := fmt.Sprintf("CHAIN_%d_ALLOWED_TOKENS", chainID)
+	tokensStr := os.Getenv(name)
+	if tokensStr == "" {
+		return nil, nil
+	}
+
+	var tokens []string
+	for _, token := range strings.Split(tokensStr, ",") {
+		token = strings.ToUpper(strings.TrimSpace(token))
+		if !allowedTokenTypes[token] {
+			return nil, fmt.Errorf("invalid %s value: %s, must be a list of USDC, USDT or NATIVE", name, tokensStr)
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+// GetEnvChainIntentAddresses returns the additional Intent contract addresses listed in CHAIN_<ID>_INTENT_ADDRESSES,
+// or nil if not set to only fulfill through the chain intent address
+func GetEnvChainIntentAddresses(chainID int) ([]string, error) {
+	name := fmt.Sprintf("CHAIN_%d_INTENT_ADDRESSES", chainID)
+	addressesStr := os.Getenv(name)
+	if addressesStr == "" {
+		return nil, nil
+	}
+
+	var addresses []string
+	for _, address := range strings.Split(addressesStr, ",") {
+		address = strings.TrimSpace(address)
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("invalid %s address: %s", name, address)
+		}
+		addresses = append(addresses, common.HexToAddress(address).Hex())
+	}
+	return addresses, nil
+}
+
+// ReceiverOverrideAllTokens is the CHAIN_<ID>_RECEIVER_OVERRIDES key applying to every token type
+const ReceiverOverrideAllTokens = "*"
+
+// GetEnvChainReceiverOverrides returns the receiver addresses listed in CHAIN_<ID>_RECEIVER_OVERRIDES by token type
+// (e.g. USDC:0x...,*:0x...), or nil if not set to send to the intent recipient
+func GetEnvChainReceiverOverrides(chainID int) (map[string]common.Address, error) {
+	name := fmt.Sprintf("CHAIN_%d_RECEIVER_OVERRIDES", chainID)
+	overridesStr := os.Getenv(name)
+	if overridesStr == "" {
+		return nil, nil
+	}
+
+	overrides := make(map[string]common.Address)
+	for _, entry := range strings.Split(overridesStr, ",") {
+		token, address, found := strings.Cut(strings.TrimSpace(entry), ":")
+		token = strings.ToUpper(strings.TrimSpace(token))
+		address = strings.TrimSpace(address)
+		if !found || (token != ReceiverOverrideAllTokens && !allowedTokenTypes[token]) {
+			return nil, fmt.Errorf("invalid %s entry: %s, must be <USDC|USDT|NATIVE|*>:<address>", name, entry)
+		}
+		if !common.IsHexAddress(address) || common.HexToAddress(address) == (common.Address{}) {
+			return nil, fmt.Errorf("invalid %s address for %s: %s", name, token, address)
+		}
+		overrides[token] = common.HexToAddress(address)
+	}
+	return overrides, nil
+}
+
+// GetEnvChainPriceID returns CHAIN_<ID>_PRICE_ID, the CoinGecko API id of the gas token, or empty to use the built-in id
+func GetEnvChainPriceID(chainID int) string {
+	return strings.TrimSpace(os.Getenv(fmt.Sprintf("CHAIN_%d_PRICE_ID", chainID)))
+}
+
+// GetEnvChainReadRPCURL returns CHAIN_<ID>_READ_RPC_URL, the endpoint state reads and gas estimations are sent to,
+// or empty to use the RPC
+func GetEnvChainReadRPCURL(chainID int) string {
+	return os.Getenv(fmt.Sprintf("CHAIN_%d_READ_RPC_URL", chainID))
+}
+
+// GetEnvChainSubmitRPCURL returns CHAIN_<ID>_SUBMIT_RPC_URL, the endpoint transactions are sent to, or empty to use the RPC
+func GetEnvChainSubmitRPCURL(chainID int) string {
+	return os.Getenv(fmt.Sprintf("CHAIN_%d_SUBMIT_RPC_URL", chainID))
+}
+
+// GetEnvChainMaxPendingTx returns CHAIN_<ID>_MAX_PENDING_TX if set, otherwise DefaultMaxPendingTx
+func GetEnvChainMaxPendingTx(chainID int) (uint64, error) {
+	maxPendingStr := os.Getenv(fmt.Sprintf("CHAIN_%d_MAX_PENDING_TX", chainID))
+	if maxPendingStr == "" {
+		return DefaultMaxPendingTx, nil
+	}
+	maxPending, err := strconv.ParseUint(maxPendingStr, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid CHAIN_%d_MAX_PENDING_TX value: %s", chainID, maxPendingStr)
+	}
+	if maxPending == 0 {
+		return 0, fmt.Errorf("CHAIN_%d_MAX_PENDING_TX must be greater than 0", chainID)
+	}
+	return maxPending, nil
+}
+
+// GetEnvChainProcessingInterval returns CHAIN_<ID>_PROCESSING_INTERVAL if set, otherwise 0 to process the chain every poll
+func GetEnvChainProcessingInterval(chainID int) (time.Duration, error) {
+	intervalStr := os.Getenv(fmt.Sprintf("CHAIN_%d_PROCESSING_INTERVAL", chainID))
+	if intervalStr == "" {
+		return 0, nil
+	}
+	interval, err := time.ParseDuration(intervalStr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid CHAIN_%d_PROCESSING_INTERVAL value: %s, must be a valid duration string", chainID, intervalStr)
+	}
+	if interval < 0 {
+		return 0, fmt.Errorf("CHAIN_%d_PROCESSING_INTERVAL must not be negative", chainID)
+	}
+	return interval, nil
+}
+
+// GetEnvChainMaxConcurrentRPC returns CHAIN_<ID>_MAX_CONCURRENT_RPC if set, otherwise 0 for no limit
+func GetEnvChainMaxConcurrentRPC(chainID int) (int, error) {
+	maxConcurrentStr := os.Getenv(fmt.Sprintf("CHAIN_%d_MAX_CONCURRENT_RPC", chainID))
+	if maxConcurrentStr == "" {
+		return 0, nil
+	}
+	maxConcurrent, err := strconv.Atoi(maxConcurrentStr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid CHAIN_%d_MAX_CONCURRENT_RPC value: %s", chainID, maxConcurrentStr)
+	}
+	if maxConcurrent < 0 {
+		return 0, fmt.Errorf("CHAIN_%d_MAX_CONCURRENT_RPC must not be negative", chainID)
+	}
+	return maxConcurrent, nil
+}
+
+// GetEnvChainConfirmations returns CHAIN_<ID>_CONFIRMATIONS if set, otherwise DefaultConfirmations
+func GetEnvChainConfirmations(chainID int) (uint64, error) {
+	confirmationsStr := os.Getenv(fmt.Sprintf("CHAIN_%d_CONFIRMATIONS", chainID))
+	if confirmationsStr == "" {
+		return DefaultConfirmations, nil
+	}
+	confirmations, err := strconv.ParseUint(confirmationsStr, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid CHAIN_%d_CONFIRMATIONS value: %s", chainID, confirmationsStr)
+	}
+	if confirmations == 0 {
+		return 0, fmt.Errorf("CHAIN_%d_CONFIRMATIONS must be greater than 0", chainID)
+	}
+	return confirmations, nil
+}
+
+// GetEnvChainReceiptPollInterval returns CHAIN_<ID>_RECEIPT_POLL_INTERVAL if set, otherwise DefaultReceiptPollInterval
+func GetEnvChainReceiptPollInterval(chainID int) (time.Duration, error) {
+	intervalStr := os.Getenv(fmt.Sprintf("CHAIN_%d_RECEIPT_POLL_INTERVAL", chainID))
+	if intervalStr == "" {
+		return DefaultReceiptPollInterval, nil
+	}
+	interval, err := time.ParseDuration(intervalStr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid CHAIN_%d_RECEIPT_POLL_INTERVAL value: %s, must be a valid duration string", chainID, intervalStr)
+	}
+	if interval <= 0 {
+		return 0, fmt.Errorf("CHAIN_%d_RECEIPT_POLL_INTERVAL must be greater than 0", chainID)
+	}
+	return interval, nil
+}
+
+// GetEnvChainReorgCheckDepth returns the number of blocks after which fulfillments are re-checked for reorgs,
+// using env override CHAIN_<ID>_REORG_CHECK_DEPTH, otherwise the depth of the chain registry
+func GetEnvChainReorgCheckDepth(chainID int) (uint64, error) {
+	if val := os.Getenv(fmt.Sprintf("CHAIN_%d_REORG_CHECK_DEPTH", chainID)); val != "" {
+		parsed, err := strconv.ParseUint(val, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid CHAIN_%d_REORG_CHECK_DEPTH value: %s", chainID, val)
+		}
+		return parsed, nil
+	}
+	if chain, ok := registry.Chains.Get(chainID); ok {
+		return chain.ReorgCheckDepth, nil
+	}
+	return DefaultReorgCheckDepth, nil
+}
+
+// GetEnvChainWithdrawGas returns the gas units used to estimate the withdraw fee,
+// using env override CHAIN_<ID>_WITHDRAW_GAS, otherwise the withdraw gas of the chain registry
+func GetEnvChainWithdrawGas(chainID int) (uint64, error) {
+	if val := os.Getenv(fmt.Sprintf("CHAIN_%d_WITHDRAW_GAS", chainID)); val != "" {
+		parsed, err := strconv.ParseUint(val, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid CHAIN_%d_WITHDRAW_GAS value: %s", chainID, val)
+		}
+		if parsed == 0 {
+			return 0, fmt.Errorf("CHAIN_%d_WITHDRAW_GAS must be greater than 0", chainID)
+		}
+		return parsed, nil
+	}
+	if chain, ok := registry.Chains.Get(chainID); ok {
+		return chain.WithdrawGas, nil
+	}
+	return DefaultWithdrawGas, nil
+}
+
 // GetEnvLogLevel returns the logging level from environment variables
 func GetEnvLogLevel() (logger.Level, error) {
 	logLevel := os.Getenv("LOG_LEVEL")
@@ -365,25 +1669,28 @@ func GetEnvLogLevel() (logger.Level, error) {
 	}
 }
 
-// GetEnvLogColoring returns whether logging coloring is enabled from environment variables
+// GetEnvLogColoring returns whether logging coloring is enabled from environment variables,
+// in auto mode coloring is enabled if the logs are written to a terminal
 func GetEnvLogColoring() (bool, error) {
 	logColoring := os.Getenv("LOG_COLORING")
 	if logColoring == "" {
-		return DefaultLogColoring, nil
+		logColoring = DefaultLogColoring
 	}
 
 	switch logColoring {
+	case LogColoringAuto:
+		return logger.IsTerminal(), nil
 	case "true":
 		return true, nil
 	case "false":
 		return false, nil
 	}
 
-	return false, fmt.Errorf("invalid LOG_COLORING value: %s, must be 'true' or 'false'", logColoring)
+	return false, fmt.Errorf("invalid LOG_COLORING value: %s, must be 'auto', 'true' or 'false'", logColoring)
 }
 
 // GetEnvChainMaxGasPrice returns the effective per-chain max gas price (wei),
-// using env override CHAIN_<ID>_MAX_GAS_PRICE, otherwise built-in defaults, otherwise the provided global
+// using env override CHAIN_<ID>_MAX_GAS_PRICE, otherwise the cap of the chain registry, otherwise the provided global
 func GetEnvChainMaxGasPrice(chainID int, global *big.Int) (*big.Int, error) {
 	if val := os.Getenv(fmt.Sprintf("CHAIN_%d_MAX_GAS_PRICE", chainID)); val != "" {
 		parsed := new(big.Int)
@@ -395,16 +1702,55 @@ func GetEnvChainMaxGasPrice(chainID int, global *big.Int) (*big.Int, error) {
 		}
 		return parsed, nil
 	}
-	if def, ok := DefaultChainMaxGasPrice[chainID]; ok {
+	if chain, ok := registry.Chains.Get(chainID); ok && chain.MaxGasPrice != "" {
 		parsed := new(big.Int)
-		if _, ok2 := parsed.SetString(def, 10); ok2 {
+		if _, ok2 := parsed.SetString(chain.MaxGasPrice, 10); ok2 {
 			return parsed, nil
 		}
-		return nil, fmt.Errorf("failed to parse built-in default max gas price for chain %d", chainID)
+		return nil, fmt.Errorf("failed to parse the max gas price of chain %d", chainID)
 	}
 	return global, nil
 }
 
+// GetEnvChainMinGasPrice returns the per-chain gas price floor (wei),
+// using env override CHAIN_<ID>_MIN_GAS_PRICE, otherwise the floor of the chain registry, otherwise DefaultMinGasPrice
+func GetEnvChainMinGasPrice(chainID int) (*big.Int, error) {
+	val := os.Getenv(fmt.Sprintf("CHAIN_%d_MIN_GAS_PRICE", chainID))
+	if val == "" {
+		val = DefaultMinGasPrice
+		if chain, ok := registry.Chains.Get(chainID); ok && chain.MinGasPrice != "" {
+			val = chain.MinGasPrice
+		}
+	}
+
+	parsed, ok := new(big.Int).SetString(val, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid CHAIN_%d_MIN_GAS_PRICE value: %s", chainID, val)
+	}
+	if parsed.Sign() <= 0 {
+		return nil, fmt.Errorf("CHAIN_%d_MIN_GAS_PRICE must be greater than 0", chainID)
+	}
+	return parsed, nil
+}
+
+// GetEnvChainMinTip returns CHAIN_<ID>_MIN_TIP, the priority fee floor in wei of dynamic fee transactions,
+// nil if not set for no floor
+func GetEnvChainMinTip(chainID int) (*big.Int, error) {
+	val := os.Getenv(fmt.Sprintf("CHAIN_%d_MIN_TIP", chainID))
+	if val == "" {
+		return nil, nil
+	}
+
+	parsed, ok := new(big.Int).SetString(val, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid CHAIN_%d_MIN_TIP value: %s", chainID, val)
+	}
+	if parsed.Sign() < 0 {
+		return nil, fmt.Errorf("CHAIN_%d_MIN_TIP must not be negative", chainID)
+	}
+	return parsed, nil
+}
+
 // GetEnvChainConfigs returns the chain configurations for all supported network based on the environment variables and network type
 // TODO: refactor this to use a more generic approach for all chains
 func GetEnvChainConfigs(network string) ([]ChainConfig, error) {
@@ -554,7 +1900,8 @@ func GetEnvChainConfigs(network string) ([]ChainConfig, error) {
 		zetachainMinFee,
 	}
 
-	return []ChainConfig{
+	chainConfigs := make([]ChainConfig, 0, len(registry.Chains.IDs()))
+	for _, chainConfig := range []ChainConfig{
 		baseConfig,
 		arbitrumConfig,
 		polygonConfig,
@@ -562,5 +1909,74 @@ func GetEnvChainConfigs(network string) ([]ChainConfig, error) {
 		avalancheConfig,
 		bscConfig,
 		zetachainConfig,
-	}, nil
+	} {
+		// Disabled chains are left out entirely, their settings are not validated
+		enabled, err := GetEnvChainEnabled(chainConfig.ChainID)
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			continue
+		}
+
+		if err := normalizeChainConfig(&chainConfig); err != nil {
+			return nil, err
+		}
+		chainConfigs = append(chainConfigs, chainConfig)
+	}
+
+	// Custom chains are validated when parsed
+	customChains, err := GetEnvCustomChains()
+	if err != nil {
+		return nil, err
+	}
+	for _, customChain := range customChains {
+		enabled, err := GetEnvChainEnabled(customChain.ChainID)
+		if err != nil {
+			return nil, err
+		}
+		if enabled {
+			chainConfigs = append(chainConfigs, customChain.ChainConfig())
+		}
+	}
+	return chainConfigs, nil
+}
+
+// GetEnvChainEnabled returns whether the chain is fulfilled from CHAIN_<ID>_ENABLED, enabled by default
+func GetEnvChainEnabled(chainID int) (bool, error) {
+	enabled := os.Getenv(fmt.Sprintf("CHAIN_%d_ENABLED", chainID))
+	switch enabled {
+	case "", "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid CHAIN_%d_ENABLED value: %s, must be 'true' or 'false'", chainID, enabled)
+}
+
+// chainEnvPrefix returns the prefix of the environment variables of a built-in chain, its name in the chain registry,
+// false if the chain is not built-in
+func chainEnvPrefix(chainID int) (string, bool) {
+	chain, exists := registry.Chains.Get(chainID)
+	if !exists || chain.Custom {
+		return "", false
+	}
+	return chain.Name, true
+}
+
+// normalizeChainConfig validates the RPC URL and intent address of the chain configuration,
+// and checksums the intent address, the error names the environment variable to fix
+func normalizeChainConfig(chainConfig *ChainConfig) error {
+	prefix, _ := chainEnvPrefix(chainConfig.ChainID)
+
+	if _, err := url.ParseRequestURI(chainConfig.RPCURL); err != nil {
+		return fmt.Errorf("invalid %s_RPC_URL for chain %d: %v", prefix, chainConfig.ChainID, err)
+	}
+
+	if !common.IsHexAddress(chainConfig.IntentAddress) {
+		return fmt.Errorf("invalid %s_INTENT_ADDRESS for chain %d: %q is not a hex address",
+			prefix, chainConfig.ChainID, chainConfig.IntentAddress)
+	}
+	chainConfig.IntentAddress = common.HexToAddress(chainConfig.IntentAddress).Hex()
+	return nil
 }
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/speedrun-hq/speedrunner/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetEnvChainConfigsValidation verifies invalid RPC URLs and intent addresses are rejected with the variable name
+// and intent addresses are checksummed
+func TestGetEnvChainConfigsValidation(t *testing.T) {
+	t.Run("checksummed intent address", func(t *testing.T) {
+		t.Setenv("BASE_INTENT_ADDRESS", strings.ToLower(BaseMainnetIntentAddress))
+		chainConfigs, err := GetEnvChainConfigs(mainnet)
+		require.NoError(t, err)
+		assert.Equal(t, BaseMainnetIntentAddress, chainConfigs[0].IntentAddress)
+	})
+
+	t.Run("invalid intent address", func(t *testing.T) {
+		t.Setenv("BSC_INTENT_ADDRESS", "0x1234")
+		_, err := GetEnvChainConfigs(mainnet)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "BSC_INTENT_ADDRESS for chain 56")
+	})
+
+	t.Run("invalid RPC URL", func(t *testing.T) {
+		t.Setenv("ARBITRUM_RPC_URL", "arb1.example.com")
+		_, err := GetEnvChainConfigs(mainnet)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "ARBITRUM_RPC_URL for chain 42161")
+	})
+}
+
+// TestGetEnvChainConfigsEnabled verifies disabled chains are left out without validating their settings
+func TestGetEnvChainConfigsEnabled(t *testing.T) {
+	t.Setenv("CHAIN_56_ENABLED", "false")
+	t.Setenv("BSC_INTENT_ADDRESS", "invalid")
+	t.Setenv("CHAIN_8453_ENABLED", "true")
+
+	chainConfigs, err := GetEnvChainConfigs(mainnet)
+	require.NoError(t, err)
+	chainIDs := make([]int, 0, len(chainConfigs))
+	for _, chainConfig := range chainConfigs {
+		chainIDs = append(chainIDs, chainConfig.ChainID)
+	}
+	assert.NotContains(t, chainIDs, BSCMainnetChainID)
+	assert.Contains(t, chainIDs, BaseMainnetChainID)
+	assert.Len(t, chainIDs, len(registry.Chains.IDs())-1)
+
+	t.Setenv("CHAIN_8453_ENABLED", "no")
+	_, err = GetEnvChainConfigs(mainnet)
+	assert.ErrorContains(t, err, "CHAIN_8453_ENABLED")
+}
+
+// TestGetEnvCustomChains verifies custom chains are validated and added to the chain configurations
+func TestGetEnvCustomChains(t *testing.T) {
+	const customChains = `[{"chainId": 10, "name": "op", "rpc": "https://mainnet.optimism.io",
+		"intentAddress": "0x0b2c639c533813f4aa9d7837caf62653d097ff85", "minFee": "100000", "priceId": "ethereum",
+		"tokens": [{"type": "usdc", "address": "0x0b2c639c533813f4aa9d7837caf62653d097ff85", "decimals": 6}]}]`
+
+	t.Run("valid chain", func(t *testing.T) {
+		t.Setenv("CHAINS", customChains)
+		chains, err := GetEnvCustomChains()
+		require.NoError(t, err)
+		require.Len(t, chains, 1)
+		assert.Equal(t, "OP", chains[0].Name)
+		assert.Equal(t, "USDC", chains[0].Tokens[0].Type)
+		assert.Equal(t, "0x0b2C639c533813f4Aa9D7837CAf62653d097Ff85", chains[0].IntentAddress)
+
+		chainConfigs, err := GetEnvChainConfigs(mainnet)
+		require.NoError(t, err)
+		assert.Len(t, chainConfigs, len(registry.Chains.IDs())+1)
+		assert.Equal(t, 10, chainConfigs[len(chainConfigs)-1].ChainID)
+
+		t.Setenv("CHAIN_10_ENABLED", "false")
+		chainConfigs, err = GetEnvChainConfigs(mainnet)
+		require.NoError(t, err)
+		assert.Len(t, chainConfigs, len(registry.Chains.IDs()))
+	})
+
+	t.Run("built-in chain", func(t *testing.T) {
+		t.Setenv("CHAINS", strings.Replace(customChains, `"chainId": 10`, `"chainId": 8453`, 1))
+		_, err := GetEnvCustomChains()
+		assert.ErrorContains(t, err, "BASE_*")
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		t.Setenv("CHAINS", strings.Replace(customChains, `"usdc"`, `"DAI"`, 1))
+		_, err := GetEnvCustomChains()
+		assert.ErrorContains(t, err, `invalid token type "DAI"`)
+	})
+
+	t.Run("chain defaults", func(t *testing.T) {
+		t.Setenv("CHAINS", strings.Replace(customChains, `"priceId"`,
+			`"nativeDecimals": 8, "withdrawGas": 80000, "maxGasPrice": "5000000000", "reorgCheckDepth": 10, "rollup": "OPStack", "priceId"`, 1))
+		chains, err := GetEnvCustomChains()
+		require.NoError(t, err)
+		assert.Equal(t, 8, chains[0].NativeDecimals)
+		assert.Equal(t, uint64(80000), chains[0].WithdrawGas)
+		assert.Equal(t, "5000000000", chains[0].MaxGasPrice)
+		assert.Equal(t, uint64(10), chains[0].ReorgCheckDepth)
+		assert.Equal(t, registry.RollupOPStack, chains[0].Rollup)
+	})
+
+	t.Run("invalid chain defaults", func(t *testing.T) {
+		t.Setenv("CHAINS", strings.Replace(customChains, `"priceId"`, `"minGasPrice": "0", "priceId"`, 1))
+		_, err := GetEnvCustomChains()
+		assert.ErrorContains(t, err, "invalid minGasPrice")
+
+		t.Setenv("CHAINS", strings.Replace(customChains, `"priceId"`, `"rollup": "zksync", "priceId"`, 1))
+		_, err = GetEnvCustomChains()
+		assert.ErrorContains(t, err, `invalid rollup "zksync"`)
+
+		t.Setenv("CHAINS", strings.Replace(customChains, `"priceId"`, `"nativeDecimals": 78, "priceId"`, 1))
+		_, err = GetEnvCustomChains()
+		assert.ErrorContains(t, err, "nativeDecimals for chain 10")
+	})
+
+	t.Run("duplicate chain", func(t *testing.T) {
+		t.Setenv("CHAINS", "["+customChains[1:len(customChains)-1]+","+customChains[1:])
+		_, err := GetEnvCustomChains()
+		assert.ErrorContains(t, err, "chain 10 defined twice")
+	})
+}
+
+// TestValidateConfigKeystore verifies the keystore signer requires the fulfiller address to check the keystore key
+func TestValidateConfigKeystore(t *testing.T) {
+	cfg := &Config{
+		FulfillerAddress: DefaultFulfillerAddress,
+		Signer:           SignerConfig{Type: SignerTypeKeystore, KeystorePath: "keystore.json"},
+		Chains:           map[int]ChainConfig{8453: {IntentAddress: BaseMainnetIntentAddress}},
+	}
+	assert.ErrorContains(t, validateConfig(cfg), "FULFILLER_ADDRESS")
+
+	cfg.FulfillerAddress = "0x0000000000000000000000000000000000000001"
+	assert.NoError(t, validateConfig(cfg))
+}
+
+// TestGetEnvRetryPolicies verifies MAX_RETRIES caps the default max retries of the error types
+// while a max retries set for an error type applies even above it
+func TestGetEnvRetryPolicies(t *testing.T) {
+	t.Setenv("RETRY_NETWORK_ERROR_MAX_RETRIES", "8")
+	policies, err := GetEnvRetryPolicies(2)
+	require.NoError(t, err)
+	assert.Equal(t, 8, policies["network_error"].MaxRetries)
+	assert.Equal(t, 2, policies["gas_error"].MaxRetries)
+
+	policies, err = GetEnvRetryPolicies(DefaultMaxRetries)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultRetryPolicies["gas_error"].MaxRetries, policies["gas_error"].MaxRetries)
+}
+
+// TestGetEnvTokenDecimals verifies the token decimals overrides are read by chain and token type
+func TestGetEnvTokenDecimals(t *testing.T) {
+	t.Setenv("CHAIN_1_USDC_DECIMALS", "8")
+	t.Setenv("CHAIN_10_USDT_DECIMALS", "18")
+	overrides, err := GetEnvTokenDecimals([]int{1, 10, 56})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]map[string]int{1: {"USDC": 8}, 10: {"USDT": 18}}, overrides)
+
+	t.Setenv("CHAIN_56_USDC_DECIMALS", "invalid")
+	_, err = GetEnvTokenDecimals([]int{1, 10, 56})
+	assert.ErrorContains(t, err, "invalid CHAIN_56_USDC_DECIMALS value")
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFileChainsKey is the section of the configuration file holding the per-chain settings
+const configFileChainsKey = "chains"
+
+// LoadConfigFile sets the environment variables defined in the YAML or JSON configuration file at path,
+// the variables already set in the environment take precedence over the file.
+// Settings are named as their environment variable, per-chain settings can be grouped by chain ID
+// under chains, e.g. chains: {8453: {MAX_PENDING_TX: 20}} sets CHAIN_8453_MAX_PENDING_TX
+func LoadConfigFile(path string) error {
+	vars, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	for name, value := range vars {
+		if _, set := os.LookupEnv(name); set {
+			continue
+		}
+		if err := os.Setenv(name, value); err != nil {
+			return fmt.Errorf("failed to set %s from config file: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// readConfigFile returns the environment variables defined in the configuration file at path
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %v", path, err)
+	}
+
+	// JSON is valid YAML, both formats are parsed the same way
+	var file map[string]interface{}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %v", path, err)
+	}
+
+	vars := make(map[string]string, len(file))
+	for key, value := range file {
+		if key != configFileChainsKey {
+			if err := setConfigFileVar(vars, key, value); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		chainsSection, ok := configFileMapping(value)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s section in config file, must map chain IDs to settings", configFileChainsKey)
+		}
+		for chainID, settings := range chainsSection {
+			chainSettings, ok := configFileMapping(settings)
+			if !ok {
+				return nil, fmt.Errorf("invalid settings of chain %s in config file, must be a mapping", chainID)
+			}
+			for key, value := range chainSettings {
+				if err := setConfigFileVar(vars, fmt.Sprintf("CHAIN_%s_%s", chainID, key), value); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return vars, nil
+}
+
+// setConfigFileVar adds the setting to vars as an environment variable, lists are joined with commas
+func setConfigFileVar(vars map[string]string, name string, value interface{}) error {
+	name = strings.ToUpper(name)
+
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}, map[interface{}]interface{}:
+		return fmt.Errorf("invalid value of %s in config file, must be a scalar or a list", name)
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			switch item.(type) {
+			case map[string]interface{}, map[interface{}]interface{}, []interface{}:
+				return fmt.Errorf("invalid value of %s in config file, list items must be scalars", name)
+			}
+			items = append(items, fmt.Sprint(item))
+		}
+		vars[name] = strings.Join(items, ",")
+	default:
+		vars[name] = fmt.Sprint(v)
+	}
+	return nil
+}
+
+// configFileMapping returns the mapping with its keys as strings, the YAML decoder keeps
+// non-string keys such as chain IDs in a map[interface{}]interface{}
+func configFileMapping(value interface{}) (map[string]interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, true
+	case map[interface{}]interface{}:
+		mapping := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			mapping[fmt.Sprint(key)] = item
+		}
+		return mapping, true
+	default:
+		return nil, false
+	}
+}
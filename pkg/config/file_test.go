@@ -0,0 +1,100 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfigFile writes the content to a config file in a temporary directory and returns its path
+func writeConfigFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// TestReadConfigFile verifies YAML and JSON files are read as environment variables
+func TestReadConfigFile(t *testing.T) {
+	expected := map[string]string{
+		"POLLING_INTERVAL":               "10",
+		"FEE_SAFETY_MARGIN":              "1.2",
+		"INTENT_CLAIMING":                "true",
+		"ALLOWED_SOURCE_CHAINS":          "1,8453",
+		"CHAIN_8453_MAX_PENDING_TX":      "20",
+		"CHAIN_8453_PROCESSING_INTERVAL": "30s",
+		"CHAIN_56_MIN_GAS_PRICE":         "100000000",
+	}
+
+	yamlPath := writeConfigFile(t, "config.yaml", `
+POLLING_INTERVAL: 10
+fee_safety_margin: 1.2
+INTENT_CLAIMING: true
+ALLOWED_SOURCE_CHAINS: [1, 8453]
+METRICS_HOST:
+chains:
+  8453:
+    MAX_PENDING_TX: 20
+    processing_interval: 30s
+  56:
+    MIN_GAS_PRICE: "100000000"
+`)
+	vars, err := readConfigFile(yamlPath)
+	require.NoError(t, err)
+	assert.Equal(t, expected, vars)
+
+	jsonPath := writeConfigFile(t, "config.json", `{
+  "POLLING_INTERVAL": 10,
+  "FEE_SAFETY_MARGIN": 1.2,
+  "INTENT_CLAIMING": true,
+  "ALLOWED_SOURCE_CHAINS": [1, 8453],
+  "chains": {
+    "8453": {"MAX_PENDING_TX": 20, "PROCESSING_INTERVAL": "30s"},
+    "56": {"MIN_GAS_PRICE": "100000000"}
+  }
+}`)
+	vars, err = readConfigFile(jsonPath)
+	require.NoError(t, err)
+	assert.Equal(t, expected, vars)
+}
+
+// TestReadConfigFileErrors verifies invalid files are rejected
+func TestReadConfigFileErrors(t *testing.T) {
+	tests := map[string]string{
+		"invalid syntax":   "POLLING_INTERVAL: [10",
+		"nested setting":   "CIRCUIT_BREAKER:\n  ENABLED: true",
+		"invalid chains":   "chains: [8453]",
+		"invalid chain":    "chains:\n  8453: 20",
+		"nested list item": "ALLOWED_SOURCE_CHAINS: [[1]]",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := readConfigFile(writeConfigFile(t, "config.yaml", content))
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := readConfigFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+// TestLoadConfigFile verifies the environment variables take precedence over the file
+func TestLoadConfigFile(t *testing.T) {
+	t.Setenv("WORKER_COUNT", "3")
+	// register the variable set by the file to restore it after the test
+	t.Setenv("MAX_RETRIES", "")
+	require.NoError(t, os.Unsetenv("MAX_RETRIES"))
+
+	path := writeConfigFile(t, "config.yaml", "WORKER_COUNT: 8\nMAX_RETRIES: 4\n")
+	require.NoError(t, LoadConfigFile(path))
+
+	workers, err := GetEnvWorkerCount()
+	require.NoError(t, err)
+	assert.Equal(t, 3, workers)
+
+	maxRetries, err := GetEnvMaxRetries()
+	require.NoError(t, err)
+	assert.Equal(t, 4, maxRetries)
+}
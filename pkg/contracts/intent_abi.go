@@ -0,0 +1,80 @@
+package contracts
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// LoadIntentABI parses the Intent ABI from the file at path, the baked-in IntentABI is used if path is empty
+// The file can contain either a plain ABI array or a compiler artifact with an "abi" field
+func LoadIntentABI(path string) (abi.ABI, error) {
+	if path == "" {
+		return abi.JSON(strings.NewReader(IntentABI))
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return abi.ABI{}, fmt.Errorf("failed to read intent ABI file %s: %v", path, err)
+	}
+
+	parsed, err := abi.JSON(strings.NewReader(string(content)))
+	if err != nil {
+		artifactABI, artifactErr := parseArtifactABI(content)
+		if artifactErr != nil {
+			return abi.ABI{}, fmt.Errorf("failed to parse intent ABI file %s: %v", path, err)
+		}
+		parsed = artifactABI
+	}
+	return parsed, nil
+}
+
+// parseArtifactABI parses the ABI of a compiler artifact (Foundry, Hardhat)
+func parseArtifactABI(content []byte) (abi.ABI, error) {
+	var artifact struct {
+		ABI json.RawMessage `json:"abi"`
+	}
+	if err := json.Unmarshal(content, &artifact); err != nil {
+		return abi.ABI{}, err
+	}
+	if len(artifact.ABI) == 0 {
+		return abi.ABI{}, fmt.Errorf("no abi field in artifact")
+	}
+	return abi.JSON(strings.NewReader(string(artifact.ABI)))
+}
+
+// NewIntentWithABI creates a new instance of Intent using a custom ABI, bound to a specific deployed contract.
+func NewIntentWithABI(address common.Address, parsed abi.ABI, backend bind.ContractBackend) (*Intent, error) {
+	contract := bind.NewBoundContract(address, parsed, backend, backend, backend)
+	return &Intent{IntentCaller: IntentCaller{contract: contract}, IntentTransactor: IntentTransactor{contract: contract}, IntentFilterer: IntentFilterer{contract: contract}}, nil
+}
+
+// FulfillArgs returns the arguments of the fulfill method in the order of its inputs in the ABI,
+// each input is resolved by name from values so that the contract can reorder or rename arguments
+func FulfillArgs(parsed abi.ABI, method string, values map[string]interface{}) ([]interface{}, error) {
+	m, ok := parsed.Methods[method]
+	if !ok {
+		return nil, fmt.Errorf("method %s not found in intent ABI", method)
+	}
+
+	args := make([]interface{}, 0, len(m.Inputs))
+	for _, input := range m.Inputs {
+		value, ok := values[input.Name]
+		if !ok {
+			return nil, fmt.Errorf("no value for input %s of method %s", input.Name, method)
+		}
+		args = append(args, value)
+	}
+	return args, nil
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Intent *IntentTransactor) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Intent.contract.Transact(opts, method, params...)
+}
@@ -0,0 +1,90 @@
+package contracts
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const upgradedIntentABI = `[{
+	"inputs": [
+		{"internalType": "address", "name": "receiver", "type": "address"},
+		{"internalType": "bytes32", "name": "intentId", "type": "bytes32"},
+		{"internalType": "address", "name": "asset", "type": "address"},
+		{"internalType": "uint256", "name": "amount", "type": "uint256"}
+	],
+	"name": "fulfillIntent",
+	"outputs": [],
+	"stateMutability": "nonpayable",
+	"type": "function"
+}]`
+
+func TestLoadIntentABI(t *testing.T) {
+	t.Run("baked-in ABI when path is empty", func(t *testing.T) {
+		parsed, err := LoadIntentABI("")
+		require.NoError(t, err)
+		assert.Contains(t, parsed.Methods, "fulfill")
+	})
+
+	t.Run("plain ABI file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "intent.json")
+		require.NoError(t, os.WriteFile(path, []byte(upgradedIntentABI), 0o600))
+
+		parsed, err := LoadIntentABI(path)
+		require.NoError(t, err)
+		assert.Contains(t, parsed.Methods, "fulfillIntent")
+	})
+
+	t.Run("compiler artifact", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "Intent.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"abi": `+upgradedIntentABI+`, "bytecode": "0x"}`), 0o600))
+
+		parsed, err := LoadIntentABI(path)
+		require.NoError(t, err)
+		assert.Contains(t, parsed.Methods, "fulfillIntent")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := LoadIntentABI(filepath.Join(t.TempDir(), "missing.json"))
+		assert.Error(t, err)
+	})
+}
+
+func TestFulfillArgs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "intent.json")
+	require.NoError(t, os.WriteFile(path, []byte(upgradedIntentABI), 0o600))
+	parsed, err := LoadIntentABI(path)
+	require.NoError(t, err)
+
+	intentID := [32]byte{1}
+	asset := common.HexToAddress("0x1")
+	receiver := common.HexToAddress("0x2")
+	amount := big.NewInt(100)
+	values := map[string]interface{}{
+		"intentId": intentID,
+		"asset":    asset,
+		"amount":   amount,
+		"receiver": receiver,
+	}
+
+	t.Run("arguments follow the ABI order", func(t *testing.T) {
+		args, err := FulfillArgs(parsed, "fulfillIntent", values)
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{receiver, intentID, asset, amount}, args)
+	})
+
+	t.Run("unknown method", func(t *testing.T) {
+		_, err := FulfillArgs(parsed, "fulfill", values)
+		assert.Error(t, err)
+	})
+
+	t.Run("missing input value", func(t *testing.T) {
+		_, err := FulfillArgs(parsed, "fulfillIntent", map[string]interface{}{"intentId": intentID})
+		assert.Error(t, err)
+	})
+}
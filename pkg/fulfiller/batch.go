@@ -0,0 +1,303 @@
+package fulfiller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/speedrun-hq/speedrunner/pkg/chains"
+	"github.com/speedrun-hq/speedrunner/pkg/metrics"
+	"github.com/speedrun-hq/speedrunner/pkg/models"
+)
+
+// errBatchNotFulfilled is wrapped by the errors of batches that fulfilled none of their intents,
+// either because the batch transaction was never sent or because it reverted
+var errBatchNotFulfilled = errors.New("batch not fulfilled")
+
+// batchKey identifies the intents that can be fulfilled in the same transaction,
+// sent through the same Intent contract in the same token to the same receiver
+type batchKey struct {
+	destinationChain int
+	contract         string
+	tokenType        chains.TokenType
+	recipient        common.Address
+}
+
+// batchIntents groups the intents to the same receiver, token and destination into batches of at most
+// BatchFulfillSize intents fulfilled in one transaction, the other intents are returned to be fulfilled individually
+// Intents are batched only on chains whose Intent contract supports multicall, native token intents are never batched
+func (s *Fulfiller) batchIntents(intents []models.Intent) ([][]models.Intent, []models.Intent) {
+	if s.config.BatchFulfillSize <= 1 {
+		return nil, intents
+	}
+
+	groups := make(map[batchKey][]models.Intent)
+	var keys []batchKey
+	var singles []models.Intent
+	for _, intent := range intents {
+		key, ok := s.batchKey(intent)
+		if !ok {
+			singles = append(singles, intent)
+			continue
+		}
+		if _, exists := groups[key]; !exists {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], intent)
+	}
+
+	var batches [][]models.Intent
+	for _, key := range keys {
+		group := groups[key]
+		for len(group) > 0 {
+			size := min(len(group), s.config.BatchFulfillSize)
+			if size == 1 {
+				singles = append(singles, group[0])
+			} else {
+				batches = append(batches, group[:size])
+			}
+			group = group[size:]
+		}
+	}
+	return batches, singles
+}
+
+// batchKey returns the batch key of the intent, false if the intent can't be batched
+func (s *Fulfiller) batchKey(intent models.Intent) (batchKey, bool) {
+	tokenType := chains.GetTokenType(intent.Token)
+	if tokenType == "" || tokenType == chains.TokenTypeNative {
+		return batchKey{}, false
+	}
+
+	s.mu.Lock()
+	chainClient, exists := s.chainClients[intent.DestinationChain]
+	s.mu.Unlock()
+	if !exists || !chainClient.SupportsMulticall() {
+		return batchKey{}, false
+	}
+
+	return batchKey{
+		destinationChain: intent.DestinationChain,
+		contract:         strings.ToLower(intent.Contract),
+		tokenType:        tokenType,
+		recipient:        common.HexToAddress(intent.Recipient),
+	}, true
+}
+
+// processBatch fulfills a batch of intents in one transaction, falling back to individual fulfillments
+// if the batch transaction wasn't sent or reverted, other failures go through the retry of each intent
+// since the batch transaction may still be mined
+func (s *Fulfiller) processBatch(ctx context.Context, id int, batch []models.Intent) {
+	chainID := batch[0].DestinationChain
+
+	// Check if circuit breaker is enabled and open for destination chain
+	if cb, ok := s.circuitBreakers[chainID]; ok && cb.IsEnabled() && cb.IsOpen() {
+		s.logger.Info("Worker %d: Circuit breaker open for chain %d, skipping batch of %d intents", id, chainID, len(batch))
+		for _, intent := range batch {
+			s.releaseExposure(intent)
+			s.wg.Done()
+		}
+		return
+	}
+
+	// Limit the concurrency on the chain while its circuit breaker is in cooldown
+	release, err := s.acquireCooldownSlot(ctx, chainID)
+	if err != nil {
+		for _, intent := range batch {
+			s.releaseExposure(intent)
+			s.wg.Done()
+		}
+		return
+	}
+	defer release()
+
+	// Skip the intents fulfilled since they were fetched, then claim the others
+	// so that other instances don't fulfill them concurrently
+	claimed := make([]models.Intent, 0, len(batch))
+	for _, intent := range batch {
+		if !s.verifyIntentStatus(ctx, intent) || !s.claimIntent(ctx, intent) {
+			s.releaseExposure(intent)
+			s.wg.Done()
+			continue
+		}
+		claimed = append(claimed, intent)
+	}
+	if len(claimed) == 1 {
+		s.runIntent(ctx, id, claimed[0])
+		return
+	}
+	if len(claimed) == 0 {
+		return
+	}
+
+	s.logger.Info("Worker %d processing batch of %d intents to %s (dest: %d)",
+		id, len(claimed), claimed[0].Recipient, chainID)
+
+	startTime := time.Now()
+	results, err := s.fulfillBatchWithTimeout(ctx, claimed)
+	if err != nil && !errors.Is(err, errBatchNotFulfilled) {
+		s.logger.ErrorWithChain(chainID, "Batch fulfillment of %d intents failed after sending the transaction: %v",
+			len(claimed), err)
+		metrics.BatchFulfillments.WithLabelValues(strconv.Itoa(chainID), "failed").Inc()
+		for _, intent := range claimed {
+			s.handleResult(ctx, id, intent, nil, err)
+		}
+		return
+	}
+	if err != nil {
+		s.logger.ErrorWithChain(chainID, "Batch fulfillment of %d intents failed, fulfilling them individually: %v",
+			len(claimed), err)
+		metrics.BatchFulfillments.WithLabelValues(strconv.Itoa(chainID), "fallback").Inc()
+		for _, intent := range claimed {
+			s.runIntent(ctx, id, intent)
+		}
+		return
+	}
+
+	metrics.BatchFulfillments.WithLabelValues(strconv.Itoa(chainID), "success").Inc()
+	processingTime := time.Since(startTime).Seconds()
+	for i, intent := range claimed {
+		metrics.IntentProcessingTime.WithLabelValues(strconv.Itoa(chainID)).Observe(processingTime)
+		s.handleResult(ctx, id, intent, results[i], nil)
+	}
+}
+
+// fulfillBatchWithTimeout fulfills the batch, aborting if it doesn't complete within the configured timeout
+func (s *Fulfiller) fulfillBatchWithTimeout(ctx context.Context, intents []models.Intent) ([]*models.FulfillmentResult, error) {
+	if s.config.FulfillTimeout <= 0 {
+		return s.fulfillBatch(ctx, intents)
+	}
+
+	fulfillCtx, cancel := context.WithTimeout(ctx, s.config.FulfillTimeout)
+	defer cancel()
+
+	results, err := s.fulfillBatch(fulfillCtx, intents)
+	if err != nil && !errors.Is(err, errFulfillTimeout) && errors.Is(fulfillCtx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%w after %v: %w", errFulfillTimeout, s.config.FulfillTimeout, err)
+	}
+	return results, err
+}
+
+// fulfillBatch fulfills intents to the same receiver, token and destination in one multicall transaction,
+// it returns the result of each intent, sharing the gas of the transaction equally
+// Errors before the transaction is sent and reverts of the transaction wrap errBatchNotFulfilled
+func (s *Fulfiller) fulfillBatch(ctx context.Context, intents []models.Intent) ([]*models.FulfillmentResult, error) {
+	first := intents[0]
+	notSent := func(err error) error {
+		return fmt.Errorf("%w: %w", errBatchNotFulfilled, err)
+	}
+
+	s.mu.Lock()
+	chainClient, exists := s.chainClients[first.DestinationChain]
+	s.mu.Unlock()
+
+	if !exists {
+		return nil, notSent(fmt.Errorf("destination chain configuration not found for: %d", first.DestinationChain))
+	}
+
+	if err := s.updateGasPrice(ctx, chainClient, first.DestinationChain); err != nil {
+		return nil, notSent(err)
+	}
+
+	intentIDs := make([][32]byte, 0, len(intents))
+	ids := make([]string, 0, len(intents))
+	amounts := make([]*big.Int, 0, len(intents))
+	total := new(big.Int)
+	for _, intent := range intents {
+		amount, err := intentAmount(intent)
+		if err != nil {
+			return nil, notSent(err)
+		}
+		intentIDs = append(intentIDs, common.HexToHash(intent.ID))
+		ids = append(ids, intent.ID)
+		amounts = append(amounts, amount)
+		total.Add(total, amount)
+	}
+
+	tokenType := chains.GetTokenType(first.Token)
+	if tokenType == "" {
+		return nil, notSent(fmt.Errorf("unknown token %s in intent: %s", first.Token, first.ID))
+	}
+	receiver := s.intentReceiver(chainClient, first, tokenType)
+
+	intentAddress, err := chainClient.ResolveIntentAddress(first.Contract)
+	if err != nil {
+		return nil, notSent(err)
+	}
+	tokenAddress := chains.GetTokenEthAddress(first.DestinationChain, tokenType)
+
+	opts, err := chainClient.TransactOpts()
+	if err != nil {
+		return nil, notSent(err)
+	}
+	txOpts := *opts
+
+	// A single approval covers the total amount of the batch
+	approval := &models.FulfillmentResult{}
+	if err := s.approveToken(ctx, chainClient, first, intentAddress, tokenAddress, total, &txOpts, approval); err != nil {
+		return nil, notSent(err)
+	}
+
+	s.logger.NoticeWithChain(first.DestinationChain, "Initiating batch fulfillment of %d intents (token: %s, amount: %s, receiver: %s)",
+		len(intents), tokenAddress.Hex(), total.String(), receiver.Hex())
+
+	restoreNonce := s.applyReleasedNonce(chainClient, &txOpts)
+	details := fulfillTx{
+		intentIDs:     ids,
+		intentAddress: intentAddress,
+		token:         tokenAddress,
+		amount:        total,
+		receiver:      receiver,
+		opts:          &txOpts,
+	}
+	tx, err := s.txSender(chainClient).FulfillBatch(&txOpts, intentAddress, intentIDs, tokenAddress, amounts, receiver)
+	if err != nil {
+		restoreNonce()
+		s.logTxFailure(first.DestinationChain, details, nil, err)
+		return nil, notSent(fmt.Errorf("failed to fulfill batch on %d: %w", first.DestinationChain, err))
+	}
+
+	s.logger.InfoWithChain(first.DestinationChain, "Batch fulfillment transaction created for %d intents: %s",
+		len(intents), tx.Hash().Hex())
+
+	receipt, err := s.waitMined(ctx, chainClient, tx)
+	if err != nil {
+		s.logTxFailure(first.DestinationChain, details, tx, err)
+		return nil, fmt.Errorf("failed to wait for batch transaction on %d: %w", first.DestinationChain, err)
+	}
+
+	if receipt.Status == 0 {
+		err := fmt.Errorf("%w: batch transaction %s failed on %d", errBatchNotFulfilled, tx.Hash().Hex(), first.DestinationChain)
+		s.logTxFailure(first.DestinationChain, details, tx, err)
+		return nil, err
+	}
+
+	s.logger.NoticeWithChain(first.DestinationChain, "Batch fulfillment transaction successful for %d intents: %s",
+		len(intents), tx.Hash().Hex())
+
+	results := make([]*models.FulfillmentResult, len(intents))
+	for i := range intents {
+		result := &models.FulfillmentResult{
+			TxHash:    tx.Hash().Hex(),
+			GasUsed:   receipt.GasUsed / uint64(len(intents)),
+			GasPrice:  receiptGasPrice(receipt, tx),
+			BlockHash: receipt.BlockHash.Hex(),
+		}
+		if receipt.BlockNumber != nil {
+			result.BlockNumber = receipt.BlockNumber.Uint64()
+		}
+		// the approval is accounted to the first intent
+		if i == 0 {
+			result.ApprovalNeeded = approval.ApprovalNeeded
+			result.ApprovalGasUsed = approval.ApprovalGasUsed
+			result.ApprovalGasPrice = approval.ApprovalGasPrice
+		}
+		results[i] = result
+	}
+	return results, nil
+}
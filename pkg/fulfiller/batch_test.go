@@ -0,0 +1,174 @@
+package fulfiller
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/speedrun-hq/speedrunner/pkg/chainclient"
+	"github.com/speedrun-hq/speedrunner/pkg/clock"
+	"github.com/speedrun-hq/speedrunner/pkg/config"
+	"github.com/speedrun-hq/speedrunner/pkg/contracts"
+	"github.com/speedrun-hq/speedrunner/pkg/logger"
+	"github.com/speedrun-hq/speedrunner/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// multicallABI is the Intent ABI with the multicall method of OpenZeppelin Multicall
+var multicallABI = strings.TrimSuffix(strings.TrimSpace(contracts.IntentABI), "]") + `,
+	{"type": "function", "name": "multicall", "stateMutability": "nonpayable",
+	 "inputs": [{"name": "data", "type": "bytes[]"}], "outputs": [{"name": "results", "type": "bytes[]"}]}
+]`
+
+// fakeAllowanceService serves a fixed gas price and an unlimited allowance for any call
+type fakeAllowanceService struct {
+	fakeGasPriceService
+}
+
+func (f *fakeAllowanceService) Call(_ map[string]interface{}, _ string) hexutil.Bytes {
+	return math.U256Bytes(new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1)))
+}
+
+// newMulticallClient returns a chain client whose Intent ABI supports multicall
+func newMulticallClient(t *testing.T, chainID int, client *ethclient.Client) *chainclient.Client {
+	parsed, err := abi.JSON(strings.NewReader(multicallABI))
+	require.NoError(t, err)
+
+	chainClient := &chainclient.Client{
+		ChainID:       chainID,
+		Client:        client,
+		IntentAddress: "0x1111111111111111111111111111111111111111",
+		GasMultiplier: 1,
+		Auth:          &bind.TransactOpts{From: common.HexToAddress("0x2222222222222222222222222222222222222222")},
+	}
+	require.NoError(t, chainClient.SetIntentABI(parsed, "fulfill"))
+	require.True(t, chainClient.SupportsMulticall())
+	return chainClient
+}
+
+// TestBatchIntents verifies intents to the same receiver, token and destination are grouped up to the batch size
+func TestBatchIntents(t *testing.T) {
+	const (
+		usdcBase  = "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"
+		recipient = "0x1234567890abcdef1234567890abcdef12345678"
+	)
+	s := &Fulfiller{
+		config: &config.Config{BatchFulfillSize: 2},
+		chainClients: map[int]*chainclient.Client{
+			42161: newMulticallClient(t, 42161, nil),
+			137:   {ChainID: 137},
+		},
+		logger: logger.NewMemoryLogger(),
+	}
+
+	intents := []models.Intent{
+		{ID: "a1", DestinationChain: 42161, Token: usdcBase, Recipient: recipient},
+		{ID: "other", DestinationChain: 42161, Token: usdcBase, Recipient: "0x9999999999999999999999999999999999999999"},
+		{ID: "native", DestinationChain: 42161, Token: "0x0000000000000000000000000000000000000000", Recipient: recipient},
+		{ID: "a2", DestinationChain: 42161, Token: usdcBase, Recipient: "0x" + strings.ToUpper(recipient[2:])},
+		{ID: "no-multicall", DestinationChain: 137, Token: usdcBase, Recipient: recipient},
+		{ID: "a3", DestinationChain: 42161, Token: usdcBase, Recipient: recipient},
+	}
+
+	batches, singles := s.batchIntents(intents)
+	require.Len(t, batches, 1)
+	assert.Equal(t, []string{"a1", "a2"}, intentIDs(batches[0]))
+	assert.Equal(t, []string{"native", "no-multicall", "a3", "other"}, intentIDs(singles))
+
+	// batching is disabled with a batch size of 1
+	s.config.BatchFulfillSize = 1
+	batches, singles = s.batchIntents(intents)
+	assert.Empty(t, batches)
+	assert.Len(t, singles, len(intents))
+}
+
+// TestFulfillBatch verifies a batch is sent in one transaction whose gas is shared by the intents
+func TestFulfillBatch(t *testing.T) {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", &fakeAllowanceService{}))
+	client := ethclient.NewClient(rpc.DialInProc(server))
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+
+	sender := &mockTxSender{status: types.ReceiptStatusSuccessful}
+	s, _ := newTestFulfiller(nil)
+	s.chainClients[42161] = newMulticallClient(t, 42161, client)
+	s.txSenders = map[int]TxSender{42161: sender}
+	first := testIntent(func(i *models.Intent) { i.ID = "0x01" })
+	second := testIntent(func(i *models.Intent) { i.ID, i.Amount = "0x02", "2500000" })
+
+	results, err := s.fulfillBatch(context.Background(), []models.Intent{first, second})
+	require.NoError(t, err)
+	require.Len(t, sender.batches, 1)
+	assert.Equal(t, []*big.Int{big.NewInt(1_000_000), big.NewInt(2_500_000)}, sender.batches[0])
+	require.Len(t, results, 2)
+	assert.Equal(t, results[0].TxHash, results[1].TxHash)
+	assert.Equal(t, uint64(25000), results[0].GasUsed)
+	assert.Equal(t, uint64(25000), results[1].GasUsed)
+	assert.False(t, results[0].ApprovalNeeded)
+
+	// a reverted transaction fulfills none of the intents
+	sender.status = types.ReceiptStatusFailed
+	_, err = s.fulfillBatch(context.Background(), []models.Intent{first, second})
+	assert.ErrorContains(t, err, "failed on 42161")
+	assert.ErrorIs(t, err, errBatchNotFulfilled)
+
+	// the intents of a transaction not known to be mined may be fulfilled
+	sender.waitErr = errors.New("connection refused")
+	_, err = s.fulfillBatch(context.Background(), []models.Intent{first, second})
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, errBatchNotFulfilled)
+}
+
+// TestProcessBatchSentFailure verifies the intents of a sent batch transaction that failed to be mined are retried
+// rather than fulfilled individually
+func TestProcessBatchSentFailure(t *testing.T) {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", &fakeAllowanceService{}))
+	client := ethclient.NewClient(rpc.DialInProc(server))
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+
+	sender := &mockTxSender{waitErr: errors.New("connection refused")}
+	s, _ := newTestFulfiller(&config.Config{MaxRetries: 3})
+	s.chainClients[42161] = newMulticallClient(t, 42161, client)
+	s.txSenders = map[int]TxSender{42161: sender}
+	s.retryJobs = newRetryQueue(0)
+	s.exposure = newExposureTracker(0)
+	s.clock = clock.Real{}
+	first := testIntent(func(i *models.Intent) { i.ID = "0x01" })
+	second := testIntent(func(i *models.Intent) { i.ID, i.Amount = "0x02", "2500000" })
+
+	s.wg.Add(2)
+	s.processBatch(context.Background(), 1, []models.Intent{first, second})
+	s.wg.Wait()
+
+	// only the batch transaction was sent, both intents are queued for retry
+	assert.Len(t, sender.sent, 1)
+	require.Equal(t, 2, s.retryJobs.len())
+	assert.Equal(t, "network_error", popRetryJob(t, s.retryJobs).ErrorType)
+}
+
+// intentIDs returns the IDs of the intents
+func intentIDs(intents []models.Intent) []string {
+	ids := make([]string, 0, len(intents))
+	for _, intent := range intents {
+		ids = append(ids, intent.ID)
+	}
+	return ids
+}
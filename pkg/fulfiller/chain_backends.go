@@ -0,0 +1,64 @@
+package fulfiller
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/speedrun-hq/speedrunner/pkg/chainclient"
+)
+
+// BalanceReader reads the balances of an account on a chain, implemented by chainclient.Client
+type BalanceReader interface {
+	TokenBalance(ctx context.Context, token common.Address, account common.Address) (*big.Int, error)
+	NativeBalance(ctx context.Context, account common.Address) (*big.Int, error)
+}
+
+// TxSender sends the fulfill transactions of intents and waits for them to be mined, implemented by chainclient.Client
+type TxSender interface {
+	Fulfill(
+		opts *bind.TransactOpts,
+		intentAddress common.Address,
+		intentID [32]byte,
+		asset common.Address,
+		amount *big.Int,
+		receiver common.Address,
+	) (*types.Transaction, error)
+	FulfillBatch(
+		opts *bind.TransactOpts,
+		intentAddress common.Address,
+		intentIDs [][32]byte,
+		asset common.Address,
+		amounts []*big.Int,
+		receiver common.Address,
+	) (*types.Transaction, error)
+	WaitMined(ctx context.Context, tx *types.Transaction) (*types.Receipt, error)
+}
+
+var (
+	_ BalanceReader = (*chainclient.Client)(nil)
+	_ TxSender      = (*chainclient.Client)(nil)
+)
+
+// balanceReader returns the balance reader of the chain, the chain client unless replaced in balanceReaders
+func (s *Fulfiller) balanceReader(chainID int) (BalanceReader, error) {
+	if reader, ok := s.balanceReaders[chainID]; ok {
+		return reader, nil
+	}
+	chainClient, exists := s.chainClients[chainID]
+	if !exists {
+		return nil, fmt.Errorf("chain client not found for chain %d", chainID)
+	}
+	return chainClient, nil
+}
+
+// txSender returns the sender of the fulfill transactions of the chain, the chain client unless replaced in txSenders
+func (s *Fulfiller) txSender(chainClient *chainclient.Client) TxSender {
+	if sender, ok := s.txSenders[chainClient.ChainID]; ok {
+		return sender
+	}
+	return chainClient
+}
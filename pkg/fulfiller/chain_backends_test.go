@@ -0,0 +1,140 @@
+package fulfiller
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/speedrun-hq/speedrunner/pkg/chainclient"
+	"github.com/speedrun-hq/speedrunner/pkg/config"
+	"github.com/speedrun-hq/speedrunner/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockBalanceReader serves fixed balances by token address, the zero address for the native balance
+type mockBalanceReader struct {
+	balances map[common.Address]*big.Int
+}
+
+func (m *mockBalanceReader) TokenBalance(_ context.Context, token common.Address, _ common.Address) (*big.Int, error) {
+	balance, ok := m.balances[token]
+	if !ok {
+		return nil, errors.New("no balance")
+	}
+	return balance, nil
+}
+
+func (m *mockBalanceReader) NativeBalance(ctx context.Context, account common.Address) (*big.Int, error) {
+	return m.TokenBalance(ctx, common.Address{}, account)
+}
+
+// mockTxSender records the fulfill transactions and mines them with a fixed receipt status
+type mockTxSender struct {
+	sent    []*bind.TransactOpts
+	batches [][]*big.Int
+	status  uint64
+	waitErr error
+}
+
+func (m *mockTxSender) Fulfill(
+	opts *bind.TransactOpts,
+	_ common.Address,
+	_ [32]byte,
+	_ common.Address,
+	_ *big.Int,
+	_ common.Address,
+) (*types.Transaction, error) {
+	m.sent = append(m.sent, opts)
+	return types.NewTx(&types.LegacyTx{Nonce: uint64(len(m.sent)), GasPrice: opts.GasPrice, Gas: 100000, Value: opts.Value}), nil
+}
+
+func (m *mockTxSender) FulfillBatch(
+	opts *bind.TransactOpts,
+	_ common.Address,
+	_ [][32]byte,
+	_ common.Address,
+	amounts []*big.Int,
+	_ common.Address,
+) (*types.Transaction, error) {
+	m.sent = append(m.sent, opts)
+	m.batches = append(m.batches, amounts)
+	return types.NewTx(&types.LegacyTx{Nonce: uint64(len(m.sent)), GasPrice: opts.GasPrice, Gas: 200000}), nil
+}
+
+func (m *mockTxSender) WaitMined(_ context.Context, tx *types.Transaction) (*types.Receipt, error) {
+	if m.waitErr != nil {
+		return nil, m.waitErr
+	}
+	return &types.Receipt{Status: m.status, TxHash: tx.Hash(), GasUsed: 50000, BlockNumber: big.NewInt(100)}, nil
+}
+
+// TestBalanceReader verifies balances are read from the injected reader
+func TestBalanceReader(t *testing.T) {
+	token := common.HexToAddress("0xaf88d065e77c8cC2239327C5EDb3A432268e5831")
+	s, _ := newTestFulfiller(&config.Config{FulfillerAddress: "0x2222222222222222222222222222222222222222"})
+	s.balanceReaders = map[int]BalanceReader{
+		42161: &mockBalanceReader{balances: map[common.Address]*big.Int{
+			token:              big.NewInt(5_000_000),
+			(common.Address{}): big.NewInt(1e18),
+		}},
+	}
+
+	balance, err := s.getTokenBalance(42161, token)
+	require.NoError(t, err)
+	assert.Equal(t, 0, balance.Cmp(big.NewFloat(5_000_000)))
+
+	balance, err = s.getNativeBalance(42161)
+	require.NoError(t, err)
+	assert.Equal(t, 0, balance.Cmp(big.NewFloat(1e18)))
+
+	_, err = s.getTokenBalance(42161, common.HexToAddress("0x1"))
+	assert.Error(t, err)
+
+	_, err = s.getTokenBalance(8453, token)
+	assert.ErrorContains(t, err, "chain client not found for chain 8453")
+}
+
+// TestFulfillIntentTxSender verifies a native token intent is fulfilled through the injected transaction sender
+func TestFulfillIntentTxSender(t *testing.T) {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", &fakeGasPriceService{}))
+	client := ethclient.NewClient(rpc.DialInProc(server))
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+
+	sender := &mockTxSender{status: types.ReceiptStatusSuccessful}
+	s, _ := newTestFulfiller(nil)
+	s.chainClients[42161] = &chainclient.Client{
+		ChainID:       42161,
+		Client:        client,
+		IntentAddress: "0x1111111111111111111111111111111111111111",
+		GasMultiplier: 1,
+		Auth:          &bind.TransactOpts{From: common.HexToAddress("0x2222222222222222222222222222222222222222")},
+	}
+	s.txSenders = map[int]TxSender{42161: sender}
+	intent := testIntent(func(i *models.Intent) {
+		i.Token, i.Amount = "0x0000000000000000000000000000000000000000", "1000000000000000"
+	})
+
+	result, err := s.fulfillIntent(context.Background(), intent)
+	require.NoError(t, err)
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, big.NewInt(1_000_000_000_000_000), sender.sent[0].Value)
+	assert.Equal(t, big.NewInt(1_000_000_000), sender.sent[0].GasPrice)
+	assert.Equal(t, uint64(50000), result.GasUsed)
+	assert.Equal(t, uint64(100), result.BlockNumber)
+
+	// a reverted transaction fails the fulfillment
+	sender.status = types.ReceiptStatusFailed
+	_, err = s.fulfillIntent(context.Background(), intent)
+	assert.ErrorContains(t, err, "transaction failed on 42161")
+}
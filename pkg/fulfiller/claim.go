@@ -0,0 +1,93 @@
+package fulfiller
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/speedrun-hq/speedrunner/pkg/models"
+	"github.com/speedrun-hq/speedrunner/pkg/srunclient"
+)
+
+// claimTimeout is the maximum duration of a claim, release or status request
+const claimTimeout = 5 * time.Second
+
+// intentStatusPending is the API status of intents waiting for fulfillment
+const intentStatusPending = "pending"
+
+// baseIntentID returns the intent ID without the retry tags added by the worker
+func baseIntentID(id string) string {
+	return strings.Split(id, "_retry_")[0]
+}
+
+// newClaimerID returns the ID claiming intents for this instance, the hostname followed by a random UUID,
+// the instances sharing the fulfiller address must not share the claims as the API claim is idempotent per claimer
+func newClaimerID() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "fulfiller"
+	}
+	return hostname + "-" + uuid.NewString()
+}
+
+// claimIntent claims the intent through the API when claiming is enabled
+// it returns false if the intent is claimed by another instance and must be skipped,
+// the fulfillment proceeds if the claim fails for another reason as the lock is optimistic
+func (s *Fulfiller) claimIntent(ctx context.Context, intent models.Intent) bool {
+	if !s.config.IntentClaiming {
+		return true
+	}
+
+	claimCtx, cancel := context.WithTimeout(ctx, claimTimeout)
+	defer cancel()
+
+	err := s.srunClient.ClaimIntent(claimCtx, baseIntentID(intent.ID), s.claimerID)
+	if errors.Is(err, srunclient.ErrIntentClaimed) {
+		s.logger.InfoWithChain(intent.DestinationChain, "Skipping intent %s: claimed by another fulfiller", intent.ID)
+		return false
+	}
+	if err != nil {
+		s.logger.ErrorWithChain(intent.DestinationChain, "Failed to claim intent %s, proceeding: %v", intent.ID, err)
+	}
+	return true
+}
+
+// verifyIntentStatus checks the current status of the intent through the API when VerifyIntentStatus is enabled
+// it returns false if the intent is no longer pending, e.g. fulfilled by another fulfiller since it was fetched,
+// the fulfillment proceeds if the status can't be fetched
+func (s *Fulfiller) verifyIntentStatus(ctx context.Context, intent models.Intent) bool {
+	if !s.config.VerifyIntentStatus {
+		return true
+	}
+
+	statusCtx, cancel := context.WithTimeout(ctx, claimTimeout)
+	defer cancel()
+
+	current, err := s.srunClient.GetIntent(statusCtx, baseIntentID(intent.ID))
+	if err != nil {
+		s.logger.ErrorWithChain(intent.DestinationChain, "Failed to verify status of intent %s, proceeding: %v", intent.ID, err)
+		return true
+	}
+	if !strings.EqualFold(current.Status, intentStatusPending) {
+		s.logger.InfoWithChain(intent.DestinationChain, "Skipping intent %s: status is %s", intent.ID, current.Status)
+		return false
+	}
+	return true
+}
+
+// releaseIntent releases the claim on an intent that won't be fulfilled by this instance
+func (s *Fulfiller) releaseIntent(ctx context.Context, intent models.Intent) {
+	if !s.config.IntentClaiming {
+		return
+	}
+
+	releaseCtx, cancel := context.WithTimeout(ctx, claimTimeout)
+	defer cancel()
+
+	if err := s.srunClient.ReleaseIntent(releaseCtx, baseIntentID(intent.ID), s.claimerID); err != nil {
+		s.logger.ErrorWithChain(intent.DestinationChain, "Failed to release intent %s: %v", intent.ID, err)
+	}
+}
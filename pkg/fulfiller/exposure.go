@@ -0,0 +1,85 @@
+package fulfiller
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/speedrun-hq/speedrunner/pkg/metrics"
+	"github.com/speedrun-hq/speedrunner/pkg/models"
+)
+
+// exposureTracker tracks the USD value committed to intents in flight across all chains,
+// an intent is committed from its acceptance by the filter until its processing ends without a pending retry
+type exposureTracker struct {
+	mu       sync.Mutex
+	maxUSD   float64 // 0 for no limit
+	totalUSD float64
+	intents  map[string]float64
+}
+
+// newExposureTracker creates an exposure tracker with a cap in USD, 0 for no limit
+func newExposureTracker(maxUSD float64) *exposureTracker {
+	return &exposureTracker{
+		maxUSD:  maxUSD,
+		intents: make(map[string]float64),
+	}
+}
+
+// reserve commits the value of the intent, it returns false if it would exceed the cap
+// reserving an intent already committed is a no-op
+func (e *exposureTracker) reserve(intentID string, valueUSD float64) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.intents[intentID]; ok {
+		return true
+	}
+	if e.maxUSD > 0 && e.totalUSD+valueUSD > e.maxUSD {
+		return false
+	}
+	e.intents[intentID] = valueUSD
+	e.totalUSD += valueUSD
+	metrics.ExposureUSD.Set(e.totalUSD)
+	return true
+}
+
+// release removes the value of the intent from the exposure
+func (e *exposureTracker) release(intentID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	valueUSD, ok := e.intents[intentID]
+	if !ok {
+		return
+	}
+	delete(e.intents, intentID)
+	e.totalUSD -= valueUSD
+	if len(e.intents) == 0 {
+		// avoid drifting float errors once nothing is in flight
+		e.totalUSD = 0
+	}
+	metrics.ExposureUSD.Set(e.totalUSD)
+}
+
+// current returns the USD value committed to intents in flight
+func (e *exposureTracker) current() float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.totalUSD
+}
+
+// intentAmountUSD returns the USD value of the intent amount on the destination chain,
+// stablecoins are valued at $1 and native tokens at the stored price of the chain
+func intentAmountUSD(intent models.Intent, nativePriceUSD float64) (float64, error) {
+	amount, ok := new(big.Int).SetString(intent.Amount, 10)
+	if !ok {
+		return 0, fmt.Errorf("invalid amount: %s", intent.Amount)
+	}
+	return amountUSD(convertTokenUnits(amount, intent), intent, nativePriceUSD)
+}
+
+// releaseExposure removes the intent from the exposure once its processing ends without a pending retry
+func (s *Fulfiller) releaseExposure(intent models.Intent) {
+	s.exposure.release(baseIntentID(intent.ID))
+}
@@ -0,0 +1,68 @@
+package fulfiller
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/speedrun-hq/speedrunner/pkg/metrics"
+	"github.com/speedrun-hq/speedrunner/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExposureTracker tests intents are rejected once the committed value reaches the cap
+func TestExposureTracker(t *testing.T) {
+	e := newExposureTracker(100)
+
+	assert.True(t, e.reserve("intent1", 60))
+	assert.InDelta(t, 60, testutil.ToFloat64(metrics.ExposureUSD), 0.001)
+
+	// reserving the same intent again doesn't count twice
+	assert.True(t, e.reserve("intent1", 60))
+	assert.InDelta(t, 60, e.current(), 0.001)
+
+	assert.False(t, e.reserve("intent2", 50))
+	assert.True(t, e.reserve("intent3", 40))
+	assert.InDelta(t, 100, e.current(), 0.001)
+
+	e.release("intent1")
+	assert.InDelta(t, 40, e.current(), 0.001)
+	assert.True(t, e.reserve("intent2", 50))
+
+	e.release("intent2")
+	e.release("intent3")
+	e.release("unknown")
+	assert.Equal(t, 0.0, e.current())
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.ExposureUSD))
+}
+
+// TestExposureTrackerNoLimit tests the exposure is tracked without cap
+func TestExposureTrackerNoLimit(t *testing.T) {
+	e := newExposureTracker(0)
+	assert.True(t, e.reserve("intent1", 1e9))
+	assert.True(t, e.reserve("intent2", 1e9))
+	assert.InDelta(t, 2e9, e.current(), 0.001)
+}
+
+// TestIntentAmountUSD tests the valuation of intent amounts
+func TestIntentAmountUSD(t *testing.T) {
+	intent := models.Intent{
+		SourceChain:      8453,
+		DestinationChain: 42161,
+		Token:            "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913", // USDC on Base
+		Amount:           "25000000",
+	}
+	valueUSD, err := intentAmountUSD(intent, 0)
+	require.NoError(t, err)
+	assert.InDelta(t, 25, valueUSD, 0.0001)
+
+	intent.Token = "0x0000000000000000000000000000000000000000"
+	intent.Amount = "10000000000000000" // 0.01 ETH
+	valueUSD, err = intentAmountUSD(intent, 3000)
+	require.NoError(t, err)
+	assert.InDelta(t, 30, valueUSD, 0.0001)
+
+	intent.Amount = "invalid"
+	_, err = intentAmountUSD(intent, 3000)
+	assert.Error(t, err)
+}
@@ -1,18 +1,86 @@
 package fulfiller
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"math/big"
+	"slices"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/speedrun-hq/speedrunner/pkg/chains"
+	"github.com/speedrun-hq/speedrunner/pkg/metrics"
 	"github.com/speedrun-hq/speedrunner/pkg/models"
 )
 
+// limitIntents returns at most maxIntents intents, keeping the most recent ones
+// the remaining intents are left pending and evaluated again in the next polling cycle
+func limitIntents(intents []models.Intent, maxIntents int) []models.Intent {
+	if maxIntents <= 0 || len(intents) <= maxIntents {
+		return intents
+	}
+
+	sorted := make([]models.Intent, len(intents))
+	copy(sorted, intents)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.After(sorted[j].CreatedAt)
+	})
+	return sorted[:maxIntents]
+}
+
 // filterViableIntents filters intents that are viable for fulfillment
 func (s *Fulfiller) filterViableIntents(intents []models.Intent) []models.Intent {
 	var viableIntents []models.Intent
+	balances := make(balanceCache)
+	pausedChains := make(map[int]bool)
+	dueChains := make(map[int]bool)
+	seen := make(map[intentKey]bool)
 	for _, intent := range intents {
+		// Reject malformed intents before doing any work on them
+		if err := intent.Validate(); err != nil {
+			reason := "invalid"
+			var invalid *models.InvalidIntentError
+			if errors.As(err, &invalid) {
+				reason = invalid.Reason
+			}
+			metrics.InvalidIntents.WithLabelValues(reason).Inc()
+			s.logger.Info("Skipping intent %s: Invalid intent: %v", intent.ID, err)
+			continue
+		}
+
+		// Check the intent isn't returned twice by the API, two workers would race to fulfill it
+		key := intentKey{id: intent.ID, destinationChain: intent.DestinationChain}
+		if seen[key] {
+			metrics.SkippedIntents.WithLabelValues(strconv.Itoa(intent.SourceChain), skipReasonDuplicate).Inc()
+			s.logger.Debug("Skipping intent %s: Duplicate intent to chain %d in the poll", intent.ID, intent.DestinationChain)
+			continue
+		}
+		seen[key] = true
+
+		// Check the intent comes from a source chain fulfilled from
+		if reason := s.sourceChainSkipReason(intent.SourceChain); reason != "" {
+			metrics.SkippedIntents.WithLabelValues(strconv.Itoa(intent.SourceChain), reason).Inc()
+			s.logger.Debug("Skipping intent %s: Source chain %d filtered (%s)", intent.ID, intent.SourceChain, reason)
+			continue
+		}
+
+		// Check the token of the intent is supported, unknown tokens are counted to see which tokens to add
+		if chains.GetTokenType(intent.Token) == "" {
+			token := common.HexToAddress(intent.Token).Hex()
+			metrics.UnknownTokens.WithLabelValues(token).Inc()
+			s.logger.Info("Skipping intent %s: Unknown token %s from source chain %d", intent.ID, token, intent.SourceChain)
+			continue
+		}
+
+		// Check the intents to the destination chain are processed in this poll
+		if !s.isChainProcessingDue(intent.DestinationChain, dueChains) {
+			s.logger.Debug("Skipping intent %s: Processing interval of chain %d not elapsed", intent.ID, intent.DestinationChain)
+			continue
+		}
+
 		// Check circuit breaker status
 		if breaker, exists := s.circuitBreakers[intent.DestinationChain]; exists {
 			if breaker.IsOpen() {
@@ -22,8 +90,14 @@ func (s *Fulfiller) filterViableIntents(intents []models.Intent) []models.Intent
 			}
 		}
 
-		// Check if source chain == destination chain
-		if intent.SourceChain == intent.DestinationChain {
+		// Check if the intent was recently fulfilled, the API may still report it as pending after a restart
+		if s.fulfilled.Contains(baseIntentID(intent.ID)) {
+			s.logger.Debug("Skipping intent %s: Recently fulfilled", intent.ID)
+			continue
+		}
+
+		// Check if source chain == destination chain, unless same-chain intents are fulfilled
+		if intent.SourceChain == intent.DestinationChain && !s.config.AllowSameChain {
 			s.logger.Debug("Skipping intent %s: Source and destination chains are the same: %d",
 				intent.ID, intent.SourceChain)
 			continue
@@ -31,26 +105,23 @@ func (s *Fulfiller) filterViableIntents(intents []models.Intent) []models.Intent
 
 		// Check if intent is more than 2 minutes old, only process recent intent
 		// TODO: allow to configure this in config
-		intentAge := time.Since(intent.CreatedAt)
+		intentAge := s.now().Sub(intent.CreatedAt)
 		if intentAge > 2*time.Minute {
 			s.logger.Debug("Skipping intent %s: Intent is too old (age: %s)", intent.ID, intentAge.String())
 			continue
 		}
 
-		// Check token balance
-		if !s.hasSufficientBalance(intent) {
-			s.logger.Debug("Skipping intent %s: Insufficient token balance for chain %d",
+		// Check if fulfillments are paused on the chain because of too many pending transactions
+		if s.isPendingTxLimitReached(intent.DestinationChain, pausedChains) {
+			s.logger.Debug("Skipping intent %s: Too many pending transactions on chain %d",
 				intent.ID, intent.DestinationChain)
 			continue
 		}
 
-		fee, success := new(big.Int).SetString(intent.IntentFee, 10)
-		if !success {
-			s.logger.Debug("Skipping intent %s: Error parsing intent fee: invalid format", intent.ID)
-			continue
-		}
-		if fee.Cmp(big.NewInt(0)) <= 0 {
-			s.logger.Debug("Skipping intent %s: Fee is zero or negative", intent.ID)
+		// Check token balance
+		if !s.hasSufficientBalance(intent, balances) {
+			s.logger.Debug("Skipping intent %s: Insufficient token balance for chain %d",
+				intent.ID, intent.DestinationChain)
 			continue
 		}
 
@@ -65,81 +136,245 @@ func (s *Fulfiller) filterViableIntents(intents []models.Intent) []models.Intent
 			continue
 		}
 
-		// convert fee for BSC unit difference
-		if intent.SourceChain == 56 {
-			fee = new(big.Int).Div(fee, big.NewInt(1000000000000))
-		} else if intent.DestinationChain == 56 {
-			fee = new(big.Int).Mul(fee, big.NewInt(1000000000000))
+		// Check the Intent contract of the intent is configured on the chain
+		if !destinationChainClient.HasIntentContract(intent.Contract) {
+			s.logger.Debug("Skipping intent %s: Intent contract %s not configured on chain %d",
+				intent.ID, intent.Contract, intent.DestinationChain)
+			continue
 		}
 
-		// Check if fee meets minimum requirement for the chain
-		if destinationChainClient.MinFee != nil && fee.Cmp(destinationChainClient.MinFee) < 0 {
-			s.logger.Debug("Skipping intent %s: Fee %s below minimum %s for chain %d",
-				intent.ID, fee.String(), destinationChainClient.MinFee.String(), intent.DestinationChain)
+		// Check if the token is fulfilled on the chain
+		if tokenType := chains.GetTokenType(intent.Token); !destinationChainClient.IsTokenAllowed(string(tokenType)) {
+			s.logger.Debug("Skipping intent %s: Token %s not allowed on chain %d",
+				intent.ID, tokenType, intent.DestinationChain)
 			continue
 		}
 
-		// Check if the current withdraw fee for the chain is below the intent fee
+		// Get the fee in USD and the current withdraw fee for the chain
+		tokenPriceUSD := destinationChainClient.GetStoredTokenPriceUSD()
 		currentWithdrawFeeUSD := destinationChainClient.GetWithdrawFeeUSD()
-		feeUSD, err := chains.GetStandardizedAmount(fee, intent.DestinationChain, chains.GetTokenType(intent.Token))
+		feeUSD, err := intentFeeUSD(intent, tokenPriceUSD)
 		if err != nil {
-			s.logger.Debug("Skipping intent %s: Error getting standardized amount for fee %s: %v",
-				intent.ID, fee.String(), err)
+			s.logger.Debug("Skipping intent %s: Error getting USD value of fee %s: %v",
+				intent.ID, intent.IntentFee, err)
+			continue
+		}
+
+		// Record the fee relative to the withdraw cost before the fee checks, to tune the min fees from all intents
+		if currentWithdrawFeeUSD > 0 {
+			metrics.FeeCostRatio.WithLabelValues(strconv.Itoa(intent.DestinationChain)).Observe(feeUSD / currentWithdrawFeeUSD)
+		}
+
+		// Check if fee meets minimum requirement for the chain, the min fee in USD takes precedence if set
+		minFeeUSD, err := intentMinFeeUSD(destinationChainClient.GetMinFee(), destinationChainClient.MinFeeUSD, intent, tokenPriceUSD)
+		if err != nil {
+			s.logger.Debug("Skipping intent %s: Error getting USD value of min fee: %v", intent.ID, err)
+			continue
+		}
+		if feeUSD < minFeeUSD {
+			s.logger.Debug("Skipping intent %s: Fee %.4f USD below minimum %.4f USD for chain %d",
+				intent.ID, feeUSD, minFeeUSD, intent.DestinationChain)
 			continue
 		}
+
+		// Check if the current withdraw fee for the chain, with the safety margin, is below the intent fee
 		// we skip for equal as well as an added security measure
-		if currentWithdrawFeeUSD >= feeUSD {
-			s.logger.Debug("Skipping intent %s: Current withdraw fee USD %.2f is greater than or equal to intent fee USD %.2f",
-				intent.ID, currentWithdrawFeeUSD, feeUSD)
+		if requiredFeeUSD := s.requiredFeeUSD(currentWithdrawFeeUSD); requiredFeeUSD >= feeUSD {
+			s.logger.Debug("Skipping intent %s: Current withdraw fee USD %.2f (%.2f with safety margin) is greater than or equal to intent fee USD %.2f",
+				intent.ID, currentWithdrawFeeUSD, requiredFeeUSD, feeUSD)
 			continue
 		}
 
+		// Check the value committed to intents in flight stays below the global cap
+		valueUSD, err := intentAmountUSD(intent, tokenPriceUSD)
+		if err != nil {
+			s.logger.Debug("Skipping intent %s: Error getting USD value of amount %s: %v",
+				intent.ID, intent.Amount, err)
+			continue
+		}
+		if !s.exposure.reserve(baseIntentID(intent.ID), valueUSD) {
+			s.logger.Info("Skipping intent %s: Value %.2f USD exceeds the remaining exposure (current: %.2f USD, max: %.2f USD)",
+				intent.ID, valueUSD, s.exposure.current(), s.config.MaxExposureUSD)
+			continue
+		}
+
+		balances.reserve(intent)
 		viableIntents = append(viableIntents, intent)
 	}
 	return viableIntents
 }
 
-// hasSufficientBalance checks if we have sufficient token balance for the intent
-func (s *Fulfiller) hasSufficientBalance(intent models.Intent) bool {
+// intentKey identifies an intent in a poll to de-duplicate the intents returned by the API
+type intentKey struct {
+	id               string
+	destinationChain int
+}
+
+// requiredFeeUSD returns the withdraw fee multiplied by the fee safety margin, that the intent fee must exceed
+func (s *Fulfiller) requiredFeeUSD(withdrawFeeUSD float64) float64 {
+	if s.config.FeeSafetyMargin > 1 {
+		return withdrawFeeUSD * s.config.FeeSafetyMargin
+	}
+	return withdrawFeeUSD
+}
+
+// Reasons intents are skipped because of their source chain, or because they are duplicated in a poll
+const (
+	skipReasonSourceChainBlocked    = "source_chain_blocked"
+	skipReasonSourceChainNotAllowed = "source_chain_not_allowed"
+	skipReasonDuplicate             = "duplicate"
+)
+
+// sourceChainSkipReason returns why intents from the source chain are skipped, or empty if they can be fulfilled
+// the blocked chains take precedence over the allowed chains
+func (s *Fulfiller) sourceChainSkipReason(chainID int) string {
+	if slices.Contains(s.config.BlockedSourceChains, chainID) {
+		return skipReasonSourceChainBlocked
+	}
+	if s.config.AllowedSourceChains != nil && !slices.Contains(s.config.AllowedSourceChains, chainID) {
+		return skipReasonSourceChainNotAllowed
+	}
+	return ""
+}
+
+// isChainProcessingDue checks if the processing interval of the chain elapsed since the last poll processing it,
+// the result is computed once per chain for a filter pass and cached in dueChains
+func (s *Fulfiller) isChainProcessingDue(chainID int, dueChains map[int]bool) bool {
+	if due, checked := dueChains[chainID]; checked {
+		return due
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Get token type from address
-	tokenType := chains.GetTokenType(intent.Token)
-	if tokenType == "" {
-		s.logger.DebugWithChain(intent.DestinationChain, "Unknown token type for address %s", intent.Token)
-		return false
+	chainClient, exists := s.chainClients[chainID]
+	if !exists || chainClient.ProcessingInterval <= 0 {
+		dueChains[chainID] = true
+		return true
 	}
 
-	// Get token for the destination chain
-	token := chains.GetTokenEthAddress(intent.DestinationChain, tokenType)
-	if token == (common.Address{}) {
-		s.logger.DebugWithChain(intent.DestinationChain, "Invalid token address for %s", tokenType)
+	now := s.now()
+	due := now.Sub(s.chainProcessedAt[chainID]) >= chainClient.ProcessingInterval
+	if due {
+		if s.chainProcessedAt == nil {
+			s.chainProcessedAt = make(map[int]time.Time)
+		}
+		s.chainProcessedAt[chainID] = now
+	}
+	dueChains[chainID] = due
+	return due
+}
+
+// isPendingTxLimitReached checks if the pending transactions of the chain reached the configured maximum
+// the result is computed once per chain for a filter pass and cached in pausedChains
+func (s *Fulfiller) isPendingTxLimitReached(chainID int, pausedChains map[int]bool) bool {
+	if paused, checked := pausedChains[chainID]; checked {
+		return paused
+	}
+
+	s.mu.Lock()
+	chainClient, exists := s.chainClients[chainID]
+	s.mu.Unlock()
+	if !exists {
 		return false
 	}
 
-	// Get token balance
-	balance, err := s.getTokenBalance(intent.DestinationChain, token)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	pending, err := chainClient.PendingTxCount(ctx)
 	if err != nil {
-		s.logger.DebugWithChain(intent.DestinationChain, "Error getting token balance: %v", err)
+		// don't block fulfillments on a failed check, the transaction would fail anyway if the node is down
+		s.logger.DebugWithChain(chainID, "Error getting pending transactions: %v", err)
+		pausedChains[chainID] = false
 		return false
 	}
+	metrics.PendingTransactions.WithLabelValues(strconv.Itoa(chainID)).Set(float64(pending))
+
+	paused := pending >= chainClient.MaxPendingTx
+	if paused {
+		s.logger.ErrorWithChain(chainID, "Pausing fulfillments: %d pending transactions (max: %d)", pending, chainClient.MaxPendingTx)
+	}
+	pausedChains[chainID] = paused
+	return paused
+}
+
+// balanceKey identifies a balance of the fulfiller, the zero token address is used for the native token
+type balanceKey struct {
+	chainID int
+	token   common.Address
+}
+
+// balanceCache holds the balances fetched during a single filter pass, so each (chain, token) balance is
+// fetched once, the amounts of the intents selected in the pass are reserved to not count a balance twice
+type balanceCache map[balanceKey]*big.Float
+
+// intentBalanceKey returns the balance used to fulfill the intent and the amount required
+func intentBalanceKey(intent models.Intent) (balanceKey, *big.Float, error) {
+	// Get token type from address
+	tokenType := chains.GetTokenType(intent.Token)
+	if tokenType == "" {
+		return balanceKey{}, nil, fmt.Errorf("unknown token type for address %s", intent.Token)
+	}
 
 	// Convert intent amount to big.Int
 	amount, success := new(big.Int).SetString(intent.Amount, 10)
 	if !success {
-		s.logger.DebugWithChain(intent.DestinationChain, "Error parsing intent amount: %s", intent.Amount)
+		return balanceKey{}, nil, fmt.Errorf("error parsing intent amount: %s", intent.Amount)
+	}
+
+	// convert amount for the token decimals difference between the chains
+	amount = convertTokenUnits(amount, intent)
+
+	// Native token intents are fulfilled from the native balance
+	key := balanceKey{chainID: intent.DestinationChain}
+	if tokenType != chains.TokenTypeNative {
+		// Get token for the destination chain
+		key.token = chains.GetTokenEthAddress(intent.DestinationChain, tokenType)
+		if key.token == (common.Address{}) {
+			return balanceKey{}, nil, fmt.Errorf("invalid token address for %s", tokenType)
+		}
+	}
+
+	return key, new(big.Float).SetInt(amount), nil
+}
+
+// hasSufficientBalance checks if we have sufficient token balance for the intent
+// balances are fetched once per filter pass and cached in balances
+func (s *Fulfiller) hasSufficientBalance(intent models.Intent, balances balanceCache) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, amount, err := intentBalanceKey(intent)
+	if err != nil {
+		s.logger.DebugWithChain(intent.DestinationChain, "Cannot check balance: %v", err)
 		return false
 	}
 
-	// convert amount for BSC unit difference
-	if intent.SourceChain == 56 {
-		amount = new(big.Int).Div(amount, big.NewInt(1000000000000))
-	} else if intent.DestinationChain == 56 {
-		amount = new(big.Int).Mul(amount, big.NewInt(1000000000000))
+	balance, cached := balances[key]
+	if !cached {
+		if key.token == (common.Address{}) {
+			balance, err = s.getNativeBalance(key.chainID)
+		} else {
+			balance, err = s.getTokenBalance(key.chainID, key.token)
+		}
+		if err != nil {
+			s.logger.DebugWithChain(intent.DestinationChain, "Error getting token balance: %v", err)
+			return false
+		}
+		balances[key] = balance
 	}
 
 	// Check if we have sufficient balance
-	amountFloat := new(big.Float).SetInt(amount)
-	return balance.Cmp(amountFloat) >= 0
+	return balance.Cmp(amount) >= 0
+}
+
+// reserve deducts the amount of a selected intent from the cached balance
+func (b balanceCache) reserve(intent models.Intent) {
+	key, amount, err := intentBalanceKey(intent)
+	if err != nil {
+		return
+	}
+	if balance, ok := b[key]; ok {
+		b[key] = new(big.Float).Sub(balance, amount)
+	}
 }
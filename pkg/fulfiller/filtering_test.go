@@ -0,0 +1,444 @@
+package fulfiller
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/speedrun-hq/speedrunner/pkg/chainclient"
+	"github.com/speedrun-hq/speedrunner/pkg/chains"
+	"github.com/speedrun-hq/speedrunner/pkg/circuitbreaker"
+	"github.com/speedrun-hq/speedrunner/pkg/clock"
+	"github.com/speedrun-hq/speedrunner/pkg/config"
+	"github.com/speedrun-hq/speedrunner/pkg/logger"
+	"github.com/speedrun-hq/speedrunner/pkg/metrics"
+	"github.com/speedrun-hq/speedrunner/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLimitIntents verifies the most recent intents are kept when the cycle limit is reached
+func TestLimitIntents(t *testing.T) {
+	now := time.Now()
+	intents := []models.Intent{
+		{ID: "old", CreatedAt: now.Add(-3 * time.Minute)},
+		{ID: "newest", CreatedAt: now},
+		{ID: "recent", CreatedAt: now.Add(-1 * time.Minute)},
+	}
+
+	limited := limitIntents(intents, 2)
+	assert.Len(t, limited, 2)
+	assert.Equal(t, "newest", limited[0].ID)
+	assert.Equal(t, "recent", limited[1].ID)
+
+	// original order is preserved
+	assert.Equal(t, "old", intents[0].ID)
+
+	// no limit when below the cap
+	assert.Len(t, limitIntents(intents, 5), 3)
+	assert.Len(t, limitIntents(intents, 0), 3)
+}
+
+// TestBalanceCacheReservation verifies cached balances are shared between intents and reduced by selected intents
+func TestBalanceCacheReservation(t *testing.T) {
+	s, _ := newTestFulfiller(nil)
+	intent := testIntent(func(i *models.Intent) { i.Amount = "600" })
+
+	key, amount, err := intentBalanceKey(intent)
+	require.NoError(t, err)
+	assert.Equal(t, chains.GetTokenEthAddress(42161, chains.TokenTypeUSDC), key.token)
+	assert.Equal(t, 0, amount.Cmp(big.NewFloat(600)))
+
+	// balance already fetched in the pass, no RPC call is made
+	balances := balanceCache{key: big.NewFloat(1000)}
+	assert.True(t, s.hasSufficientBalance(intent, balances))
+
+	// once reserved, the remaining balance can't cover the same amount again
+	balances.reserve(intent)
+	assert.False(t, s.hasSufficientBalance(intent, balances))
+	assert.Equal(t, 0, balances[key].Cmp(big.NewFloat(400)))
+}
+
+// TestSourceChainSkipReason verifies the allowed and blocked source chain lists
+func TestSourceChainSkipReason(t *testing.T) {
+	s := &Fulfiller{config: &config.Config{}}
+	assert.Empty(t, s.sourceChainSkipReason(1))
+
+	s.config.AllowedSourceChains = []int{1, 8453}
+	assert.Empty(t, s.sourceChainSkipReason(8453))
+	assert.Equal(t, skipReasonSourceChainNotAllowed, s.sourceChainSkipReason(56))
+
+	// blocked chains take precedence
+	s.config.BlockedSourceChains = []int{8453}
+	assert.Equal(t, skipReasonSourceChainBlocked, s.sourceChainSkipReason(8453))
+	assert.Empty(t, s.sourceChainSkipReason(1))
+
+	s.config.AllowedSourceChains = nil
+	assert.Equal(t, skipReasonSourceChainBlocked, s.sourceChainSkipReason(8453))
+	assert.Empty(t, s.sourceChainSkipReason(56))
+}
+
+// TestRequiredFeeUSD verifies the fee safety margin is applied to the withdraw fee
+func TestRequiredFeeUSD(t *testing.T) {
+	s := &Fulfiller{config: &config.Config{}}
+	assert.InDelta(t, 0.5, s.requiredFeeUSD(0.5), 1e-9)
+
+	s.config.FeeSafetyMargin = 1
+	assert.InDelta(t, 0.5, s.requiredFeeUSD(0.5), 1e-9)
+
+	s.config.FeeSafetyMargin = 1.2
+	assert.InDelta(t, 0.6, s.requiredFeeUSD(0.5), 1e-9)
+}
+
+// TestChainProcessingInterval verifies chains with a processing interval are only processed once per interval
+func TestChainProcessingInterval(t *testing.T) {
+	s := &Fulfiller{
+		chainClients: map[int]*chainclient.Client{
+			8453:  {ChainID: 8453},
+			42161: {ChainID: 42161, ProcessingInterval: time.Hour},
+		},
+	}
+
+	// the result is the same for all intents of a filter pass
+	dueChains := make(map[int]bool)
+	assert.True(t, s.isChainProcessingDue(42161, dueChains))
+	assert.True(t, s.isChainProcessingDue(42161, dueChains))
+	assert.True(t, s.isChainProcessingDue(8453, dueChains))
+	assert.True(t, s.isChainProcessingDue(1, dueChains))
+
+	// the next pass is within the interval
+	dueChains = make(map[int]bool)
+	assert.False(t, s.isChainProcessingDue(42161, dueChains))
+	assert.True(t, s.isChainProcessingDue(8453, dueChains))
+
+	// the interval elapsed
+	s.chainProcessedAt[42161] = time.Now().Add(-time.Hour)
+	assert.True(t, s.isChainProcessingDue(42161, make(map[int]bool)))
+}
+
+// TestFilterSkipReasons verifies intents are skipped with the reason logged
+func TestFilterSkipReasons(t *testing.T) {
+	s, log := newTestFulfiller(&config.Config{BlockedSourceChains: []int{56}})
+	breaker := circuitbreaker.NewCircuitBreaker(137, true, 1, time.Minute, time.Minute, 0, 0, log)
+	breaker.RecordFailure()
+	s.circuitBreakers = map[int]*circuitbreaker.CircuitBreaker{137: breaker}
+
+	tests := []struct {
+		name    string
+		modify  func(*models.Intent)
+		level   logger.Level
+		message string
+	}{
+		{"invalid intent", func(i *models.Intent) { i.Amount = "abc" }, logger.InfoLevel, "Invalid intent: invalid_amount"},
+		{"native asset mismatch", func(i *models.Intent) {
+			i.Token, i.SourceChain, i.DestinationChain = "0x0000000000000000000000000000000000000000", 1, 56
+		}, logger.InfoLevel, "Invalid intent: native_asset_mismatch"},
+		{"zero recipient", func(i *models.Intent) { i.Recipient = "0x0000000000000000000000000000000000000000" }, logger.InfoLevel, "Invalid intent: zero_recipient"},
+		{"unknown token", func(i *models.Intent) { i.Token = "0x1111111111111111111111111111111111111111" }, logger.InfoLevel, "Unknown token 0x1111111111111111111111111111111111111111 from source chain 8453"},
+		{"blocked source chain", func(i *models.Intent) { i.SourceChain = 56 }, logger.DebugLevel, "source_chain_blocked"},
+		{"circuit breaker open", func(i *models.Intent) { i.DestinationChain = 137 }, logger.InfoLevel, "Circuit breaker is open for chain 137"},
+		{"same chain", func(i *models.Intent) { i.DestinationChain = 8453 }, logger.DebugLevel, "Source and destination chains are the same"},
+		{"too old", func(i *models.Intent) { i.CreatedAt = time.Now().Add(-time.Hour) }, logger.DebugLevel, "Intent is too old"},
+		{"no balance", func(i *models.Intent) {}, logger.DebugLevel, "Insufficient token balance for chain 42161"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log.Reset()
+			intent := testIntent(tt.modify)
+
+			assert.Empty(t, s.filterViableIntents([]models.Intent{intent}))
+			assert.True(t, log.Contains(tt.level, "Skipping intent "+intent.ID+": "), "no skip logged: %v", log.Entries())
+			assert.True(t, log.Contains(tt.level, tt.message), "missing %q in %v", tt.message, log.Entries())
+		})
+	}
+}
+
+// TestFilterUnknownToken verifies intents with an unknown token are counted by token address
+func TestFilterUnknownToken(t *testing.T) {
+	s, _ := newTestFulfiller(nil)
+	token := "0x2222222222222222222222222222222222222222"
+	intent := testIntent(func(i *models.Intent) { i.Token = token })
+
+	counter := metrics.UnknownTokens.WithLabelValues(token)
+	before := testutil.ToFloat64(counter)
+	assert.Empty(t, s.filterViableIntents([]models.Intent{intent}))
+	assert.Equal(t, before+1, testutil.ToFloat64(counter))
+}
+
+// TestFilterAllowSameChain verifies same-chain intents are only skipped if not allowed
+func TestFilterAllowSameChain(t *testing.T) {
+	s, log := newTestFulfiller(&config.Config{AllowSameChain: true})
+	intent := testIntent(func(i *models.Intent) { i.DestinationChain = 8453 })
+
+	// the intent goes through to the next checks
+	assert.Empty(t, s.filterViableIntents([]models.Intent{intent}))
+	assert.False(t, log.Contains(logger.DebugLevel, "Source and destination chains are the same"), "%v", log.Entries())
+	assert.True(t, log.Contains(logger.DebugLevel, "Insufficient token balance for chain 8453"), "%v", log.Entries())
+}
+
+// TestFilterDuplicateIntents verifies an intent returned twice in a poll is only processed once,
+// intents are duplicates when they share their ID and destination chain
+func TestFilterDuplicateIntents(t *testing.T) {
+	tests := []struct {
+		name      string
+		modify    func(*models.Intent)
+		duplicate bool
+	}{
+		{"same intent", func(i *models.Intent) {}, true},
+		{"same ID and destination chain", func(i *models.Intent) { i.Amount = "2000000" }, true},
+		{"other destination chain", func(i *models.Intent) { i.DestinationChain = 137 }, false},
+		{"other ID", func(i *models.Intent) {
+			i.ID = "0x5c4f2b2f3d7f5c9b0e1f2a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f"
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, log := newTestFulfiller(nil)
+			assert.Empty(t, s.filterViableIntents([]models.Intent{testIntent(), testIntent(tt.modify)}))
+
+			var duplicates, processed int
+			for _, entry := range log.Entries() {
+				if strings.Contains(entry.Message, "Duplicate intent") {
+					duplicates++
+				}
+				if strings.Contains(entry.Message, "Insufficient token balance") {
+					processed++
+				}
+			}
+			if tt.duplicate {
+				assert.Equal(t, 1, duplicates, "%v", log.Entries())
+				assert.Equal(t, 1, processed, "%v", log.Entries())
+			} else {
+				assert.Zero(t, duplicates, "%v", log.Entries())
+				assert.Equal(t, 2, processed, "%v", log.Entries())
+			}
+		})
+	}
+}
+
+// TestConvertTokenUnitsSameChain verifies amounts of same-chain intents are not converted, both sides use the same token
+func TestConvertTokenUnitsSameChain(t *testing.T) {
+	amount := big.NewInt(1000000000000000000)
+	usdt := "0x55d398326f99059fF775485246999027B3197955" // USDT on BSC
+
+	assert.Equal(t, amount, convertTokenUnits(amount, models.Intent{SourceChain: 56, DestinationChain: 56, Token: usdt}))
+	assert.Equal(t, big.NewInt(1000000), convertTokenUnits(amount, models.Intent{SourceChain: 56, DestinationChain: 8453, Token: usdt}))
+}
+
+// fakeBalanceService serves the token balances of the fulfiller from balanceOf calls by token address,
+// tokens without a balance return defaultBalance
+type fakeBalanceService struct {
+	balances       map[common.Address]*big.Int
+	defaultBalance *big.Int
+}
+
+func (f *fakeBalanceService) Call(args map[string]interface{}, _ string) (hexutil.Bytes, error) {
+	to, _ := args["to"].(string)
+	balance, ok := f.balances[common.HexToAddress(to)]
+	if !ok {
+		balance = f.defaultBalance
+	}
+	return common.LeftPadBytes(balance.Bytes(), 32), nil
+}
+
+func (f *fakeBalanceService) GetBalance(_ common.Address, _ string) *hexutil.Big {
+	return (*hexutil.Big)(f.defaultBalance)
+}
+
+// TestFilterViableIntentsDecisions verifies each skip reason of the filter and the accepted intents,
+// including the unit conversion of intents from and to BSC
+func TestFilterViableIntentsDecisions(t *testing.T) {
+	usdcBSC := "0x8AC76a51cc950d9822D68b83fE1Ad97B32Cd580d"
+	usdtBase := "0x50c5725949A6F0c72E6C4a641F24049A917DB0Cb"
+	usdcArbitrum := "0xaf88d065e77c8cC2239327C5EDb3A432268e5831"
+	oneUSDC := big.NewInt(1_000_000)
+	oneBSCUSDC := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	usdc := func(amount int64) string { return new(big.Int).Mul(oneUSDC, big.NewInt(amount)).String() }
+	bscUSDC := func(amount int64) string { return new(big.Int).Mul(oneBSCUSDC, big.NewInt(amount)).String() }
+
+	// 100 USDC on every chain but BSC, 10 USDC on BSC
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", &fakeBalanceService{
+		balances:       map[common.Address]*big.Int{common.HexToAddress(usdcBSC): new(big.Int).Mul(oneBSCUSDC, big.NewInt(10))},
+		defaultBalance: new(big.Int).Mul(oneUSDC, big.NewInt(100)),
+	}))
+	client := ethclient.NewClient(rpc.DialInProc(server))
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+
+	newFulfiller := func() (*Fulfiller, *logger.MemoryLogger) {
+		s, log := newTestFulfiller(&config.Config{
+			FulfillerAddress: "0x2222222222222222222222222222222222222222",
+			MaxExposureUSD:   50,
+		})
+		breaker := circuitbreaker.NewCircuitBreaker(137, true, 1, time.Minute, time.Minute, 0, 0, log)
+		breaker.RecordFailure()
+
+		chainClient := func(chainID int) *chainclient.Client {
+			return &chainclient.Client{
+				ChainID:        chainID,
+				Client:         client,
+				IntentAddress:  "0x1111111111111111111111111111111111111111",
+				MinFee:         big.NewInt(0),
+				MinFeeUSD:      0.1,
+				WithdrawFeeUSD: 0.2,
+			}
+		}
+		arbitrum := chainClient(42161)
+		arbitrum.AllowedTokens = []string{"USDC"}
+
+		s.chainClients = map[int]*chainclient.Client{
+			42161: arbitrum,
+			8453:  chainClient(8453),
+			56:    chainClient(56),
+			137:   chainClient(137),
+		}
+		s.circuitBreakers = map[int]*circuitbreaker.CircuitBreaker{137: breaker}
+		s.exposure = newExposureTracker(50)
+		return s, log
+	}
+
+	// 0.5 USDC fee, above the min and withdraw fees of the chains
+	withFee := func(i *models.Intent) { i.IntentFee = "500000" }
+
+	tests := []struct {
+		name    string
+		modify  func(*models.Intent)
+		message string // logged skip reason, empty if the intent is viable
+	}{
+		{
+			name:   "viable",
+			modify: func(i *models.Intent) {},
+		},
+		{
+			name:    "invalid fee",
+			modify:  func(i *models.Intent) { i.IntentFee = "abc" },
+			message: "Invalid intent: invalid_intent_fee",
+		},
+		{
+			name:    "circuit breaker open",
+			modify:  func(i *models.Intent) { i.DestinationChain = 137 },
+			message: "Circuit breaker is open for chain 137",
+		},
+		{
+			name:    "same chain",
+			modify:  func(i *models.Intent) { i.DestinationChain = 8453 },
+			message: "Source and destination chains are the same",
+		},
+		{
+			name:    "too old",
+			modify:  func(i *models.Intent) { i.CreatedAt = time.Now().Add(-time.Hour) },
+			message: "Intent is too old",
+		},
+		{
+			name:    "insufficient balance",
+			modify:  func(i *models.Intent) { i.Amount = usdc(101) },
+			message: "Insufficient token balance for chain 42161",
+		},
+		{
+			name:    "intent contract not configured",
+			modify:  func(i *models.Intent) { i.Contract = "0x3333333333333333333333333333333333333333" },
+			message: "not configured on chain 42161",
+		},
+		{
+			name:    "token not allowed",
+			modify:  func(i *models.Intent) { i.Token = usdtBase },
+			message: "Token USDT not allowed on chain 42161",
+		},
+		{
+			name:    "fee below min fee",
+			modify:  func(i *models.Intent) { i.IntentFee = "50000" },
+			message: "below minimum 0.1000 USD for chain 42161",
+		},
+		{
+			name:    "fee below withdraw fee",
+			modify:  func(i *models.Intent) { i.IntentFee = "150000" },
+			message: "Current withdraw fee USD 0.20",
+		},
+		{
+			name:    "exposure exceeded",
+			modify:  func(i *models.Intent) { i.Amount = usdc(60) },
+			message: "exceeds the remaining exposure",
+		},
+		{
+			name: "from BSC converted to 6 decimals",
+			modify: func(i *models.Intent) {
+				i.SourceChain, i.Token = 56, usdcBSC
+				i.Amount, i.IntentFee = bscUSDC(40), bscUSDC(1)
+			},
+		},
+		{
+			name: "from BSC above balance after conversion",
+			modify: func(i *models.Intent) {
+				i.SourceChain, i.Token = 56, usdcBSC
+				i.Amount, i.IntentFee = bscUSDC(101), bscUSDC(1)
+			},
+			message: "Insufficient token balance for chain 42161",
+		},
+		{
+			name: "to BSC converted to 18 decimals",
+			modify: func(i *models.Intent) {
+				i.SourceChain, i.DestinationChain, i.Token = 42161, 56, usdcArbitrum
+				i.Amount, i.IntentFee = usdc(5), usdc(1)
+			},
+		},
+		{
+			name: "to BSC above balance after conversion",
+			modify: func(i *models.Intent) {
+				i.SourceChain, i.DestinationChain, i.Token = 42161, 56, usdcArbitrum
+				i.Amount, i.IntentFee = usdc(11), usdc(1)
+			},
+			message: "Insufficient token balance for chain 56",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, log := newFulfiller()
+			intent := testIntent(withFee, tt.modify)
+
+			viable := s.filterViableIntents([]models.Intent{intent})
+			if tt.message == "" {
+				require.Len(t, viable, 1, "%v", log.Entries())
+				assert.Equal(t, intent, viable[0])
+				return
+			}
+			assert.Empty(t, viable)
+			var logged bool
+			for _, entry := range log.Entries() {
+				if strings.Contains(entry.Message, "Skipping intent "+intent.ID+": ") && strings.Contains(entry.Message, tt.message) {
+					logged = true
+				}
+			}
+			assert.True(t, logged, "missing %q in %v", tt.message, log.Entries())
+		})
+	}
+}
+
+// TestFilterIntentAge verifies intents are skipped once older than 2 minutes on the fulfiller clock
+func TestFilterIntentAge(t *testing.T) {
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := clock.NewFake(createdAt.Add(2 * time.Minute))
+	s, log := newTestFulfiller(nil)
+	s.clock = now
+	intent := testIntent(func(i *models.Intent) { i.CreatedAt = createdAt })
+
+	// exactly 2 minutes old, the intent goes through to the next checks
+	assert.Empty(t, s.filterViableIntents([]models.Intent{intent}))
+	assert.False(t, log.Contains(logger.DebugLevel, "Intent is too old"), "%v", log.Entries())
+
+	now.Advance(time.Second)
+	log.Reset()
+	assert.Empty(t, s.filterViableIntents([]models.Intent{intent}))
+	assert.True(t, log.Contains(logger.DebugLevel, "Intent is too old (age: 2m1s)"), "%v", log.Entries())
+}
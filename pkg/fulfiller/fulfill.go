@@ -2,44 +2,166 @@ package fulfiller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"strings"
+	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/speedrun-hq/speedrunner/pkg/chainclient"
 	"github.com/speedrun-hq/speedrunner/pkg/chains"
 	"github.com/speedrun-hq/speedrunner/pkg/contracts"
 	"github.com/speedrun-hq/speedrunner/pkg/metrics"
 	"github.com/speedrun-hq/speedrunner/pkg/models"
 )
 
+// confirmationPollInterval is the interval between checks of the chain head while waiting for confirmations
+const confirmationPollInterval = 2 * time.Second
+
+// errFulfillTimeout is returned when a fulfillment doesn't complete before its deadline
+var errFulfillTimeout = errors.New("fulfillment timed out")
+
+// errTxReorged is returned when a mined transaction is no longer included in the chain after its confirmations
+var errTxReorged = errors.New("transaction reorged")
+
 // fulfillIntent attempts to fulfill a single intent
-func (s *Fulfiller) fulfillIntent(ctx context.Context, intent models.Intent) error {
+func (s *Fulfiller) fulfillIntent(ctx context.Context, intent models.Intent) (*models.FulfillmentResult, error) {
 	s.mu.Lock()
 	chainClient, exists := s.chainClients[intent.DestinationChain]
 	s.mu.Unlock()
 
 	if !exists {
-		return fmt.Errorf("destination chain configuration not found for: %d", intent.DestinationChain)
+		return nil, fmt.Errorf("destination chain configuration not found for: %d", intent.DestinationChain)
+	}
+
+	if err := s.updateGasPrice(ctx, chainClient, intent.DestinationChain); err != nil {
+		return nil, err
+	}
+
+	// Convert intent ID to bytes32
+	intentID := common.HexToHash(intent.ID)
+
+	amount, err := intentAmount(intent)
+	if err != nil {
+		return nil, err
 	}
 
-	// Update gas price before transaction
+	s.logger.InfoWithChain(intent.DestinationChain, "Fulfilling intent %s with amount %s", intent.ID, amount.String())
+
+	// Get the token type from token address
+	tokenType := chains.GetTokenType(intent.Token)
+	if tokenType == "" {
+		return nil, fmt.Errorf("unknown token %s in intent: %s", intent.Token, intent.ID)
+	}
+
+	receiver := s.intentReceiver(chainClient, intent, tokenType)
+
+	// Get the Intent contract the intent is fulfilled through
+	intentAddress, err := chainClient.ResolveIntentAddress(intent.Contract)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenAddress := chains.GetTokenEthAddress(intent.DestinationChain, tokenType)
+	s.logger.DebugWithChain(intent.DestinationChain, "Using token %s address %s",
+		tokenType, tokenAddress.Hex(),
+	)
+
+	// Apply current gas price to transactor
+	opts, err := chainClient.TransactOpts()
+	if err != nil {
+		return nil, err
+	}
+	txOpts := *opts
+
+	// Bump the gas price of retries so that they don't fail identically
+	if bumped := s.bumpedGasPrice(chainClient, intent, txOpts.GasPrice); bumped != nil {
+		txOpts.GasPrice = bumped
+	}
+
+	result := &models.FulfillmentResult{}
+
+	if tokenType == chains.TokenTypeNative {
+		// Native token is sent as the transaction value, no approval required
+		s.logger.DebugWithChain(intent.DestinationChain, "Native token intent %s, sending %s as value", intent.ID, amount.String())
+		txOpts.Value = amount
+	} else if err := s.approveToken(ctx, chainClient, intent, intentAddress, tokenAddress, amount, &txOpts, result); err != nil {
+		return nil, err
+	}
+
+	// Now call the contract's fulfill function with current gas price
+	s.logger.NoticeWithChain(intent.DestinationChain, "Initiating fulfillment for intent %s (token: %s, amount: %s, receiver: %s)",
+		intent.ID, tokenAddress.Hex(), amount.String(), receiver.Hex())
+
+	restoreNonce := s.applyReleasedNonce(chainClient, &txOpts)
+	details := fulfillTx{
+		intentIDs:     []string{intent.ID},
+		intentAddress: intentAddress,
+		token:         tokenAddress,
+		amount:        amount,
+		receiver:      receiver,
+		opts:          &txOpts,
+	}
+	tx, err := s.txSender(chainClient).Fulfill(&txOpts, intentAddress, intentID, tokenAddress, amount, receiver)
+	if err != nil {
+		restoreNonce()
+		s.logger.ErrorWithChain(intent.DestinationChain, "Failed to create fulfillment transaction for intent %s: %v", intent.ID, err)
+		s.logTxFailure(intent.DestinationChain, details, nil, err)
+		return nil, fmt.Errorf("failed to fulfill intent on %d: %w", intent.DestinationChain, err)
+	}
+
+	s.logger.InfoWithChain(intent.DestinationChain, "Fulfillment transaction created for intent %s: %s", intent.ID, tx.Hash().Hex())
+
+	// Wait for the transaction to be mined
+	receipt, err := s.waitMined(ctx, chainClient, tx)
+	if err != nil {
+		s.logger.ErrorWithChain(intent.DestinationChain, "Failed to wait for transaction on intent %s: %v", intent.ID, err)
+		s.logTxFailure(intent.DestinationChain, details, tx, err)
+		return nil, fmt.Errorf("failed to wait for transaction on %d: %w", intent.DestinationChain, err)
+	}
+
+	if receipt.Status == 0 {
+		s.logger.ErrorWithChain(intent.DestinationChain, "Fulfillment transaction failed for intent %s: %s", intent.ID, tx.Hash().Hex())
+		err := fmt.Errorf("transaction failed on %d", intent.DestinationChain)
+		s.logTxFailure(intent.DestinationChain, details, tx, err)
+		return nil, err
+	}
+
+	s.logger.NoticeWithChain(intent.DestinationChain, "Fulfillment transaction successful for intent %s: %s", intent.ID, tx.Hash().Hex())
+
+	result.TxHash = tx.Hash().Hex()
+	result.GasUsed = receipt.GasUsed
+	result.GasPrice = receiptGasPrice(receipt, tx)
+	if receipt.BlockNumber != nil {
+		result.BlockNumber = receipt.BlockNumber.Uint64()
+	}
+	result.BlockHash = receipt.BlockHash.Hex()
+	return result, nil
+}
+
+// updateGasPrice updates the gas price of the chain before a fulfillment,
+// it returns an error if the gas price can't be fetched or exceeds the max gas price of the chain
+func (s *Fulfiller) updateGasPrice(ctx context.Context, chainClient *chainclient.Client, chainID int) error {
 	finalGasPrice, err := chainClient.UpdateGasPrice(ctx)
 	if err != nil {
-		s.logger.ErrorWithChain(intent.DestinationChain, "Failed to update gas price: %v", err)
-		return fmt.Errorf("failed to update gas price on %d: %v", intent.DestinationChain, err)
+		s.logger.ErrorWithChain(chainID, "Failed to update gas price: %v", err)
+		return fmt.Errorf("failed to update gas price on %d: %w", chainID, err)
 	} else if finalGasPrice == nil {
-		s.logger.DebugWithChain(intent.DestinationChain, "Fetched gas price is nil")
+		s.logger.DebugWithChain(chainID, "Fetched gas price is nil")
 		// Continue with default/previous gas price
 	} else if finalGasPrice.Cmp(big.NewInt(0)) <= 0 {
-		s.logger.DebugWithChain(intent.DestinationChain, "Fetched gas price is zero or negative: %s", finalGasPrice.String())
+		s.logger.DebugWithChain(chainID, "Fetched gas price is zero or negative: %s", finalGasPrice.String())
 	} else {
 		// Guardrail: ensure we never proceed over the configured max gas price
 		if !chainClient.IsWithinMax(finalGasPrice) {
-			s.logger.ErrorWithChain(intent.DestinationChain, "Aborting fulfill: gas price too high after multiplier %s > %s", finalGasPrice.String(), chainClient.MaxGasPrice.String())
-			return fmt.Errorf("gas price %s exceeds max %s", finalGasPrice.String(), chainClient.MaxGasPrice.String())
+			s.logger.ErrorWithChain(chainID, "Aborting fulfill: gas price too high after multiplier %s > %s", finalGasPrice.String(), chainClient.GetMaxGasPrice())
+			return fmt.Errorf("gas price %s exceeds max %s", finalGasPrice.String(), chainClient.GetMaxGasPrice())
 		}
 
 		// Update metric (convert to gwei for readability)
@@ -48,48 +170,46 @@ func (s *Fulfiller) fulfillIntent(ctx context.Context, intent models.Intent) err
 			big.NewFloat(1e9), // 1 gwei = 10^9 wei
 		)
 		gweiFlt, _ := gasPriceGwei.Float64()
-		metrics.GasPrice.WithLabelValues(fmt.Sprintf("%d", intent.DestinationChain)).Set(gweiFlt)
-		s.logger.DebugWithChain(intent.DestinationChain, "Updated gas price: %.2f gwei", gweiFlt)
+		metrics.GasPrice.WithLabelValues(fmt.Sprintf("%d", chainID)).Set(gweiFlt)
+		s.logger.DebugWithChain(chainID, "Updated gas price: %.2f gwei", gweiFlt)
 	}
+	return nil
+}
 
-	// Convert intent ID to bytes32
-	intentID := common.HexToHash(intent.ID)
-
-	// Convert amount to big.Int
+// intentAmount returns the amount of the intent in the units of the destination chain
+func intentAmount(intent models.Intent) (*big.Int, error) {
 	amount, ok := new(big.Int).SetString(intent.Amount, 10)
 	if !ok {
-		return fmt.Errorf("invalid amount: %s", intent.Amount)
+		return nil, fmt.Errorf("invalid amount: %s", intent.Amount)
 	}
 
-	// convert for BSC unit difference
-	// TODO: use the token decimal attribute to convert amounts correctly
-	if intent.SourceChain == 56 {
-		amount = new(big.Int).Div(amount, big.NewInt(1000000000000))
-	} else if intent.DestinationChain == 56 {
-		amount = new(big.Int).Mul(amount, big.NewInt(1000000000000))
-	}
-
-	s.logger.InfoWithChain(intent.DestinationChain, "Fulfilling intent %s with amount %s", intent.ID, amount.String())
-
-	// Convert addresses
-	receiver := common.HexToAddress(intent.Recipient)
-
-	// Get the Intent contract address
-	intentAddress := common.HexToAddress(chainClient.IntentAddress)
+	// convert for the token decimals difference between the chains
+	return convertTokenUnits(amount, intent), nil
+}
 
-	// Get the token type from token address
-	tokenType := chains.GetTokenType(intent.Token)
-	if tokenType == "" {
-		return fmt.Errorf("token type not specified in intent: %s", intent.ID)
+// intentReceiver returns the address receiving the tokens of the intent, the receiver may be substituted
+// by an address configured for the chain and token, see chainclient.Client.ReceiverOverrides for the implications
+func (s *Fulfiller) intentReceiver(chainClient *chainclient.Client, intent models.Intent, tokenType chains.TokenType) common.Address {
+	recipient := common.HexToAddress(intent.Recipient)
+	receiver := chainClient.Receiver(string(tokenType), recipient)
+	if receiver != recipient {
+		s.logger.InfoWithChain(intent.DestinationChain, "Sending intent %s to receiver override %s instead of recipient %s",
+			intent.ID, receiver.Hex(), recipient.Hex())
 	}
+	return receiver
+}
 
-	tokenAddress := chains.GetTokenEthAddress(intent.DestinationChain, tokenType)
-	s.logger.DebugWithChain(intent.DestinationChain, "Using token %s address %s",
-		tokenType, tokenAddress.Hex(),
-	)
-
-	// First, approve the token transfer
-	// We need to approve the Intent contract to spend our tokens
+// approveToken approves the Intent contract at intentAddress to spend the token of the intent if the current allowance is insufficient
+func (s *Fulfiller) approveToken(
+	ctx context.Context,
+	chainClient *chainclient.Client,
+	intent models.Intent,
+	intentAddress common.Address,
+	tokenAddress common.Address,
+	amount *big.Int,
+	txOpts *bind.TransactOpts,
+	result *models.FulfillmentResult,
+) error {
 	s.logger.DebugWithChain(intent.DestinationChain, "Checking token allowance for intent %s (token: %s, spender: %s)",
 		intent.ID, tokenAddress.Hex(), intentAddress.Hex(),
 	)
@@ -100,105 +220,274 @@ func (s *Fulfiller) fulfillIntent(ctx context.Context, intent models.Intent) err
 	}
 
 	// Create ERC20 contract binding
+	backend := chainClient.ContractBackend()
 	erc20Contract := bind.NewBoundContract(
 		tokenAddress,
 		erc20ABI,
-		chainClient.Client,
-		chainClient.Client,
-		chainClient.Client,
+		backend,
+		backend,
+		backend,
 	)
 
-	// Apply current gas price to transactor
-	s.mu.Lock()
-	txOpts := *chainClient.Auth
-	s.mu.Unlock()
+	// Check current allowance first
+	if s.hasAllowance(ctx, erc20Contract, intent, txOpts.From, intentAddress, amount) {
+		return nil
+	}
 
-	// Check if approval is needed
-	needsApproval := true
+	// Wait for an approval slot of the chain, another worker may have approved the token in the meantime
+	releaseSlot, err := s.acquireApprovalSlot(ctx, intent.DestinationChain)
+	if err != nil {
+		return fmt.Errorf("failed to wait for an approval slot: %w", err)
+	}
+	defer releaseSlot()
+	if s.approvalSlots[intent.DestinationChain] != nil &&
+		s.hasAllowance(ctx, erc20Contract, intent, txOpts.From, intentAddress, amount) {
+		return nil
+	}
 
-	// Check current allowance first
-	callOpts := &bind.CallOpts{Context: ctx}
+	// Approve the Intent contract to spend our tokens
+	s.logger.InfoWithChain(intent.DestinationChain, "Initiating token approval for intent %s (token: %s, spender: %s)",
+		intent.ID, tokenAddress.Hex(), intentAddress.Hex())
 
-	// Use method call to get allowance
+	// Use max uint256 value for unlimited approval to avoid future approval transactions
+	maxUint256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+	// Send the approve transaction with unlimited amount, at the approval gas price of the chain
+	approveOpts := *txOpts
+	approveOpts.GasPrice = chainClient.ApprovalGasPrice(txOpts.GasPrice)
+	restoreNonce := s.applyReleasedNonce(chainClient, &approveOpts)
+	approveTx, err := erc20Contract.Transact(&approveOpts, "approve", intentAddress, maxUint256)
+	if err != nil {
+		restoreNonce()
+		s.logger.ErrorWithChain(intent.DestinationChain, "Failed to create approval transaction for intent %s: %v", intent.ID, err)
+		return fmt.Errorf("failed to approve token transfer: %w", err)
+	}
+
+	s.logger.InfoWithChain(intent.DestinationChain, "Approval transaction sent for intent %s: %s", intent.ID, approveTx.Hash().Hex())
+
+	// Wait for the approve transaction to be mined
+	approveReceipt, err := s.waitMined(ctx, chainClient, approveTx)
+	if err != nil {
+		s.logger.ErrorWithChain(intent.DestinationChain, "Failed to mine approval transaction for intent %s: %v", intent.ID, err)
+		return fmt.Errorf("failed to wait for approve transaction: %w", err)
+	}
+
+	if approveReceipt.Status == 0 {
+		s.logger.ErrorWithChain(intent.DestinationChain, "Approval transaction failed for intent %s: %s", intent.ID, approveTx.Hash().Hex())
+		return fmt.Errorf("approve transaction failed")
+	}
+
+	s.logger.InfoWithChain(intent.DestinationChain, "Approval successful for intent %s: %s (gas used: %d)",
+		intent.ID, approveTx.Hash().Hex(), approveReceipt.GasUsed)
+
+	result.ApprovalNeeded = true
+	result.ApprovalGasUsed = approveReceipt.GasUsed
+	result.ApprovalGasPrice = receiptGasPrice(approveReceipt, approveTx)
+	return nil
+}
+
+// hasAllowance returns true if the allowance of the Intent contract at spender covers the amount,
+// false if it doesn't or can't be read
+func (s *Fulfiller) hasAllowance(
+	ctx context.Context,
+	erc20Contract *bind.BoundContract,
+	intent models.Intent,
+	owner common.Address,
+	spender common.Address,
+	amount *big.Int,
+) bool {
 	var out []interface{}
-	err = erc20Contract.Call(callOpts, &out, "allowance", txOpts.From, intentAddress)
+	err := erc20Contract.Call(&bind.CallOpts{Context: ctx}, &out, "allowance", owner, spender)
 	if err != nil {
-		s.logger.DebugWithChain(
-			intent.DestinationChain,
-			"Failed to check allowance for intent %s: %v",
-			intent.ID,
-			err,
-		)
-		// Continue with approval (default behavior)
-	} else if len(out) > 0 {
-		if allowance, ok := out[0].(*big.Int); ok && allowance != nil {
-			s.logger.DebugWithChain(intent.DestinationChain, "Current allowance for intent %s: %s (needed: %s)",
-				intent.ID, allowance.String(), amount.String())
-			if allowance.Cmp(amount) >= 0 {
-				s.logger.DebugWithChain(intent.DestinationChain, "Existing allowance is sufficient for intent %s, skipping approval",
-					intent.ID)
-				needsApproval = false
-			}
-		}
+		s.logger.DebugWithChain(intent.DestinationChain, "Failed to check allowance for intent %s: %v", intent.ID, err)
+		return false
+	}
+	if len(out) == 0 {
+		return false
+	}
+	allowance, ok := out[0].(*big.Int)
+	if !ok || allowance == nil {
+		return false
 	}
 
-	// Proceed with approval if needed
-	if needsApproval {
-		s.logger.InfoWithChain(intent.DestinationChain, "Initiating token approval for intent %s (token: %s, spender: %s)",
-			intent.ID, tokenAddress.Hex(), intentAddress.Hex())
+	s.logger.DebugWithChain(intent.DestinationChain, "Current allowance for intent %s: %s (needed: %s)",
+		intent.ID, allowance.String(), amount.String())
+	if allowance.Cmp(amount) < 0 {
+		return false
+	}
+	s.logger.DebugWithChain(intent.DestinationChain, "Existing allowance is sufficient for intent %s, skipping approval", intent.ID)
+	return true
+}
 
-		// Use max uint256 value for unlimited approval to avoid future approval transactions
-		maxUint256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+// acquireApprovalSlot waits for a slot of the approval transactions of the chain, limited to MaxConcurrentApprovals,
+// it returns the function releasing the slot
+func (s *Fulfiller) acquireApprovalSlot(ctx context.Context, chainID int) (func(), error) {
+	slots := s.approvalSlots[chainID]
+	if slots == nil {
+		return func() {}, nil
+	}
 
-		// Send the approve transaction with unlimited amount
-		approveTx, err := erc20Contract.Transact(&txOpts, "approve", intentAddress, maxUint256)
-		if err != nil {
-			s.logger.ErrorWithChain(intent.DestinationChain, "Failed to create approval transaction for intent %s: %v", intent.ID, err)
-			return fmt.Errorf("failed to approve token transfer: %v", err)
+	select {
+	case slots <- struct{}{}:
+	default:
+		s.logger.DebugWithChain(chainID, "Waiting for one of the %d approval slots", cap(slots))
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
 		}
+	}
+	return func() { <-slots }, nil
+}
 
-		s.logger.InfoWithChain(intent.DestinationChain, "Approval transaction sent for intent %s: %s", intent.ID, approveTx.Hash().Hex())
+// replacementBumpPercent is the minimum gas price increase over a pending transaction for nodes to accept its replacement
+const replacementBumpPercent = 10
 
-		// Wait for the approve transaction to be mined
-		approveReceipt, err := bind.WaitMined(ctx, chainClient.Client, approveTx)
-		if err != nil {
-			s.logger.ErrorWithChain(intent.DestinationChain, "Failed to mine approval transaction for intent %s: %v", intent.ID, err)
-			return fmt.Errorf("failed to wait for approve transaction: %v", err)
+// applyReleasedNonce sets the nonce of a transaction that wasn't mined in time on the transactor to replace it,
+// the gas price is raised to replacementBumpPercent above the stuck transaction so that nodes accept the replacement.
+// The nonce is left to the pending state of the node otherwise, or if the raised gas price exceeds the max gas price.
+// It returns a function putting the nonce back in the released set, to call if the transaction can't be sent
+func (s *Fulfiller) applyReleasedNonce(chainClient *chainclient.Client, txOpts *bind.TransactOpts) func() {
+	txOpts.Nonce = nil
+	nonce, stuckGasPrice, ok := chainClient.TakeReleasedNonce()
+	if !ok {
+		return func() {}
+	}
+	restore := func() {
+		chainClient.ReleaseNonce(nonce, stuckGasPrice)
+	}
+
+	if stuckGasPrice != nil {
+		replacement := bumpGasPrice(stuckGasPrice, replacementBumpPercent, 1)
+		// round up so that the increase is not truncated below the required percentage
+		replacement.Add(replacement, big.NewInt(1))
+		if txOpts.GasPrice == nil || txOpts.GasPrice.Cmp(replacement) < 0 {
+			if !chainClient.IsWithinMax(replacement) {
+				s.logger.ErrorWithChain(chainClient.ChainID,
+					"Not reusing nonce %d: replacing the stuck transaction requires gas price %s above the max gas price",
+					nonce, replacement.String())
+				restore()
+				return func() {}
+			}
+			txOpts.GasPrice = replacement
 		}
+	}
 
-		if approveReceipt.Status == 0 {
-			s.logger.ErrorWithChain(intent.DestinationChain, "Approval transaction failed for intent %s: %s", intent.ID, approveTx.Hash().Hex())
-			return fmt.Errorf("approve transaction failed")
+	s.logger.InfoWithChain(chainClient.ChainID, "Reusing nonce %d to replace a stuck transaction (gas price: %s)",
+		nonce, txOpts.GasPrice)
+	txOpts.Nonce = new(big.Int).SetUint64(nonce)
+	return restore
+}
+
+// waitMined waits for the transaction to be mined with the confirmations configured for the chain,
+// if the deadline is reached before the transaction is mined, the nonce is released for replacement
+func (s *Fulfiller) waitMined(ctx context.Context, chainClient *chainclient.Client, tx *types.Transaction) (*types.Receipt, error) {
+	receipt, err := s.txSender(chainClient).WaitMined(ctx, tx)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			chainClient.ReleaseNonce(tx.Nonce(), tx.GasFeeCap())
+			s.logger.ErrorWithChain(chainClient.ChainID, "Transaction %s not mined before deadline, releasing nonce %d for replacement",
+				tx.Hash().Hex(), tx.Nonce())
+			return nil, fmt.Errorf("%w: transaction %s not mined", errFulfillTimeout, tx.Hash().Hex())
 		}
+		return nil, err
+	}
+
+	return s.waitConfirmations(ctx, chainClient, receipt)
+}
 
-		s.logger.InfoWithChain(intent.DestinationChain, "Approval successful for intent %s: %s (gas used: %d)",
-			intent.ID, approveTx.Hash().Hex(), approveReceipt.GasUsed)
+// waitConfirmations waits until the block of the receipt has the configured number of confirmations
+// and returns the receipt the transaction is included with after them, following the transaction
+// if it was re-included in a different block, or errTxReorged if it's no longer included
+func (s *Fulfiller) waitConfirmations(ctx context.Context, chainClient *chainclient.Client, receipt *types.Receipt) (*types.Receipt, error) {
+	if chainClient.Confirmations <= 1 || receipt.BlockNumber == nil {
+		return receipt, nil
 	}
 
-	// Now call the contract's fulfill function with current gas price
-	s.logger.NoticeWithChain(intent.DestinationChain, "Initiating fulfillment for intent %s (token: %s, amount: %s, receiver: %s)",
-		intent.ID, tokenAddress.Hex(), amount.String(), receiver.Hex())
+	for {
+		target := receipt.BlockNumber.Uint64() + chainClient.Confirmations - 1
+		s.logger.DebugWithChain(chainClient.ChainID, "Waiting for %d confirmations of transaction %s (target block: %d)",
+			chainClient.Confirmations, receipt.TxHash.Hex(), target)
 
-	tx, err := chainClient.IntentContract.Fulfill(&txOpts, intentID, tokenAddress, amount, receiver)
-	if err != nil {
-		s.logger.ErrorWithChain(intent.DestinationChain, "Failed to create fulfillment transaction for intent %s: %v", intent.ID, err)
-		return fmt.Errorf("failed to fulfill intent on %d: %v", intent.DestinationChain, err)
+		if err := s.waitForBlock(ctx, chainClient, target); err != nil {
+			return nil, fmt.Errorf("failed to wait for confirmations of transaction %s: %w", receipt.TxHash.Hex(), err)
+		}
+
+		// Ensure the transaction is still included in the same block
+		confirmed, err := chainClient.TransactionReceipt(ctx, receipt.TxHash)
+		if errors.Is(err, ethereum.NotFound) {
+			return nil, fmt.Errorf("%w: transaction %s removed from block %s",
+				errTxReorged, receipt.TxHash.Hex(), receipt.BlockHash.Hex())
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get receipt of transaction %s after confirmations: %w", receipt.TxHash.Hex(), err)
+		}
+		if confirmed.BlockHash == receipt.BlockHash {
+			return confirmed, nil
+		}
+
+		// The transaction was re-included in a different block, wait for the confirmations of that block
+		s.logger.InfoWithChain(chainClient.ChainID, "Transaction %s moved from block %s to block %s by a reorg",
+			receipt.TxHash.Hex(), receipt.BlockHash.Hex(), confirmed.BlockHash.Hex())
+		if confirmed.BlockNumber == nil {
+			return confirmed, nil
+		}
+		receipt = confirmed
 	}
+}
 
-	s.logger.InfoWithChain(intent.DestinationChain, "Fulfillment transaction created for intent %s: %s", intent.ID, tx.Hash().Hex())
+// waitForBlock waits until the head of the chain reaches the target block
+func (s *Fulfiller) waitForBlock(ctx context.Context, chainClient *chainclient.Client, target uint64) error {
+	ticker := time.NewTicker(confirmationPollInterval)
+	defer ticker.Stop()
 
-	// Wait for the transaction to be mined
-	receipt, err := bind.WaitMined(ctx, chainClient.Client, tx)
-	if err != nil {
-		s.logger.ErrorWithChain(intent.DestinationChain, "Failed to wait for transaction on intent %s: %v", intent.ID, err)
-		return fmt.Errorf("failed to wait for transaction on %d: %v", intent.DestinationChain, err)
+	for {
+		head, err := chainClient.GetLatestBlockNumber(ctx)
+		if err != nil {
+			s.logger.DebugWithChain(chainClient.ChainID, "Failed to get latest block while waiting for block %d: %v", target, err)
+		} else if head >= target {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
 	}
+}
 
-	if receipt.Status == 0 {
-		s.logger.ErrorWithChain(intent.DestinationChain, "Fulfillment transaction failed for intent %s: %s", intent.ID, tx.Hash().Hex())
-		return fmt.Errorf("transaction failed on %d", intent.DestinationChain)
+// bumpedGasPrice returns the gas price increased by GasBumpPercent per attempt for retries of gas errors
+// and stuck transactions, capped at the chain max gas price, or nil if no bump applies
+func (s *Fulfiller) bumpedGasPrice(chainClient *chainclient.Client, intent models.Intent, gasPrice *big.Int) *big.Int {
+	retryCount, errorType := parseRetryTag(intent.ID)
+	if retryCount == 0 || s.config.GasBumpPercent <= 0 || gasPrice == nil || gasPrice.Sign() <= 0 {
+		return nil
+	}
+	if errorType != "gas_error" && errorType != "timeout" {
+		return nil
 	}
 
-	s.logger.NoticeWithChain(intent.DestinationChain, "Fulfillment transaction successful for intent %s: %s", intent.ID, tx.Hash().Hex())
-	return nil
+	bumped := bumpGasPrice(gasPrice, s.config.GasBumpPercent, retryCount)
+	if maxGasPrice := chainClient.GetMaxGasPrice(); maxGasPrice != nil && bumped.Cmp(maxGasPrice) > 0 {
+		bumped = new(big.Int).Set(maxGasPrice)
+	}
+	s.logger.InfoWithChain(intent.DestinationChain, "Bumping gas price of intent %s retry %d from %s to %s (error: %s)",
+		intent.ID, retryCount, gasPrice.String(), bumped.String(), errorType)
+	return bumped
+}
+
+// bumpGasPrice returns the gas price increased by percent compounded for each attempt
+func bumpGasPrice(gasPrice *big.Int, percent float64, attempts int) *big.Int {
+	factor := math.Pow(1+percent/100, float64(attempts))
+	bumped, _ := new(big.Float).Mul(new(big.Float).SetInt(gasPrice), big.NewFloat(factor)).Int(nil)
+	return bumped
+}
+
+// receiptGasPrice returns the gas price paid by a mined transaction, the effective gas price from the receipt if available
+func receiptGasPrice(receipt *types.Receipt, tx *types.Transaction) *big.Int {
+	if receipt.EffectiveGasPrice != nil && receipt.EffectiveGasPrice.Sign() > 0 {
+		return receipt.EffectiveGasPrice
+	}
+	return tx.GasPrice()
 }
@@ -1,11 +1,19 @@
 package fulfiller
 
 import (
+	"context"
 	"github.com/speedrun-hq/speedrunner/pkg/fulfiller/mocks"
 	"math/big"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/speedrun-hq/speedrunner/pkg/chainclient"
+	"github.com/speedrun-hq/speedrunner/pkg/config"
+	"github.com/speedrun-hq/speedrunner/pkg/logger"
+	"github.com/speedrun-hq/speedrunner/pkg/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestMockTokenApproval verifies the token approval flow using our custom mocks
@@ -65,3 +73,100 @@ func TestMockTokenApproval(t *testing.T) {
 			"Transaction should be stored in mock contract")
 	})
 }
+
+// TestBumpGasPrice tests the gas price bump is compounded per attempt
+func TestBumpGasPrice(t *testing.T) {
+	gasPrice := big.NewInt(1000000000)
+	assert.Equal(t, "1125000000", bumpGasPrice(gasPrice, 12.5, 1).String())
+	assert.Equal(t, "1265625000", bumpGasPrice(gasPrice, 12.5, 2).String())
+	assert.Equal(t, "1000000000", bumpGasPrice(gasPrice, 12.5, 0).String())
+}
+
+// TestBumpedGasPrice tests the bump only applies to retries of gas errors and stuck transactions, up to the max gas price
+func TestBumpedGasPrice(t *testing.T) {
+	s := &Fulfiller{
+		config: &config.Config{GasBumpPercent: 12.5},
+		logger: &logger.EmptyLogger{},
+	}
+	chainClient := &chainclient.Client{MaxGasPrice: big.NewInt(1200000000)}
+	gasPrice := big.NewInt(1000000000)
+
+	// first attempt
+	assert.Nil(t, s.bumpedGasPrice(chainClient, models.Intent{ID: "0xabc"}, gasPrice))
+
+	// retry of an error not related to the gas price
+	assert.Nil(t, s.bumpedGasPrice(chainClient, models.Intent{ID: "0xabc_retry_1_error_network_error"}, gasPrice))
+
+	bumped := s.bumpedGasPrice(chainClient, models.Intent{ID: "0xabc_retry_1_error_gas_error"}, gasPrice)
+	assert.Equal(t, "1125000000", bumped.String())
+
+	// capped at the max gas price
+	bumped = s.bumpedGasPrice(chainClient, models.Intent{ID: "0xabc_retry_2_error_timeout"}, gasPrice)
+	assert.Equal(t, "1200000000", bumped.String())
+
+	// disabled
+	s.config.GasBumpPercent = 0
+	assert.Nil(t, s.bumpedGasPrice(chainClient, models.Intent{ID: "0xabc_retry_1_error_gas_error"}, gasPrice))
+}
+
+// TestApplyReleasedNonce verifies released nonces are reused with a gas price above the stuck transaction,
+// and put back when the replacement would exceed the max gas price or can't be sent
+func TestApplyReleasedNonce(t *testing.T) {
+	s := &Fulfiller{logger: &logger.EmptyLogger{}}
+	chainClient := &chainclient.Client{MaxGasPrice: big.NewInt(2000000000)}
+
+	// no released nonce, the nonce is left to the node
+	txOpts := &bind.TransactOpts{GasPrice: big.NewInt(1000000000), Nonce: big.NewInt(1)}
+	s.applyReleasedNonce(chainClient, txOpts)
+	assert.Nil(t, txOpts.Nonce)
+
+	// the gas price is raised above the stuck transaction
+	chainClient.ReleaseNonce(7, big.NewInt(1000000000))
+	restore := s.applyReleasedNonce(chainClient, txOpts)
+	assert.Equal(t, big.NewInt(7), txOpts.Nonce)
+	assert.Equal(t, "1100000001", txOpts.GasPrice.String())
+
+	// the nonce is put back when the transaction can't be sent
+	restore()
+	txOpts = &bind.TransactOpts{GasPrice: big.NewInt(1500000000)}
+	s.applyReleasedNonce(chainClient, txOpts)
+	assert.Equal(t, big.NewInt(7), txOpts.Nonce)
+	assert.Equal(t, big.NewInt(1500000000), txOpts.GasPrice)
+
+	// the replacement would exceed the max gas price
+	chainClient.ReleaseNonce(8, big.NewInt(1900000000))
+	txOpts = &bind.TransactOpts{GasPrice: big.NewInt(1000000000)}
+	s.applyReleasedNonce(chainClient, txOpts)
+	assert.Nil(t, txOpts.Nonce)
+	assert.Equal(t, big.NewInt(1000000000), txOpts.GasPrice)
+	nonce, _, ok := chainClient.TakeReleasedNonce()
+	require.True(t, ok)
+	assert.Equal(t, uint64(8), nonce)
+}
+
+// TestAcquireApprovalSlot verifies approvals of a chain wait for a free slot and chains without limit never wait
+func TestAcquireApprovalSlot(t *testing.T) {
+	s := &Fulfiller{
+		approvalSlots: map[int]chan struct{}{42161: make(chan struct{}, 1)},
+		logger:        logger.NewMemoryLogger(),
+	}
+
+	release, err := s.acquireApprovalSlot(context.Background(), 42161)
+	require.NoError(t, err)
+
+	// the slot is taken until released
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = s.acquireApprovalSlot(ctx, 42161)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// other chains are not limited
+	releaseOther, err := s.acquireApprovalSlot(ctx, 8453)
+	require.NoError(t, err)
+	releaseOther()
+
+	release()
+	release, err = s.acquireApprovalSlot(context.Background(), 42161)
+	require.NoError(t, err)
+	release()
+}
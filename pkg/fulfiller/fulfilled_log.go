@@ -0,0 +1,115 @@
+package fulfiller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// fulfilledLog persists recently fulfilled intents so that they are not re-attempted after a restart
+// when the API still reports them as pending, entries expire after the ttl
+// a nil log records nothing and contains nothing
+type fulfilledLog struct {
+	mu      sync.Mutex
+	path    string
+	ttl     time.Duration
+	entries map[string]time.Time
+}
+
+// loadFulfilledLog loads the fulfilled intent log from path, expired entries are dropped
+// it returns nil if path is empty
+func loadFulfilledLog(path string, ttl time.Duration) (*fulfilledLog, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	l := &fulfilledLog{
+		path:    path,
+		ttl:     ttl,
+		entries: make(map[string]time.Time),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fulfilled log %s: %v", path, err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &l.entries); err != nil {
+			return nil, fmt.Errorf("failed to parse fulfilled log %s: %v", path, err)
+		}
+	}
+	l.prune(time.Now())
+	return l, nil
+}
+
+// Contains returns true if the intent was fulfilled within the ttl
+func (l *fulfilledLog) Contains(intentID string) bool {
+	if l == nil {
+		return false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fulfilledAt, ok := l.entries[intentID]
+	return ok && time.Since(fulfilledAt) <= l.ttl
+}
+
+// Record adds the intent to the log and writes it to disk
+func (l *fulfilledLog) Record(intentID string) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	l.entries[intentID] = now
+	l.prune(now)
+	return l.save()
+}
+
+// prune drops the expired entries, caller must hold the lock if the log is shared
+func (l *fulfilledLog) prune(now time.Time) {
+	for id, fulfilledAt := range l.entries {
+		if now.Sub(fulfilledAt) > l.ttl {
+			delete(l.entries, id)
+		}
+	}
+}
+
+// save writes the log to disk, caller must hold the lock
+func (l *fulfilledLog) save() error {
+	data, err := json.Marshal(l.entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode fulfilled log: %v", err)
+	}
+	if err := writeFileAtomic(l.path, data); err != nil {
+		return fmt.Errorf("failed to write fulfilled log: %v", err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file renamed over path so that a crash can't leave it truncated
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,52 @@
+package fulfiller
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFulfilledLogPersistence tests fulfilled intents are kept across a reload until they expire
+func TestFulfilledLogPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fulfilled.json")
+
+	l, err := loadFulfilledLog(path, time.Hour)
+	require.NoError(t, err)
+	assert.False(t, l.Contains("intent1"))
+
+	require.NoError(t, l.Record("intent1"))
+	assert.True(t, l.Contains("intent1"))
+
+	// reload as after a restart
+	l, err = loadFulfilledLog(path, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, l.Contains("intent1"))
+	assert.False(t, l.Contains("intent2"))
+
+	// expired entries are dropped on load
+	l, err = loadFulfilledLog(path, time.Nanosecond)
+	require.NoError(t, err)
+	assert.False(t, l.Contains("intent1"))
+}
+
+// TestFulfilledLogDisabled tests a log without path records nothing
+func TestFulfilledLogDisabled(t *testing.T) {
+	l, err := loadFulfilledLog("", time.Hour)
+	require.NoError(t, err)
+	assert.Nil(t, l)
+	assert.NoError(t, l.Record("intent1"))
+	assert.False(t, l.Contains("intent1"))
+}
+
+// TestFulfilledLogCorrupted tests a corrupted log file is reported
+func TestFulfilledLogCorrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fulfilled.json")
+	require.NoError(t, os.WriteFile(path, []byte("{invalid"), 0o600))
+
+	_, err := loadFulfilledLog(path, time.Hour)
+	assert.Error(t, err)
+}
@@ -2,20 +2,31 @@ package fulfiller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand"
+	"slices"
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/speedrun-hq/speedrunner/pkg/chainclient"
+	"github.com/speedrun-hq/speedrunner/pkg/chains"
 	"github.com/speedrun-hq/speedrunner/pkg/circuitbreaker"
+	"github.com/speedrun-hq/speedrunner/pkg/clock"
 	"github.com/speedrun-hq/speedrunner/pkg/config"
+	"github.com/speedrun-hq/speedrunner/pkg/contracts"
 	"github.com/speedrun-hq/speedrunner/pkg/health"
 	"github.com/speedrun-hq/speedrunner/pkg/logger"
 	"github.com/speedrun-hq/speedrunner/pkg/metrics"
 	"github.com/speedrun-hq/speedrunner/pkg/models"
+	"github.com/speedrun-hq/speedrunner/pkg/signer"
 	"github.com/speedrun-hq/speedrunner/pkg/srunclient"
 )
 
+// healthShutdownTimeout is the maximum duration to wait for the health server to stop
+const healthShutdownTimeout = 5 * time.Second
+
 // Fulfiller handles the intent fulfillment process
 type Fulfiller struct {
 	config          *config.Config
@@ -23,27 +34,111 @@ type Fulfiller struct {
 	mu              sync.Mutex
 	workers         int
 	pendingJobs     chan models.Intent
-	retryJobs       chan models.RetryJob
+	pendingBatches  chan []models.Intent
+	retryJobs       *retryQueue
 	wg              sync.WaitGroup
+	backgroundWg    sync.WaitGroup // reorg checks and fulfillment reports started by the workers
 	chainClients    map[int]*chainclient.Client
 	circuitBreakers map[int]*circuitbreaker.CircuitBreaker
+	fulfilled       *fulfilledLog
+	exporter        *fulfillmentExporter
+	exposure        *exposureTracker
+	claimerID       string // claims intents for this instance, unique even when instances share the fulfiller address
+	clock           clock.Clock
 	logger          logger.Logger
+
+	// balanceReaders and txSenders replace the chain clients reading balances and sending fulfill transactions,
+	// the chain clients are used for the chains without an entry
+	balanceReaders map[int]BalanceReader
+	txSenders      map[int]TxSender
+
+	// approvalSlots limits the approval transactions in flight per chain to MaxConcurrentApprovals,
+	// chains without an entry are not limited
+	approvalSlots map[int]chan struct{}
+
+	// cooldownSlots limits the intents of a chain processed concurrently while its circuit breaker is in cooldown,
+	// chains without an entry are not limited
+	cooldownSlots map[int]chan struct{}
+
+	// chainProcessedAt is the time of the last poll processing the intents to each chain with a processing interval
+	chainProcessedAt map[int]time.Time
 }
 
 // NewFulfiller creates a new fulfiller service
 func NewFulfiller(ctx context.Context, cfg *config.Config) (*Fulfiller, error) {
 	stdLogger := logger.NewStdLogger(cfg.LoggerConfig.Coloring, cfg.LoggerConfig.Level)
 
+	chainclient.SetPriceRequestCoalescing(cfg.PriceRequestCoalescing)
+	chainclient.SetPriceRequestRetries(cfg.PriceRequestRetries, cfg.PriceRequestBackoff)
+	chainclient.SetMaxConcurrentRPC(cfg.MaxConcurrentRPC)
+	chainclient.SetNonceSyncRetries(cfg.NonceSyncRetries, cfg.NonceSyncBackoff)
+
+	// Create the transaction signer shared by all chains
+	txSigner, err := signer.New(cfg.Signer, cfg.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create signer: %v", err)
+	}
+
+	// A keystore is expected to hold the key of the configured fulfiller
+	if cfg.Signer.Type == config.SignerTypeKeystore && txSigner.Address() != common.HexToAddress(cfg.FulfillerAddress) {
+		return nil, fmt.Errorf("keystore address %s does not match FULFILLER_ADDRESS %s",
+			txSigner.Address().Hex(), cfg.FulfillerAddress)
+	}
+	stdLogger.Notice("Using %s signer with address %s", cfg.Signer.Type, txSigner.Address().Hex())
+
+	// Register the custom chains before they are connected and their intents are processed
+	if err := registerCustomChains(cfg.CustomChains, stdLogger); err != nil {
+		return nil, err
+	}
+	if err := applyTokenDecimals(cfg.TokenDecimals, stdLogger); err != nil {
+		return nil, err
+	}
+
+	// Load the Intent contract ABI shared by all chains
+	intentABI, err := contracts.LoadIntentABI(cfg.IntentABIPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load intent ABI: %v", err)
+	}
+
+	// Load the recently fulfilled intents to avoid re-attempting them after a restart
+	fulfilled, err := loadFulfilledLog(cfg.FulfilledLogPath, cfg.FulfilledLogTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reload the retries saved on the last shutdown, they are queued regardless of the size limit to not lose them
+	retryJobs := newEvictingRetryQueue(cfg.RetryQueueSize, cfg.RetryEviction)
+	savedRetryJobs, err := loadRetryJobs(cfg.RetryQueuePath)
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range savedRetryJobs {
+		retryJobs.requeue(job)
+	}
+	if len(savedRetryJobs) > 0 {
+		stdLogger.Notice("Loaded %d retry jobs from %s", len(savedRetryJobs), cfg.RetryQueuePath)
+	}
+
+	// Open the export of fulfillments for accounting
+	exporter, err := openFulfillmentExporter(cfg.FulfillmentLogPath, cfg.FulfillmentLogFormat)
+	if err != nil {
+		return nil, err
+	}
+
 	// Connect to blockchain clients
-	chainClients := make(map[int]*chainclient.Client)
-	for _, chainConfig := range cfg.Chains {
+	chainIDs := make([]int, 0, len(cfg.Chains))
+	for chainID := range cfg.Chains {
+		chainIDs = append(chainIDs, chainID)
+	}
+	chainClients, err := connectChains(chainIDs, cfg.ChainConnectConcurrency, func(chainID int) (*chainclient.Client, error) {
+		chainConfig := cfg.Chains[chainID]
 		chainClient, err := chainclient.New(
 			ctx,
 			chainConfig.ChainID,
 			chainConfig.RPCURL,
 			chainConfig.IntentAddress,
 			chainConfig.MinFee,
-			cfg.PrivateKey,
+			txSigner,
 			stdLogger,
 		)
 		if err != nil {
@@ -56,45 +151,173 @@ func NewFulfiller(ctx context.Context, cfg *config.Config) (*Fulfiller, error) {
 			stdLogger.ErrorWithChain(chainConfig.ChainID, "Error reading per-chain max gas price: %v", err)
 			effectiveMaxGas = cfg.MaxGasPrice
 		}
-		chainClient.MaxGasPrice = effectiveMaxGas
+		chainClient.SetMaxGasPrice(effectiveMaxGas)
 
-		chainClients[chainConfig.ChainID] = chainClient
+		if err := chainClient.SetIntentABI(intentABI, cfg.FulfillMethod); err != nil {
+			chainClient.Close()
+			return nil, fmt.Errorf("failed to set intent ABI for chain %d: %v", chainConfig.ChainID, err)
+		}
+		if cfg.BatchFulfillSize > 1 && !chainClient.SupportsMulticall() {
+			stdLogger.NoticeWithChain(chainConfig.ChainID, "Intent ABI has no multicall(bytes[]) method, intents are fulfilled individually")
+		}
+
+		return chainClient, nil
+	})
+	if err != nil {
+		_ = exporter.Close()
+		return nil, err
 	}
 
 	// Initialize circuit breakers
 	circuitBreakers := make(map[int]*circuitbreaker.CircuitBreaker)
 	for chainID := range cfg.Chains {
 		circuitBreakers[chainID] = circuitbreaker.NewCircuitBreaker(
+			chainID,
 			cfg.CircuitBreaker.Enabled,
 			cfg.CircuitBreaker.Threshold,
 			cfg.CircuitBreaker.WindowDuration,
 			cfg.CircuitBreaker.ResetTimeout,
+			cfg.CircuitBreaker.Warmup,
+			cfg.CircuitBreaker.Cooldown,
 			stdLogger,
 		)
 	}
 
+	// Limit the approval transactions in flight on each chain
+	approvalSlots := make(map[int]chan struct{})
+	if cfg.MaxConcurrentApprovals > 0 {
+		for chainID := range cfg.Chains {
+			approvalSlots[chainID] = make(chan struct{}, cfg.MaxConcurrentApprovals)
+		}
+	}
+
+	// Limit the intents processed concurrently on each chain after its circuit breaker closes
+	cooldownSlots := make(map[int]chan struct{})
+	if cfg.CircuitBreaker.Enabled && cfg.CircuitBreaker.Cooldown > 0 {
+		for chainID := range cfg.Chains {
+			cooldownSlots[chainID] = make(chan struct{}, cfg.CircuitBreaker.CooldownConcurrency)
+		}
+	}
+
+	srunClient := srunclient.New(cfg.APIEndpoint, stdLogger)
+	if cfg.APIIntentsPath != "" {
+		srunClient.SetIntentsPath(cfg.APIIntentsPath)
+	}
+
+	claimerID := newClaimerID()
+	if cfg.IntentClaiming {
+		stdLogger.Notice("Claiming intents as %s", claimerID)
+	}
+
 	return &Fulfiller{
 		config:          cfg,
-		srunClient:      srunclient.New(cfg.APIEndpoint, stdLogger),
+		srunClient:      srunClient,
 		workers:         cfg.WorkerCount,
-		pendingJobs:     make(chan models.Intent, 100),   // Buffer for pending intents
-		retryJobs:       make(chan models.RetryJob, 100), // Buffer for retry jobs
+		pendingJobs:     make(chan models.Intent, cfg.PendingQueueSize),
+		pendingBatches:  make(chan []models.Intent, cfg.PendingQueueSize),
+		retryJobs:       retryJobs,
 		chainClients:    chainClients,
 		circuitBreakers: circuitBreakers,
+		clock:           clock.Real{},
+		fulfilled:       fulfilled,
+		exporter:        exporter,
+		exposure:        newExposureTracker(cfg.MaxExposureUSD),
+		claimerID:       claimerID,
+		approvalSlots:   approvalSlots,
+		cooldownSlots:   cooldownSlots,
 		logger:          stdLogger,
 	}, nil
 }
 
-// Start begins the fulfiller service
-func (s *Fulfiller) Start(ctx context.Context) {
+// now returns the current time of the fulfiller clock, the wall clock if none is set
+func (s *Fulfiller) now() time.Time {
+	if s.clock == nil {
+		return time.Now()
+	}
+	return s.clock.Now()
+}
+
+// registerCustomChains adds the chains defined through the CHAINS configuration to the chain registry
+func registerCustomChains(customChains []config.CustomChain, log logger.Logger) error {
+	for _, customChain := range customChains {
+		if err := chains.RegisterCustomChain(customChain); err != nil {
+			return fmt.Errorf("failed to register custom chain %d: %v", customChain.ChainID, err)
+		}
+		log.Notice("Registered custom chain %s (%d) with %d tokens", customChain.Name, customChain.ChainID, len(customChain.Tokens))
+	}
+	return nil
+}
+
+// applyTokenDecimals overrides the decimals of the chain registry tokens with the CHAIN_<ID>_<TOKEN>_DECIMALS values
+func applyTokenDecimals(tokenDecimals map[int]map[string]int, log logger.Logger) error {
+	for chainID, tokens := range tokenDecimals {
+		for tokenType, decimals := range tokens {
+			if err := chains.SetTokenDecimals(chainID, chains.TokenType(tokenType), decimals); err != nil {
+				return fmt.Errorf("failed to override %s decimals of chain %d: %v", tokenType, chainID, err)
+			}
+			log.Notice("Using %d decimals for %s on chain %d", decimals, tokenType, chainID)
+		}
+	}
+	return nil
+}
+
+// connectChains creates the clients of the chains, connecting to at most concurrency chains at a time
+// if any chain fails, the clients created are closed and the errors are returned in chain ID order
+func connectChains(
+	chainIDs []int,
+	concurrency int,
+	connect func(chainID int) (*chainclient.Client, error),
+) (map[int]*chainclient.Client, error) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	chainIDs = slices.Sorted(slices.Values(chainIDs))
+
+	clients := make([]*chainclient.Client, len(chainIDs))
+	errs := make([]error, len(chainIDs))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, chainID := range chainIDs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			clients[i], errs[i] = connect(chainID)
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		for _, chainClient := range clients {
+			if chainClient != nil {
+				chainClient.Close()
+			}
+		}
+		return nil, err
+	}
+
+	chainClients := make(map[int]*chainclient.Client, len(chainIDs))
+	for i, chainID := range chainIDs {
+		chainClients[chainID] = clients[i]
+	}
+	return chainClients, nil
+}
+
+// Start begins the fulfiller service and blocks until the context is cancelled
+// It returns an error if the service can't be started
+func (s *Fulfiller) Start(ctx context.Context) error {
 	// Start health monitoring server
 	healthServer := health.NewServer(
+		s.config.MetricsHost,
 		s.config.MetricsPort,
 		s.chainClients,
 		s.circuitBreakers,
 		s.logger,
 	)
-	go healthServer.Start()
+	if err := healthServer.Start(); err != nil {
+		return fmt.Errorf("failed to start health server: %v", err)
+	}
 
 	// Start worker pool
 	s.logger.Notice("Starting worker pool with %d workers", s.workers)
@@ -102,40 +325,90 @@ func (s *Fulfiller) Start(ctx context.Context) {
 		go s.worker(ctx, i)
 	}
 
-	// Start retry handler
-	go s.retryHandler(ctx)
+	// Start retry handler, it must be stopped before the job queues are closed
+	retryHandlerDone := make(chan struct{})
+	go func() {
+		defer close(retryHandlerDone)
+		s.retryHandler(ctx)
+	}()
 
 	// Start metrics updater
 	go s.startMetricsUpdater(ctx)
 
-	s.logger.Info("Starting Fulfiller Fulfiller with polling interval %v", s.config.PollingInterval)
-	ticker := time.NewTicker(s.config.PollingInterval)
-	defer ticker.Stop()
+	s.logger.Info("Starting Fulfiller Fulfiller with polling interval %v (jitter: %v)",
+		s.config.PollingInterval, s.config.PollingJitter)
+	timer := time.NewTimer(pollDelay(s.config.PollingInterval, s.config.PollingJitter))
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			s.logger.Notice("Context cancelled, shutting down service")
+			<-retryHandlerDone
+
+			// Save the queued retries before waiting for the workers, so that they are kept
+			// even if the shutdown is cut short by a fulfillment in flight
+			saved := s.flushRetryJobs(nil)
+
 			close(s.pendingJobs)
-			close(s.retryJobs)
+			close(s.pendingBatches)
 			s.wg.Wait() // Wait for all workers to finish
-			return
-		case <-ticker.C:
+			// Wait for the reorg checks and reports started by the workers, they stop with the context
+			s.backgroundWg.Wait()
+
+			// Save again with the retries queued by the workers and the reorg checks since
+			s.flushRetryJobs(saved)
+
+			// Flush the fulfillments written by the workers
+			if err := s.exporter.Close(); err != nil {
+				s.logger.Error("Error closing fulfillment log: %v", err)
+			}
+
+			// Stop serving health and metrics endpoints
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), healthShutdownTimeout)
+			if err := healthServer.Shutdown(shutdownCtx); err != nil {
+				s.logger.Error("Error shutting down health server: %v", err)
+			}
+			cancel()
+
+			// Release chain connections and background routines
+			for chainID, chainClient := range s.chainClients {
+				s.logger.DebugWithChain(chainID, "Closing chain client")
+				chainClient.Close()
+			}
+			return nil
+		case <-timer.C:
+			timer.Reset(pollDelay(s.config.PollingInterval, s.config.PollingJitter))
+
 			intents, err := s.srunClient.FetchPendingIntents()
 			if err != nil {
 				s.logger.Error("Error fetching intents: %v", err)
 				continue
 			}
+			metrics.LastSuccessfulFetch.SetToCurrentTime()
 			s.logger.Debug("Found %d pending intents", len(intents))
 
+			// Bound the work of a cycle, deferred intents are fetched again in the next poll
+			if s.config.MaxIntentsPerCycle > 0 && len(intents) > s.config.MaxIntentsPerCycle {
+				s.logger.Info("Limiting cycle to the %d most recent of %d pending intents",
+					s.config.MaxIntentsPerCycle, len(intents))
+				intents = limitIntents(intents, s.config.MaxIntentsPerCycle)
+			}
+
 			viableIntents := s.filterViableIntents(intents)
 			s.logger.Info("Found %d viable intents for processing", len(viableIntents))
 
 			// Update metric for pending intents
 			metrics.PendingIntents.Set(float64(len(viableIntents)))
 
-			// Queue viable intents for processing
-			for _, intent := range viableIntents {
+			// Queue viable intents for processing, blocks while the pending queue is full
+			// so that polling slows down to the pace of the workers
+			batches, singles := s.batchIntents(viableIntents)
+			for _, batch := range batches {
+				s.wg.Add(len(batch))
+				s.pendingBatches <- batch
+			}
+			for _, intent := range singles {
 				s.wg.Add(1)
 				s.pendingJobs <- intent
 			}
@@ -143,6 +416,15 @@ func (s *Fulfiller) Start(ctx context.Context) {
 	}
 }
 
+// pollDelay returns the delay before the next poll, a random jitter up to maxJitter is added to the interval
+// so that multiple instances don't poll the API at the same instants
+func pollDelay(interval, maxJitter time.Duration) time.Duration {
+	if maxJitter <= 0 {
+		return interval
+	}
+	return interval + time.Duration(rand.Int63n(int64(maxJitter)))
+}
+
 // retryHandler handles retrying failed jobs with exponential backoff
 func (s *Fulfiller) retryHandler(ctx context.Context) {
 	ticker := time.NewTicker(1 * time.Minute)
@@ -158,65 +440,64 @@ func (s *Fulfiller) retryHandler(ctx context.Context) {
 	}
 }
 
-// processRetryJobs processes jobs in the retry queue
+// processRetryJobs processes the due jobs of the retry queue in order of next attempt,
+// the jobs skipped for an open circuit breaker or a gas price too high are put back to be first on the next run
 func (s *Fulfiller) processRetryJobs(ctx context.Context) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	now := time.Now()
-	for {
-		select {
-		case job := <-s.retryJobs:
-			if now.Before(job.NextAttempt) {
-				// Put the job back in the queue
-				s.retryJobs <- job
-				// Update next retry metric
-				metrics.NextRetryIn.Set(time.Until(job.NextAttempt).Seconds())
-				return
-			}
+	now := s.now()
 
-			// Check if we've exceeded max retries
-			if job.RetryCount >= s.config.MaxRetries {
-				s.logger.Debug("Max retries exceeded for intent %s: %s", job.Intent.ID, job.ErrorType)
-				metrics.MaxRetriesReached.WithLabelValues(
-					fmt.Sprintf("%d", job.Intent.DestinationChain),
-					job.ErrorType,
-				).Inc()
-				continue
-			}
+	// Drop jobs past their deadline, due or not
+	for _, job := range s.retryJobs.removeExpired(now) {
+		s.dropExpiredRetry(ctx, job)
+		s.releaseExposure(job.Intent)
+	}
 
-			// Check circuit breaker
-			if breaker, exists := s.circuitBreakers[job.Intent.DestinationChain]; exists && breaker.IsOpen() {
-				// Put the job back in the queue
-				s.retryJobs <- job
-				metrics.RetriesSkipped.WithLabelValues(
-					fmt.Sprintf("%d", job.Intent.DestinationChain),
-					"circuit_breaker_open",
-				).Inc()
-				return
-			}
+	var skipped []models.RetryJob
+	for {
+		job, ok := s.retryJobs.popDue(now)
+		if !ok {
+			break
+		}
 
-			// Check gas price
-			if !s.isGasPriceAcceptable(ctx, job.Intent.DestinationChain) {
-				// Put the job back in the queue
-				s.retryJobs <- job
-				metrics.RetriesSkipped.WithLabelValues(
-					fmt.Sprintf("%d", job.Intent.DestinationChain),
-					"gas_price_too_high",
-				).Inc()
-				return
-			}
+		// Check circuit breaker
+		if breaker, exists := s.circuitBreakers[job.Intent.DestinationChain]; exists && breaker.IsOpen() {
+			skipped = append(skipped, job)
+			metrics.RetriesSkipped.WithLabelValues(
+				fmt.Sprintf("%d", job.Intent.DestinationChain),
+				"circuit_breaker_open",
+			).Inc()
+			continue
+		}
 
-			// Process the job
-			s.wg.Add(1)
-			s.pendingJobs <- job.Intent
-			metrics.RetriesExecuted.WithLabelValues(
+		// Check gas price
+		if !s.isGasPriceAcceptable(ctx, job.Intent.DestinationChain) {
+			skipped = append(skipped, job)
+			metrics.RetriesSkipped.WithLabelValues(
 				fmt.Sprintf("%d", job.Intent.DestinationChain),
-				job.ErrorType,
+				"gas_price_too_high",
 			).Inc()
-		default:
-			return
+			continue
 		}
+
+		// Process the job
+		s.wg.Add(1)
+		s.pendingJobs <- job.Intent
+		metrics.RetriesExecuted.WithLabelValues(
+			fmt.Sprintf("%d", job.Intent.DestinationChain),
+			job.ErrorType,
+		).Inc()
+	}
+
+	// Put the skipped jobs back in the queue, they keep their next attempt
+	for _, job := range skipped {
+		s.retryJobs.requeue(job)
+	}
+
+	// Update next retry metric
+	if nextAttempt, ok := s.retryJobs.nextAttempt(); ok {
+		metrics.NextRetryIn.Set(nextAttempt.Sub(now).Seconds())
 	}
 }
 
@@ -237,7 +518,7 @@ func (s *Fulfiller) isGasPriceAcceptable(ctx context.Context, chainID int) bool
 
 	// Check if gas price is within acceptable range after multiplier
 	if !chainClient.IsWithinMax(gasPrice) {
-		s.logger.ErrorWithChain(chainID, "Gas price too high: %s > %s (after multiplier)", gasPrice.String(), chainClient.MaxGasPrice.String())
+		s.logger.ErrorWithChain(chainID, "Gas price too high: %s > %s (after multiplier)", gasPrice.String(), chainClient.GetMaxGasPrice())
 		return false
 	}
 
@@ -0,0 +1,197 @@
+package fulfiller
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/speedrun-hq/speedrunner/pkg/chainclient"
+	"github.com/speedrun-hq/speedrunner/pkg/clock"
+	"github.com/speedrun-hq/speedrunner/pkg/config"
+	"github.com/speedrun-hq/speedrunner/pkg/logger"
+	"github.com/speedrun-hq/speedrunner/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testIntent returns a viable intent of 1 USDC from Base to Arbitrum with the modifications applied,
+// tests modify only the fields they check
+func testIntent(modify ...func(*models.Intent)) models.Intent {
+	intent := models.Intent{
+		ID:               "0x4b3f1a1e2c6f4b8a9d0e1f2a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e",
+		SourceChain:      8453,
+		DestinationChain: 42161,
+		Token:            "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913", // USDC on Base
+		Amount:           "1000000",
+		Recipient:        "0x1234567890abcdef1234567890abcdef12345678",
+		IntentFee:        "10000",
+		CreatedAt:        time.Now(),
+	}
+	for _, m := range modify {
+		m(&intent)
+	}
+	return intent
+}
+
+// newTestFulfiller returns a fulfiller with the config, no chain clients and a memory logger,
+// tests set the other fields they need
+func newTestFulfiller(cfg *config.Config) (*Fulfiller, *logger.MemoryLogger) {
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	log := logger.NewMemoryLogger()
+	return &Fulfiller{
+		config:       cfg,
+		chainClients: map[int]*chainclient.Client{},
+		logger:       log,
+	}, log
+}
+
+// TestPollDelay verifies the jitter is added within bounds
+func TestPollDelay(t *testing.T) {
+	assert.Equal(t, 5*time.Second, pollDelay(5*time.Second, 0))
+
+	for i := 0; i < 100; i++ {
+		delay := pollDelay(5*time.Second, 2*time.Second)
+		assert.GreaterOrEqual(t, delay, 5*time.Second)
+		assert.Less(t, delay, 7*time.Second)
+	}
+}
+
+// TestConnectChains verifies chains are connected concurrently within the bound
+func TestConnectChains(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	connect := func(chainID int) (*chainclient.Client, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			current := maxInFlight.Load()
+			if n <= current || maxInFlight.CompareAndSwap(current, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		return &chainclient.Client{ChainID: chainID}, nil
+	}
+
+	chainIDs := []int{1, 137, 42161, 43114, 56, 7000, 8453}
+	clients, err := connectChains(chainIDs, 3, connect)
+	require.NoError(t, err)
+	assert.Len(t, clients, len(chainIDs))
+	for _, chainID := range chainIDs {
+		assert.Equal(t, chainID, clients[chainID].ChainID)
+	}
+	assert.Greater(t, maxInFlight.Load(), int32(1))
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(3))
+}
+
+// TestConnectChainsErrors verifies the errors of all failed chains are reported in chain ID order
+func TestConnectChainsErrors(t *testing.T) {
+	connect := func(chainID int) (*chainclient.Client, error) {
+		if chainID == 8453 || chainID == 56 {
+			return nil, fmt.Errorf("chain %d unreachable", chainID)
+		}
+		return &chainclient.Client{ChainID: chainID}, nil
+	}
+
+	for i := 0; i < 10; i++ {
+		clients, err := connectChains([]int{8453, 1, 56, 137}, 4, connect)
+		require.Error(t, err)
+		assert.Nil(t, clients)
+		assert.Equal(t, "chain 56 unreachable\nchain 8453 unreachable", err.Error())
+	}
+}
+
+// fakeGasPriceService serves a fixed gas price
+type fakeGasPriceService struct{}
+
+func (f *fakeGasPriceService) GasPrice() (*hexutil.Big, error) {
+	return (*hexutil.Big)(big.NewInt(1_000_000_000)), nil
+}
+
+// TestProcessRetryJobsNotReady verifies jobs not ready don't block the ready jobs behind them
+func TestProcessRetryJobsNotReady(t *testing.T) {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", &fakeGasPriceService{}))
+	client := ethclient.NewClient(rpc.DialInProc(server))
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+
+	s := &Fulfiller{
+		config: &config.Config{MaxRetries: 10},
+		chainClients: map[int]*chainclient.Client{
+			42161: {ChainID: 42161, Client: client, GasMultiplier: 1},
+		},
+		retryJobs:   newRetryQueue(0),
+		pendingJobs: make(chan models.Intent, 10),
+		exposure:    newExposureTracker(0),
+		logger:      logger.NewMemoryLogger(),
+	}
+
+	s.retryJobs.push(models.RetryJob{Intent: models.Intent{ID: "later", DestinationChain: 42161}, NextAttempt: time.Now().Add(time.Minute)})
+	s.retryJobs.push(models.RetryJob{Intent: models.Intent{ID: "no_client", DestinationChain: 8453}, NextAttempt: time.Now()})
+	s.retryJobs.push(models.RetryJob{Intent: models.Intent{ID: "ready", DestinationChain: 42161}, NextAttempt: time.Now()})
+
+	s.processRetryJobs(context.Background())
+
+	require.Len(t, s.pendingJobs, 1)
+	assert.Equal(t, "ready", (<-s.pendingJobs).ID)
+	s.wg.Done()
+
+	// the skipped job is put back ahead of the job not due
+	require.Equal(t, 2, s.retryJobs.len())
+	assert.Equal(t, "no_client", popRetryJob(t, s.retryJobs).Intent.ID)
+	assert.Equal(t, "later", popRetryJob(t, s.retryJobs).Intent.ID)
+}
+
+// TestRetryPolicyAboveMaxRetries verifies a max retries of the error type above MAX_RETRIES is applied,
+// the retries are limited by the policy of their error type only
+func TestRetryPolicyAboveMaxRetries(t *testing.T) {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", &fakeGasPriceService{}))
+	client := ethclient.NewClient(rpc.DialInProc(server))
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+
+	s := &Fulfiller{
+		config: &config.Config{
+			MaxRetries: 2,
+			RetryPolicies: map[string]config.RetryPolicy{
+				"network_error": {MaxRetries: 4, Backoff: time.Second, MaxBackoff: time.Minute},
+			},
+		},
+		chainClients: map[int]*chainclient.Client{
+			42161: {ChainID: 42161, Client: client, GasMultiplier: 1},
+		},
+		retryJobs:   newRetryQueue(0),
+		pendingJobs: make(chan models.Intent, 10),
+		exposure:    newExposureTracker(0),
+		clock:       clock.Real{},
+		logger:      logger.NewMemoryLogger(),
+	}
+	intent := models.Intent{ID: "intent1_retry_3_error_network_error", DestinationChain: 42161}
+
+	require.True(t, s.scheduleRetry(context.Background(), intent, "network_error"))
+	job := popRetryJob(t, s.retryJobs)
+	assert.Equal(t, 4, job.RetryCount)
+
+	// the job is retried although its retry count is above MAX_RETRIES
+	job.NextAttempt = time.Now()
+	s.retryJobs.push(job)
+	s.processRetryJobs(context.Background())
+	require.Len(t, s.pendingJobs, 1)
+	assert.Equal(t, "intent1_retry_4_error_network_error", (<-s.pendingJobs).ID)
+	s.wg.Done()
+
+	assert.False(t, s.scheduleRetry(context.Background(), job.Intent, "network_error"))
+}
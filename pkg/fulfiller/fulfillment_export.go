@@ -0,0 +1,210 @@
+package fulfiller
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/speedrun-hq/speedrunner/pkg/chainclient"
+	"github.com/speedrun-hq/speedrunner/pkg/chains"
+	"github.com/speedrun-hq/speedrunner/pkg/config"
+	"github.com/speedrun-hq/speedrunner/pkg/models"
+)
+
+// exportFlushInterval is the time after which the buffered fulfillments are flushed to the file on the next write
+const exportFlushInterval = 10 * time.Second
+
+// fulfillmentRecord is a fulfillment written to the export
+type fulfillmentRecord struct {
+	IntentID         string    `json:"intent_id"`
+	SourceChain      int       `json:"source_chain"`
+	DestinationChain int       `json:"destination_chain"`
+	Token            string    `json:"token"`
+	TokenType        string    `json:"token_type"`
+	Amount           string    `json:"amount"`
+	IntentFee        string    `json:"intent_fee"`
+	FeeUSD           float64   `json:"fee_usd"`
+	TxHash           string    `json:"tx_hash"`
+	GasUsed          uint64    `json:"gas_used"`
+	GasCostWei       string    `json:"gas_cost_wei"`
+	GasCostUSD       float64   `json:"gas_cost_usd"`
+	Timestamp        time.Time `json:"timestamp"`
+}
+
+// fulfillmentRecordHeader is the header row of the CSV export, in the order of fulfillmentRecord.csvRow
+var fulfillmentRecordHeader = []string{
+	"intent_id", "source_chain", "destination_chain", "token", "token_type", "amount", "intent_fee",
+	"fee_usd", "tx_hash", "gas_used", "gas_cost_wei", "gas_cost_usd", "timestamp",
+}
+
+// csvRow returns the fields of the record as a CSV row
+func (r fulfillmentRecord) csvRow() []string {
+	return []string{
+		r.IntentID,
+		strconv.Itoa(r.SourceChain),
+		strconv.Itoa(r.DestinationChain),
+		r.Token,
+		r.TokenType,
+		r.Amount,
+		r.IntentFee,
+		strconv.FormatFloat(r.FeeUSD, 'f', -1, 64),
+		r.TxHash,
+		strconv.FormatUint(r.GasUsed, 10),
+		r.GasCostWei,
+		strconv.FormatFloat(r.GasCostUSD, 'f', -1, 64),
+		r.Timestamp.UTC().Format(time.RFC3339),
+	}
+}
+
+// newFulfillmentRecord builds the export record of a fulfillment, the USD values are zero if the gas token price is unknown
+func newFulfillmentRecord(intent models.Intent, result *models.FulfillmentResult, chainClient *chainclient.Client) fulfillmentRecord {
+	costWei := new(big.Int)
+	if result.GasPrice != nil {
+		costWei.Add(costWei, new(big.Int).Mul(new(big.Int).SetUint64(result.GasUsed), result.GasPrice))
+	}
+	if result.ApprovalNeeded && result.ApprovalGasPrice != nil {
+		costWei.Add(costWei, new(big.Int).Mul(new(big.Int).SetUint64(result.ApprovalGasUsed), result.ApprovalGasPrice))
+	}
+
+	record := fulfillmentRecord{
+		IntentID:         baseIntentID(intent.ID),
+		SourceChain:      intent.SourceChain,
+		DestinationChain: intent.DestinationChain,
+		Token:            intent.Token,
+		TokenType:        string(chains.GetTokenType(intent.Token)),
+		Amount:           intent.Amount,
+		IntentFee:        intent.IntentFee,
+		TxHash:           result.TxHash,
+		GasUsed:          result.GasUsed,
+		GasCostWei:       costWei.String(),
+		Timestamp:        time.Now(),
+	}
+
+	if tokenPriceUSD := chainClient.GetStoredTokenPriceUSD(); tokenPriceUSD > 0 {
+		if feeUSD, err := intentFeeUSD(intent, tokenPriceUSD); err == nil {
+			record.FeeUSD = feeUSD
+		}
+		record.GasCostUSD = fulfillmentCostUSD(result, tokenPriceUSD) + chainClient.GetL1FeeUSD()
+	}
+	return record
+}
+
+// exportFulfillment appends the fulfillment to the export for accounting
+func (s *Fulfiller) exportFulfillment(intent models.Intent, result *models.FulfillmentResult, chainClient *chainclient.Client) {
+	if err := s.exporter.Write(newFulfillmentRecord(intent, result, chainClient)); err != nil {
+		s.logger.ErrorWithChain(intent.DestinationChain, "Failed to export fulfillment of intent %s: %v", intent.ID, err)
+	}
+}
+
+// fulfillmentExporter appends every fulfillment to a file for accounting
+// writes are buffered, flushed on a write once exportFlushInterval elapsed since the last flush, and on Close
+// a nil exporter writes nothing
+type fulfillmentExporter struct {
+	mu        sync.Mutex
+	file      *os.File
+	buf       *bufio.Writer
+	csv       *csv.Writer
+	json      *json.Encoder
+	lastFlush time.Time
+}
+
+// openFulfillmentExporter opens the export file at path for appending, it returns nil if path is empty
+func openFulfillmentExporter(path, format string) (*fulfillmentExporter, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open fulfillment log %s: %v", path, err)
+	}
+
+	e := &fulfillmentExporter{
+		file:      file,
+		buf:       bufio.NewWriter(file),
+		lastFlush: time.Now(),
+	}
+
+	switch format {
+	case config.FulfillmentLogFormatCSV:
+		e.csv = csv.NewWriter(e.buf)
+
+		// write the header when starting a new file
+		info, err := file.Stat()
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("failed to stat fulfillment log %s: %v", path, err)
+		}
+		if info.Size() == 0 {
+			if err := e.csv.Write(fulfillmentRecordHeader); err != nil {
+				_ = file.Close()
+				return nil, fmt.Errorf("failed to write fulfillment log header: %v", err)
+			}
+		}
+	default:
+		e.json = json.NewEncoder(e.buf)
+	}
+	return e, nil
+}
+
+// Write appends the record to the export
+func (e *fulfillmentExporter) Write(record fulfillmentRecord) error {
+	if e == nil {
+		return nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var err error
+	if e.csv != nil {
+		err = e.csv.Write(record.csvRow())
+	} else {
+		err = e.json.Encode(record)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write fulfillment record: %v", err)
+	}
+
+	if time.Since(e.lastFlush) >= exportFlushInterval {
+		return e.flush()
+	}
+	return nil
+}
+
+// Close flushes the buffered records and closes the file
+func (e *fulfillmentExporter) Close() error {
+	if e == nil {
+		return nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	flushErr := e.flush()
+	if err := e.file.Close(); err != nil {
+		return fmt.Errorf("failed to close fulfillment log: %v", err)
+	}
+	return flushErr
+}
+
+// flush writes the buffered records to the file, caller must hold the lock
+func (e *fulfillmentExporter) flush() error {
+	e.lastFlush = time.Now()
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return fmt.Errorf("failed to flush fulfillment log: %v", err)
+		}
+	}
+	if err := e.buf.Flush(); err != nil {
+		return fmt.Errorf("failed to flush fulfillment log: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package fulfiller
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/speedrun-hq/speedrunner/pkg/chainclient"
+	"github.com/speedrun-hq/speedrunner/pkg/config"
+	"github.com/speedrun-hq/speedrunner/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFulfillmentRecord(t *testing.T) fulfillmentRecord {
+	intent := models.Intent{
+		ID:               "0xabc_retry_1_error_gas_error",
+		SourceChain:      8453,
+		DestinationChain: 42161,
+		Token:            "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913", // USDC on Base
+		Amount:           "1000000",
+		IntentFee:        "50000",
+	}
+	result := &models.FulfillmentResult{
+		TxHash:   "0xdef",
+		GasUsed:  100000,
+		GasPrice: big.NewInt(1e9),
+	}
+	record := newFulfillmentRecord(intent, result, &chainclient.Client{TokenPriceUSD: 2000})
+
+	assert.Equal(t, "0xabc", record.IntentID)
+	assert.Equal(t, "USDC", record.TokenType)
+	assert.Equal(t, "100000000000000", record.GasCostWei)
+	assert.InDelta(t, 0.05, record.FeeUSD, 1e-9)
+	assert.InDelta(t, 0.2, record.GasCostUSD, 1e-9)
+	return record
+}
+
+// TestFulfillmentExportJSON tests fulfillments are appended as JSON lines across reopens and flushed on close
+func TestFulfillmentExportJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fulfillments.jsonl")
+	record := testFulfillmentRecord(t)
+
+	for i := 0; i < 2; i++ {
+		e, err := openFulfillmentExporter(path, config.FulfillmentLogFormatJSON)
+		require.NoError(t, err)
+		require.NoError(t, e.Write(record))
+
+		// buffered until flushed
+		if i == 0 {
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Empty(t, data)
+		}
+		require.NoError(t, e.Close())
+	}
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var lines int
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var decoded fulfillmentRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &decoded))
+		assert.Equal(t, record.IntentID, decoded.IntentID)
+		assert.Equal(t, record.TxHash, decoded.TxHash)
+		lines++
+	}
+	assert.Equal(t, 2, lines)
+}
+
+// TestFulfillmentExportCSV tests the header is written once and each fulfillment is a row
+func TestFulfillmentExportCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fulfillments.csv")
+	record := testFulfillmentRecord(t)
+
+	for i := 0; i < 2; i++ {
+		e, err := openFulfillmentExporter(path, config.FulfillmentLogFormatCSV)
+		require.NoError(t, err)
+		require.NoError(t, e.Write(record))
+		require.NoError(t, e.Close())
+	}
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	rows, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, fulfillmentRecordHeader, rows[0])
+	assert.Equal(t, record.csvRow(), rows[1])
+	assert.Equal(t, record.csvRow(), rows[2])
+}
+
+// TestFulfillmentExportDisabled tests an exporter without path writes nothing
+func TestFulfillmentExportDisabled(t *testing.T) {
+	e, err := openFulfillmentExporter("", config.FulfillmentLogFormatJSON)
+	require.NoError(t, err)
+	assert.Nil(t, e)
+	assert.NoError(t, e.Write(fulfillmentRecord{}))
+	assert.NoError(t, e.Close())
+}
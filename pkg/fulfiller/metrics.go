@@ -3,10 +3,12 @@ package fulfiller
 import (
 	"context"
 	"math/big"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/speedrun-hq/speedrunner/pkg/chainclient"
 	"github.com/speedrun-hq/speedrunner/pkg/chains"
 	"github.com/speedrun-hq/speedrunner/pkg/contracts"
 	"github.com/speedrun-hq/speedrunner/pkg/metrics"
@@ -31,79 +33,109 @@ func (s *Fulfiller) startMetricsUpdater(ctx context.Context) {
 func (s *Fulfiller) updateMetrics(ctx context.Context) {
 	s.logger.Debug("Starting metrics update...")
 
-	// Update token balance metrics
-	for _, chainID := range chains.ChainList {
-		chainName := chains.GetChainName(chainID)
-		s.logger.DebugWithChain(chainID, "Processing token balances")
-
-		for _, tokenType := range chains.Tokenlist {
-
-			tokenAddress := chains.GetTokenEthAddress(chainID, tokenType)
-			if tokenAddress == (common.Address{}) {
-				s.logger.DebugWithChain(chainID, "No token address found for %s", tokenType)
-				continue
-			}
-
-			balance, err := s.getTokenBalance(chainID, tokenAddress)
-			if err != nil {
-				s.logger.DebugWithChain(chainID, "Error getting token balance for %s: %v", tokenType, err)
-				continue
-			}
-
-			// Get token decimals for logging
-			token, err := contracts.NewERC20(tokenAddress, s.chainClients[chainID].Client)
-			if err != nil {
-				s.logger.DebugWithChain(chainID, "Error creating token contract for %s: %v", tokenType, err)
-				continue
-			}
-			decimals, err := token.Decimals(&bind.CallOpts{})
-			if err != nil {
-				s.logger.DebugWithChain(chainID, "Error getting decimals for %s: %v", tokenType, err)
-				continue
-			}
-
-			// Convert balance to float64 for Prometheus
-			decimalsFloat := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
-			balance.Quo(balance, decimalsFloat)
-			balanceFloat64, _ := balance.Float64()
-
-			metrics.TokenBalance.WithLabelValues(
-				chainName,
-				string(tokenType),
-			).Set(balanceFloat64)
+	// Update the balance and gas price metrics of the chains concurrently, each doing several RPC calls
+	concurrency := s.config.MetricsConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for chainID, chainClient := range s.chainClients {
+		if reason := s.metricsSkipReason(chainID, chainClient); reason != "" {
+			s.logger.DebugWithChain(chainID, "Skipping metrics update: %s", reason)
+			continue
 		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			s.updateChainMetrics(ctx, chainID, chainClient)
+		}()
 	}
+	wg.Wait()
+
+	// Update retry queue size
+	queueSize := s.retryJobs.len()
+	s.logger.Debug("Setting retry queue size metric: %d", queueSize)
+	metrics.RetryQueueSize.Set(float64(queueSize))
+
+	s.logger.Debug("Metrics update completed")
+}
+
+// metricsSkipReason returns why the metrics of the chain are not updated, empty if they are
+func (s *Fulfiller) metricsSkipReason(chainID int, chainClient *chainclient.Client) string {
+	if breaker, exists := s.circuitBreakers[chainID]; exists && breaker.IsEnabled() && breaker.IsOpen() {
+		return "circuit breaker open"
+	}
+	if !chainClient.IsConnected() {
+		return "RPC disconnected"
+	}
+	return ""
+}
+
+// updateChainMetrics updates the token balance and gas price metrics of the chain
+func (s *Fulfiller) updateChainMetrics(ctx context.Context, chainID int, chainClient *chainclient.Client) {
+	chainName := chains.GetChainName(chainID)
+	s.logger.DebugWithChain(chainID, "Processing token balances")
 
-	// Update gas price metrics
-	for chainID, chainConfig := range s.chainClients {
-		chainName := chains.GetChainName(chainID)
-		if chainName == "" {
-			chainName = "Unknown"
+	for _, tokenType := range chains.Tokenlist {
+		tokenAddress := chains.GetTokenEthAddress(chainID, tokenType)
+		if tokenAddress == (common.Address{}) {
+			s.logger.DebugWithChain(chainID, "No token address found for %s", tokenType)
+			continue
+		}
+
+		balance, err := s.getTokenBalance(chainID, tokenAddress)
+		if err != nil {
+			s.logger.DebugWithChain(chainID, "Error getting token balance for %s: %v", tokenType, err)
+			continue
 		}
 
-		gasPrice, err := chainConfig.Client.SuggestGasPrice(ctx)
+		// Get token decimals for logging
+		token, err := contracts.NewERC20(tokenAddress, chainClient.ReadClient())
+		if err != nil {
+			s.logger.DebugWithChain(chainID, "Error creating token contract for %s: %v", tokenType, err)
+			continue
+		}
+		decimals, err := token.Decimals(&bind.CallOpts{Context: ctx})
 		if err != nil {
-			s.logger.DebugWithChain(chainID, "Error getting gas price: %v", err)
+			s.logger.DebugWithChain(chainID, "Error getting decimals for %s: %v", tokenType, err)
 			continue
 		}
 
-		// Convert gas price to gwei for Prometheus
-		gasPriceGwei := new(big.Float).Quo(
-			new(big.Float).SetInt(gasPrice),
-			new(big.Float).SetInt(big.NewInt(1e9)),
-		)
-		gasPriceFloat64, _ := gasPriceGwei.Float64()
+		// Convert balance to float64 for Prometheus
+		decimalsFloat := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+		balance.Quo(balance, decimalsFloat)
+		balanceFloat64, _ := balance.Float64()
 
-		s.logger.DebugWithChain(chainID, "Setting gas price metric: %f gwei", gasPriceFloat64)
-		metrics.GasPrice.WithLabelValues(
+		metrics.TokenBalance.WithLabelValues(
 			chainName,
-		).Set(gasPriceFloat64)
+			string(tokenType),
+		).Set(balanceFloat64)
 	}
 
-	// Update retry queue size
-	queueSize := len(s.retryJobs)
-	s.logger.Debug("Setting retry queue size metric: %d", queueSize)
-	metrics.RetryQueueSize.Set(float64(queueSize))
+	// Update gas price metric
+	if chainName == "" {
+		chainName = "Unknown"
+	}
 
-	s.logger.Debug("Metrics update completed")
+	gasPrice, err := chainClient.ReadClient().SuggestGasPrice(ctx)
+	if err != nil {
+		s.logger.DebugWithChain(chainID, "Error getting gas price: %v", err)
+		return
+	}
+
+	// Convert gas price to gwei for Prometheus
+	gasPriceGwei := new(big.Float).Quo(
+		new(big.Float).SetInt(gasPrice),
+		new(big.Float).SetInt(big.NewInt(1e9)),
+	)
+	gasPriceFloat64, _ := gasPriceGwei.Float64()
+
+	s.logger.DebugWithChain(chainID, "Setting gas price metric: %f gwei", gasPriceFloat64)
+	metrics.GasPrice.WithLabelValues(
+		chainName,
+	).Set(gasPriceFloat64)
 }
@@ -0,0 +1,28 @@
+package fulfiller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/speedrun-hq/speedrunner/pkg/chainclient"
+	"github.com/speedrun-hq/speedrunner/pkg/circuitbreaker"
+	"github.com/speedrun-hq/speedrunner/pkg/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMetricsSkipReason verifies the metrics of chains with an open circuit breaker are not updated
+func TestMetricsSkipReason(t *testing.T) {
+	log := logger.NewMemoryLogger()
+	open := circuitbreaker.NewCircuitBreaker(137, true, 1, time.Minute, time.Minute, 0, 0, log)
+	open.RecordFailure()
+	closed := circuitbreaker.NewCircuitBreaker(42161, true, 1, time.Minute, time.Minute, 0, 0, log)
+
+	s := &Fulfiller{
+		circuitBreakers: map[int]*circuitbreaker.CircuitBreaker{137: open, 42161: closed},
+		logger:          log,
+	}
+
+	assert.Equal(t, "circuit breaker open", s.metricsSkipReason(137, &chainclient.Client{ChainID: 137}))
+	assert.Empty(t, s.metricsSkipReason(42161, &chainclient.Client{ChainID: 42161}))
+	assert.Empty(t, s.metricsSkipReason(8453, &chainclient.Client{ChainID: 8453}))
+}
@@ -0,0 +1,70 @@
+package fulfiller
+
+import (
+	"math/big"
+	"strconv"
+
+	"github.com/speedrun-hq/speedrunner/pkg/chainclient"
+	"github.com/speedrun-hq/speedrunner/pkg/metrics"
+	"github.com/speedrun-hq/speedrunner/pkg/models"
+)
+
+// recordProfit computes the realized profit of a fulfillment as the intent fee minus the transaction costs in USD
+// and records it in metrics
+func (s *Fulfiller) recordProfit(intent models.Intent, result *models.FulfillmentResult, chainClient *chainclient.Client) {
+	tokenPriceUSD := chainClient.GetStoredTokenPriceUSD()
+	if tokenPriceUSD <= 0 {
+		s.logger.ErrorWithChain(intent.DestinationChain, "Cannot compute profit for intent %s: gas token price unknown", intent.ID)
+		return
+	}
+
+	feeUSD, err := intentFeeUSD(intent, tokenPriceUSD)
+	if err != nil {
+		s.logger.ErrorWithChain(intent.DestinationChain, "Cannot compute profit for intent %s: %v", intent.ID, err)
+		return
+	}
+
+	// the L1 data fee of rollups isn't part of the receipt gas, use the current estimate
+	costUSD := fulfillmentCostUSD(result, tokenPriceUSD) + chainClient.GetL1FeeUSD()
+	profitUSD := feeUSD - costUSD
+
+	chainLabel := strconv.Itoa(intent.DestinationChain)
+	metrics.RealizedProfitUSD.WithLabelValues(chainLabel).Observe(profitUSD)
+	metrics.FeesEarnedUSD.WithLabelValues(chainLabel).Add(feeUSD)
+	metrics.FulfillmentCostUSD.WithLabelValues(chainLabel).Add(costUSD)
+
+	s.logger.InfoWithChain(intent.DestinationChain, "Realized profit for intent %s: $%.4f (fee: $%.4f, cost: $%.4f)",
+		intent.ID, profitUSD, feeUSD, costUSD)
+}
+
+// checkGasUsed logs a warning and records an anomaly when the fulfillment used more gas than the withdraw gas estimate
+// of the chain times GasUsedAlertRatio, which may indicate a misconfigured token or a malicious contract
+func (s *Fulfiller) checkGasUsed(intent models.Intent, result *models.FulfillmentResult, chainClient *chainclient.Client) {
+	if s.config.GasUsedAlertRatio <= 0 || chainClient.WithdrawGas == 0 {
+		return
+	}
+
+	ratio := float64(result.GasUsed) / float64(chainClient.WithdrawGas)
+	if ratio <= s.config.GasUsedAlertRatio {
+		return
+	}
+
+	metrics.GasUsedAnomalies.WithLabelValues(strconv.Itoa(intent.DestinationChain)).Inc()
+	s.logger.ErrorWithChain(intent.DestinationChain, "Warning: fulfillment of intent %s used %d gas, %.1fx the estimate of %d "+
+		"(token: %s, tx: %s), check the token and contract or CHAIN_%d_WITHDRAW_GAS",
+		intent.ID, result.GasUsed, ratio, chainClient.WithdrawGas, intent.Token, result.TxHash, intent.DestinationChain)
+}
+
+// fulfillmentCostUSD returns the cost in USD of the transactions sent to fulfill an intent
+func fulfillmentCostUSD(result *models.FulfillmentResult, tokenPriceUSD float64) float64 {
+	costWei := new(big.Int)
+	if result.GasPrice != nil {
+		costWei.Add(costWei, new(big.Int).Mul(new(big.Int).SetUint64(result.GasUsed), result.GasPrice))
+	}
+	if result.ApprovalNeeded && result.ApprovalGasPrice != nil {
+		costWei.Add(costWei, new(big.Int).Mul(new(big.Int).SetUint64(result.ApprovalGasUsed), result.ApprovalGasPrice))
+	}
+
+	costFloat, _ := new(big.Float).SetInt(costWei).Float64()
+	return (costFloat / 1e18) * tokenPriceUSD
+}
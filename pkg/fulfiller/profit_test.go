@@ -0,0 +1,139 @@
+package fulfiller
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/speedrun-hq/speedrunner/pkg/chainclient"
+	"github.com/speedrun-hq/speedrunner/pkg/config"
+	"github.com/speedrun-hq/speedrunner/pkg/logger"
+	"github.com/speedrun-hq/speedrunner/pkg/metrics"
+	"github.com/speedrun-hq/speedrunner/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFulfillmentCostUSD tests the cost computation of fulfillment transactions
+func TestFulfillmentCostUSD(t *testing.T) {
+	t.Run("Fulfill only", func(t *testing.T) {
+		result := &models.FulfillmentResult{
+			GasUsed:  100000,
+			GasPrice: big.NewInt(10000000000), // 10 gwei
+		}
+		// 100000 * 10 gwei = 0.001 ETH at $3000
+		assert.InDelta(t, 3.0, fulfillmentCostUSD(result, 3000), 0.0001)
+	})
+
+	t.Run("Fulfill with approval", func(t *testing.T) {
+		result := &models.FulfillmentResult{
+			GasUsed:          100000,
+			GasPrice:         big.NewInt(10000000000),
+			ApprovalNeeded:   true,
+			ApprovalGasUsed:  50000,
+			ApprovalGasPrice: big.NewInt(10000000000),
+		}
+		assert.InDelta(t, 4.5, fulfillmentCostUSD(result, 3000), 0.0001)
+	})
+}
+
+// TestIntentFeeUSD tests the conversion of intent fees to USD
+func TestIntentFeeUSD(t *testing.T) {
+	intent := models.Intent{
+		SourceChain:      8453,
+		DestinationChain: 42161,
+		Token:            "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913", // USDC on Base
+		IntentFee:        "1500000",
+	}
+	feeUSD, err := intentFeeUSD(intent, 0)
+	require.NoError(t, err)
+	assert.InDelta(t, 1.5, feeUSD, 0.0001)
+
+	// BSC uses 18 decimals
+	intent.DestinationChain = 56
+	feeUSD, err = intentFeeUSD(intent, 0)
+	require.NoError(t, err)
+	assert.InDelta(t, 1.5, feeUSD, 0.0001)
+
+	intent.IntentFee = "invalid"
+	_, err = intentFeeUSD(intent, 0)
+	assert.Error(t, err)
+}
+
+// TestIntentFeeUSDNative tests the conversion of native token intent fees with the gas token price
+func TestIntentFeeUSDNative(t *testing.T) {
+	intent := models.Intent{
+		SourceChain:      56,
+		DestinationChain: 42161,
+		Token:            "0x0000000000000000000000000000000000000000",
+		IntentFee:        "1000000000000000", // 0.001 ETH
+	}
+	feeUSD, err := intentFeeUSD(intent, 3000)
+	require.NoError(t, err)
+	assert.InDelta(t, 3.0, feeUSD, 0.0001)
+
+	// price unknown
+	_, err = intentFeeUSD(intent, 0)
+	assert.Error(t, err)
+}
+
+// TestIntentMinFeeUSD tests the conversion of chain min fees to USD
+func TestIntentMinFeeUSD(t *testing.T) {
+	intent := models.Intent{
+		SourceChain:      8453,
+		DestinationChain: 42161,
+		Token:            "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913", // USDC on Base
+	}
+	minFeeUSD, err := intentMinFeeUSD(big.NewInt(100000), 0, intent, 0)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.1, minFeeUSD, 0.0001)
+
+	// the min fee in USD takes precedence
+	minFeeUSD, err = intentMinFeeUSD(big.NewInt(100000), 2.5, intent, 0)
+	require.NoError(t, err)
+	assert.InDelta(t, 2.5, minFeeUSD, 0.0001)
+
+	// BSC uses 18 decimals
+	intent.DestinationChain = 56
+	minFeeUSD, err = intentMinFeeUSD(big.NewInt(400000000000000000), 0, intent, 0)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.4, minFeeUSD, 0.0001)
+
+	// native token is converted with the gas token price
+	intent.DestinationChain = 42161
+	intent.Token = "0x0000000000000000000000000000000000000000"
+	minFeeUSD, err = intentMinFeeUSD(big.NewInt(1000000000000000), 0, intent, 3000)
+	require.NoError(t, err)
+	assert.InDelta(t, 3.0, minFeeUSD, 0.0001)
+
+	// price unknown
+	_, err = intentMinFeeUSD(big.NewInt(1000000000000000), 0, intent, 0)
+	assert.Error(t, err)
+
+	// no min fee
+	minFeeUSD, err = intentMinFeeUSD(nil, 0, intent, 0)
+	require.NoError(t, err)
+	assert.Zero(t, minFeeUSD)
+}
+
+// TestCheckGasUsed tests an anomaly is recorded only when the gas used exceeds the estimate by the alert ratio
+func TestCheckGasUsed(t *testing.T) {
+	log := logger.NewMemoryLogger()
+	s := &Fulfiller{config: &config.Config{GasUsedAlertRatio: 3}, logger: log}
+	chainClient := &chainclient.Client{WithdrawGas: 80000}
+	intent := models.Intent{ID: "0x01", DestinationChain: 8453}
+	anomalies := func() float64 { return testutil.ToFloat64(metrics.GasUsedAnomalies.WithLabelValues("8453")) }
+	initial := anomalies()
+
+	s.checkGasUsed(intent, &models.FulfillmentResult{GasUsed: 240000}, chainClient)
+	assert.Equal(t, initial, anomalies())
+
+	s.checkGasUsed(intent, &models.FulfillmentResult{GasUsed: 240001, TxHash: "0xabc"}, chainClient)
+	assert.Equal(t, initial+1, anomalies())
+	assert.True(t, log.Contains(logger.ErrorLevel, "intent 0x01 used 240001 gas, 3.0x the estimate of 80000"), "%v", log.Entries())
+
+	// disabled check
+	s.config.GasUsedAlertRatio = 0
+	s.checkGasUsed(intent, &models.FulfillmentResult{GasUsed: 1000000}, chainClient)
+	assert.Equal(t, initial+1, anomalies())
+}
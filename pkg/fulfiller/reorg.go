@@ -0,0 +1,92 @@
+package fulfiller
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/speedrun-hq/speedrunner/pkg/chainclient"
+	"github.com/speedrun-hq/speedrunner/pkg/metrics"
+	"github.com/speedrun-hq/speedrunner/pkg/models"
+)
+
+// reorgCheckTimeout is the maximum duration of the receipt request re-checking a fulfillment
+const reorgCheckTimeout = 10 * time.Second
+
+// monitorReorg waits until the fulfillment block is ReorgCheckDepth blocks deep and re-checks the transaction
+// is still included in it, the intent is re-queued if the transaction was dropped or failed after the reorg
+func (s *Fulfiller) monitorReorg(
+	ctx context.Context,
+	chainClient *chainclient.Client,
+	intent models.Intent,
+	result *models.FulfillmentResult,
+) {
+	if chainClient.ReorgCheckDepth == 0 || result.BlockHash == "" {
+		return
+	}
+
+	if err := s.waitForBlock(ctx, chainClient, result.BlockNumber+chainClient.ReorgCheckDepth); err != nil {
+		s.logger.DebugWithChain(intent.DestinationChain, "Stopped reorg check of intent %s: %v", intent.ID, err)
+		return
+	}
+
+	requeue, reorged, err := s.checkReorg(ctx, chainClient, result)
+	if err != nil {
+		s.logger.ErrorWithChain(intent.DestinationChain, "Failed to check reorg of intent %s: %v", intent.ID, err)
+		return
+	}
+	if !reorged {
+		return
+	}
+
+	metrics.ReorgsDetected.WithLabelValues(strconv.Itoa(intent.DestinationChain), strconv.FormatBool(requeue)).Inc()
+	if !requeue {
+		s.logger.InfoWithChain(intent.DestinationChain, "Fulfillment %s of intent %s was reorged and included in another block",
+			result.TxHash, intent.ID)
+		return
+	}
+
+	s.logger.ErrorWithChain(intent.DestinationChain, "Fulfillment %s of intent %s was reorged out of block %s, re-queuing intent",
+		result.TxHash, intent.ID, result.BlockHash)
+	if ctx.Err() != nil {
+		return
+	}
+
+	// the exposure of the intent was released with its fulfillment, commit it again for the retry
+	valueUSD, err := intentAmountUSD(intent, chainClient.GetStoredTokenPriceUSD())
+	if err != nil || !s.exposure.reserve(baseIntentID(intent.ID), valueUSD) {
+		s.logger.ErrorWithChain(intent.DestinationChain, "Not re-queuing reorged intent %s: exposure can't be reserved", intent.ID)
+		s.releaseIntent(ctx, intent)
+		return
+	}
+	if !s.scheduleRetry(ctx, intent, "reorg") {
+		s.releaseExposure(intent)
+	}
+}
+
+// checkReorg returns whether the fulfillment transaction left its block, and whether the intent must be fulfilled again
+// because the transaction is no longer included or failed in its new block
+func (s *Fulfiller) checkReorg(
+	ctx context.Context,
+	chainClient *chainclient.Client,
+	result *models.FulfillmentResult,
+) (requeue bool, reorged bool, err error) {
+	checkCtx, cancel := context.WithTimeout(ctx, reorgCheckTimeout)
+	defer cancel()
+
+	receipt, err := chainClient.TransactionReceipt(checkCtx, common.HexToHash(result.TxHash))
+	if errors.Is(err, ethereum.NotFound) {
+		return true, true, nil
+	}
+	if err != nil {
+		return false, false, err
+	}
+	if receipt.BlockHash == common.HexToHash(result.BlockHash) {
+		return false, false, nil
+	}
+	return receipt.Status == types.ReceiptStatusFailed, true, nil
+}
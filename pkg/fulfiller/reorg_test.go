@@ -0,0 +1,171 @@
+package fulfiller
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/speedrun-hq/speedrunner/pkg/chainclient"
+	"github.com/speedrun-hq/speedrunner/pkg/clock"
+	"github.com/speedrun-hq/speedrunner/pkg/config"
+	"github.com/speedrun-hq/speedrunner/pkg/logger"
+	"github.com/speedrun-hq/speedrunner/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReceiptService serves a fixed receipt, or none if nil
+type fakeReceiptService struct {
+	receipt *types.Receipt
+}
+
+func (f *fakeReceiptService) GetTransactionReceipt(_ common.Hash) (*types.Receipt, error) {
+	return f.receipt, nil
+}
+
+func (f *fakeReceiptService) BlockNumber() hexutil.Uint64 {
+	return 200
+}
+
+// newReorgTestClient returns a chain client backed by an in-process RPC serving the receipt
+func newReorgTestClient(t *testing.T, receipt *types.Receipt) *chainclient.Client {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", &fakeReceiptService{receipt: receipt}))
+	client := ethclient.NewClient(rpc.DialInProc(server))
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+	return &chainclient.Client{Client: client}
+}
+
+// TestCheckReorg tests the detection of fulfillments reorged out of their block
+func TestCheckReorg(t *testing.T) {
+	s := &Fulfiller{logger: &logger.EmptyLogger{}}
+	txHash := common.HexToHash("0x01")
+	blockHash := common.HexToHash("0xb1")
+	result := &models.FulfillmentResult{TxHash: txHash.Hex(), BlockHash: blockHash.Hex(), BlockNumber: 100}
+	newReceipt := func(blockHash common.Hash, status uint64) *types.Receipt {
+		return &types.Receipt{
+			Status:      status,
+			TxHash:      txHash,
+			BlockHash:   blockHash,
+			BlockNumber: common.Big1,
+			Logs:        []*types.Log{},
+		}
+	}
+
+	t.Run("Still included", func(t *testing.T) {
+		client := newReorgTestClient(t, newReceipt(blockHash, types.ReceiptStatusSuccessful))
+		requeue, reorged, err := s.checkReorg(context.Background(), client, result)
+		require.NoError(t, err)
+		assert.False(t, reorged)
+		assert.False(t, requeue)
+	})
+
+	t.Run("Included in another block", func(t *testing.T) {
+		client := newReorgTestClient(t, newReceipt(common.HexToHash("0xb2"), types.ReceiptStatusSuccessful))
+		requeue, reorged, err := s.checkReorg(context.Background(), client, result)
+		require.NoError(t, err)
+		assert.True(t, reorged)
+		assert.False(t, requeue)
+	})
+
+	t.Run("Failed in another block", func(t *testing.T) {
+		client := newReorgTestClient(t, newReceipt(common.HexToHash("0xb2"), types.ReceiptStatusFailed))
+		requeue, reorged, err := s.checkReorg(context.Background(), client, result)
+		require.NoError(t, err)
+		assert.True(t, reorged)
+		assert.True(t, requeue)
+	})
+
+	t.Run("Dropped", func(t *testing.T) {
+		client := newReorgTestClient(t, nil)
+		requeue, reorged, err := s.checkReorg(context.Background(), client, result)
+		require.NoError(t, err)
+		assert.True(t, reorged)
+		assert.True(t, requeue)
+	})
+}
+
+// TestMonitorReorgRequeue verifies a dropped fulfillment is re-queued with its exposure reserved again,
+// and that the check is awaited as a background goroutine
+func TestMonitorReorgRequeue(t *testing.T) {
+	s := &Fulfiller{
+		config: &config.Config{
+			RetryPolicies: map[string]config.RetryPolicy{
+				"reorg": {MaxRetries: 3, Backoff: time.Second, MaxBackoff: time.Minute},
+			},
+		},
+		retryJobs: newRetryQueue(0),
+		exposure:  newExposureTracker(0),
+		clock:     clock.Real{},
+		logger:    &logger.EmptyLogger{},
+	}
+	chainClient := newReorgTestClient(t, nil)
+	chainClient.ReorgCheckDepth = 10
+	intent := models.Intent{
+		ID:               "0xabc",
+		SourceChain:      8453,
+		DestinationChain: 42161,
+		Token:            "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913", // USDC on Base
+		Amount:           "25000000",
+	}
+	result := &models.FulfillmentResult{
+		TxHash:      common.HexToHash("0x01").Hex(),
+		BlockHash:   common.HexToHash("0xb1").Hex(),
+		BlockNumber: 100,
+	}
+
+	s.goBackground(func() { s.monitorReorg(context.Background(), chainClient, intent, result) })
+	s.backgroundWg.Wait()
+
+	require.Equal(t, 1, s.retryJobs.len())
+	job := popRetryJob(t, s.retryJobs)
+	assert.Equal(t, "reorg", job.ErrorType)
+	assert.InDelta(t, 25, s.exposure.current(), 0.0001)
+}
+
+// TestWaitConfirmations tests transactions are followed to the block they're re-included in after a reorg
+func TestWaitConfirmations(t *testing.T) {
+	s := &Fulfiller{logger: &logger.EmptyLogger{}}
+	txHash := common.HexToHash("0x01")
+	mined := &types.Receipt{TxHash: txHash, BlockHash: common.HexToHash("0xb1"), BlockNumber: big.NewInt(100)}
+
+	t.Run("Same block", func(t *testing.T) {
+		client := newReorgTestClient(t, &types.Receipt{
+			Status: types.ReceiptStatusSuccessful, TxHash: txHash, BlockHash: mined.BlockHash,
+			BlockNumber: mined.BlockNumber, Logs: []*types.Log{},
+		})
+		client.Confirmations = 3
+		receipt, err := s.waitConfirmations(context.Background(), client, mined)
+		require.NoError(t, err)
+		assert.Equal(t, mined.BlockHash, receipt.BlockHash)
+	})
+
+	t.Run("Re-included in another block", func(t *testing.T) {
+		moved := common.HexToHash("0xb2")
+		client := newReorgTestClient(t, &types.Receipt{
+			Status: types.ReceiptStatusSuccessful, TxHash: txHash, BlockHash: moved,
+			BlockNumber: big.NewInt(101), Logs: []*types.Log{},
+		})
+		client.Confirmations = 3
+		receipt, err := s.waitConfirmations(context.Background(), client, mined)
+		require.NoError(t, err)
+		assert.Equal(t, moved, receipt.BlockHash)
+		assert.Equal(t, uint64(101), receipt.BlockNumber.Uint64())
+	})
+
+	t.Run("Removed from the chain", func(t *testing.T) {
+		client := newReorgTestClient(t, nil)
+		client.Confirmations = 3
+		_, err := s.waitConfirmations(context.Background(), client, mined)
+		require.ErrorIs(t, err, errTxReorged)
+	})
+}
@@ -0,0 +1,54 @@
+package fulfiller
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/speedrun-hq/speedrunner/pkg/metrics"
+	"github.com/speedrun-hq/speedrunner/pkg/models"
+	"github.com/speedrun-hq/speedrunner/pkg/srunclient"
+)
+
+// reportRetries is the number of retries of a failed fulfillment report
+const reportRetries = 3
+
+// reportRetryBackoff is the delay before the first retry of a failed fulfillment report, doubled after each retry
+var reportRetryBackoff = 2 * time.Second
+
+// reportFulfillment reports the fulfillment transaction of the intent to the API when ReportFulfillment is enabled,
+// failed reports are retried with an exponential backoff unless the API rejects them
+func (s *Fulfiller) reportFulfillment(ctx context.Context, intent models.Intent, result *models.FulfillmentResult) {
+	if !s.config.ReportFulfillment {
+		return
+	}
+
+	chainLabel := strconv.Itoa(intent.DestinationChain)
+	backoff := reportRetryBackoff
+	for attempt := 0; ; attempt++ {
+		reportCtx, cancel := context.WithTimeout(ctx, claimTimeout)
+		err := s.srunClient.ReportFulfillment(reportCtx, baseIntentID(intent.ID), result.TxHash, intent.DestinationChain)
+		cancel()
+		if err == nil {
+			s.logger.DebugWithChain(intent.DestinationChain, "Reported fulfillment of intent %s (tx: %s)", intent.ID, result.TxHash)
+			metrics.FulfillmentReports.WithLabelValues(chainLabel, "success").Inc()
+			return
+		}
+		if errors.Is(err, srunclient.ErrReportRejected) || attempt >= reportRetries {
+			s.logger.ErrorWithChain(intent.DestinationChain, "Failed to report fulfillment of intent %s: %v", intent.ID, err)
+			metrics.FulfillmentReports.WithLabelValues(chainLabel, "failed").Inc()
+			return
+		}
+
+		s.logger.DebugWithChain(intent.DestinationChain, "Failed to report fulfillment of intent %s, retrying in %v: %v",
+			intent.ID, backoff, err)
+		select {
+		case <-ctx.Done():
+			metrics.FulfillmentReports.WithLabelValues(chainLabel, "failed").Inc()
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
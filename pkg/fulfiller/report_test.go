@@ -0,0 +1,77 @@
+package fulfiller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/speedrun-hq/speedrunner/pkg/config"
+	"github.com/speedrun-hq/speedrunner/pkg/logger"
+	"github.com/speedrun-hq/speedrunner/pkg/metrics"
+	"github.com/speedrun-hq/speedrunner/pkg/models"
+	"github.com/speedrun-hq/speedrunner/pkg/srunclient"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReportFulfillment verifies failed reports are retried, except when rejected by the API
+func TestReportFulfillment(t *testing.T) {
+	backoff := reportRetryBackoff
+	reportRetryBackoff = time.Millisecond
+	defer func() { reportRetryBackoff = backoff }()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		switch r.URL.Path {
+		case "/api/v1/intents/0x01/fulfillment":
+			// succeeds on the second attempt
+			if n == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case "/api/v1/intents/0x02/fulfillment":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	memLogger := logger.NewMemoryLogger()
+	s := &Fulfiller{
+		config:     &config.Config{ReportFulfillment: true},
+		srunClient: srunclient.New(server.URL, memLogger),
+		logger:     memLogger,
+	}
+	ctx := context.Background()
+	result := &models.FulfillmentResult{TxHash: "0xabc"}
+	successes := testutil.ToFloat64(metrics.FulfillmentReports.WithLabelValues("8453", "success"))
+	failures := testutil.ToFloat64(metrics.FulfillmentReports.WithLabelValues("8453", "failed"))
+
+	// retry tags are removed from the reported intent ID
+	s.reportFulfillment(ctx, models.Intent{ID: "0x01_retry_1", DestinationChain: 8453}, result)
+	assert.Equal(t, int32(2), requests.Load())
+	assert.Equal(t, successes+1, testutil.ToFloat64(metrics.FulfillmentReports.WithLabelValues("8453", "success")))
+
+	// rejected reports are not retried
+	requests.Store(0)
+	s.reportFulfillment(ctx, models.Intent{ID: "0x02", DestinationChain: 8453}, result)
+	assert.Equal(t, int32(1), requests.Load())
+
+	requests.Store(0)
+	s.reportFulfillment(ctx, models.Intent{ID: "0x03", DestinationChain: 8453}, result)
+	assert.Equal(t, int32(reportRetries+1), requests.Load())
+	assert.Equal(t, failures+2, testutil.ToFloat64(metrics.FulfillmentReports.WithLabelValues("8453", "failed")))
+	assert.True(t, memLogger.Contains(logger.ErrorLevel, "Failed to report fulfillment of intent 0x03"))
+
+	// nothing is reported when disabled
+	requests.Store(0)
+	s.config.ReportFulfillment = false
+	s.reportFulfillment(ctx, models.Intent{ID: "0x01", DestinationChain: 8453}, result)
+	assert.Zero(t, requests.Load())
+}
@@ -0,0 +1,78 @@
+package fulfiller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/speedrun-hq/speedrunner/pkg/models"
+)
+
+// saveRetryJobs writes the retry jobs to path so that pending retries survive a restart, nothing is written
+// if path is empty, the file is removed if there are no jobs
+func saveRetryJobs(path string, jobs []models.RetryJob) error {
+	if path == "" {
+		return nil
+	}
+	if len(jobs) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove retry queue file %s: %v", path, err)
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(jobs)
+	if err != nil {
+		return fmt.Errorf("failed to encode retry queue: %v", err)
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return fmt.Errorf("failed to write retry queue file %s: %v", path, err)
+	}
+	return nil
+}
+
+// loadRetryJobs reads the retry jobs saved to path on the last shutdown, the file is rewritten on the next shutdown
+// it returns nil if path is empty or the file doesn't exist
+func loadRetryJobs(path string) ([]models.RetryJob, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read retry queue file %s: %v", path, err)
+	}
+
+	var jobs []models.RetryJob
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &jobs); err != nil {
+			return nil, fmt.Errorf("failed to parse retry queue file %s: %v", path, err)
+		}
+	}
+	return jobs, nil
+}
+
+// flushRetryJobs drains the retry queue and saves the drained jobs with the jobs already saved to the retry queue file
+// on shutdown, it returns all the saved jobs
+func (s *Fulfiller) flushRetryJobs(saved []models.RetryJob) []models.RetryJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	drained := s.retryJobs.drain()
+	jobs := append(saved, drained...)
+	if saved != nil && len(drained) == 0 {
+		return jobs
+	}
+	if err := saveRetryJobs(s.config.RetryQueuePath, jobs); err != nil {
+		s.logger.Error("Error saving %d retry jobs: %v", len(jobs), err)
+		return jobs
+	}
+	if s.config.RetryQueuePath != "" && len(drained) > 0 {
+		s.logger.Notice("Saved %d retry jobs to %s", len(jobs), s.config.RetryQueuePath)
+	}
+	return jobs
+}
@@ -0,0 +1,108 @@
+package fulfiller
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/speedrun-hq/speedrunner/pkg/config"
+	"github.com/speedrun-hq/speedrunner/pkg/logger"
+	"github.com/speedrun-hq/speedrunner/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRetryJobsPersistence tests the retry jobs flushed on shutdown are reloaded in order after a restart
+func TestRetryJobsPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "retries.json")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	queue := newRetryQueue(0)
+	queue.push(models.RetryJob{
+		Intent:      models.Intent{ID: "intent2-retry-1-gas_error", DestinationChain: 42161, CreatedAt: now},
+		RetryCount:  1,
+		NextAttempt: now.Add(2 * time.Minute),
+		ErrorType:   "gas_error",
+		Deadline:    now.Add(time.Hour),
+		FeeUSD:      0.5,
+	})
+	queue.push(models.RetryJob{Intent: models.Intent{ID: "intent1"}, NextAttempt: now.Add(time.Minute)})
+
+	s := &Fulfiller{
+		config:    &config.Config{RetryQueuePath: path},
+		retryJobs: queue,
+		logger:    logger.NewMemoryLogger(),
+	}
+	s.flushRetryJobs(nil)
+	assert.Zero(t, queue.len())
+
+	jobs, err := loadRetryJobs(path)
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, "intent1", jobs[0].Intent.ID)
+	assert.Equal(t, "intent2-retry-1-gas_error", jobs[1].Intent.ID)
+	assert.Equal(t, 1, jobs[1].RetryCount)
+	assert.True(t, jobs[1].NextAttempt.Equal(now.Add(2*time.Minute)))
+	assert.True(t, jobs[1].Deadline.Equal(now.Add(time.Hour)))
+	assert.Equal(t, 0.5, jobs[1].FeeUSD)
+
+	// an empty queue removes the file so that no retries are reloaded
+	s.flushRetryJobs(nil)
+	_, err = os.Stat(path)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	jobs, err = loadRetryJobs(path)
+	require.NoError(t, err)
+	assert.Empty(t, jobs)
+}
+
+// TestRetryJobsPersistenceDisabled tests nothing is saved or loaded without a path
+func TestRetryJobsPersistenceDisabled(t *testing.T) {
+	require.NoError(t, saveRetryJobs("", []models.RetryJob{{Intent: models.Intent{ID: "intent1"}}}))
+	jobs, err := loadRetryJobs("")
+	require.NoError(t, err)
+	assert.Nil(t, jobs)
+
+	path := filepath.Join(t.TempDir(), "retries.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+	_, err = loadRetryJobs(path)
+	assert.ErrorContains(t, err, "failed to parse retry queue file")
+}
+
+// TestStartShutdownSavesRetryJobs tests the shutdown of the service doesn't wait on queued retries and saves them
+func TestStartShutdownSavesRetryJobs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "retries.json")
+	s := &Fulfiller{
+		config: &config.Config{
+			MetricsHost:     "127.0.0.1",
+			MetricsPort:     "0",
+			PollingInterval: time.Hour,
+			RetryQueuePath:  path,
+		},
+		workers:        1,
+		pendingJobs:    make(chan models.Intent, 1),
+		pendingBatches: make(chan []models.Intent, 1),
+		retryJobs:      newRetryQueue(0),
+		exposure:       newExposureTracker(0),
+		logger:         logger.NewMemoryLogger(),
+	}
+	require.True(t, s.scheduleRetry(context.Background(), models.Intent{ID: "intent1", DestinationChain: 42161}, "network_error"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Start(ctx) }()
+	cancel()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown blocked on the queued retry")
+	}
+
+	jobs, err := loadRetryJobs(path)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "intent1_retry_1_error_network_error", jobs[0].Intent.ID)
+}
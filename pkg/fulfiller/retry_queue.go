@@ -0,0 +1,194 @@
+package fulfiller
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/speedrun-hq/speedrunner/pkg/config"
+	"github.com/speedrun-hq/speedrunner/pkg/models"
+)
+
+// retryQueue holds the retry jobs ordered by next attempt, jobs due at the same time are ordered by insertion
+// so that the earliest due job is always processed first
+type retryQueue struct {
+	mu       sync.Mutex
+	jobs     retryJobHeap
+	seq      uint64
+	maxSize  int    // 0 for no limit
+	eviction string // config.RetryEviction* policy applied when the queue is full
+}
+
+// newRetryQueue creates an empty retry queue holding at most maxSize jobs, 0 for no limit,
+// new jobs are rejected when the queue is full
+func newRetryQueue(maxSize int) *retryQueue {
+	return &retryQueue{maxSize: maxSize, eviction: config.RetryEvictionNone}
+}
+
+// newEvictingRetryQueue creates an empty retry queue holding at most maxSize jobs, when the queue is full
+// the job selected by the eviction policy, queued or new, is evicted to make room
+func newEvictingRetryQueue(maxSize int, eviction string) *retryQueue {
+	return &retryQueue{maxSize: maxSize, eviction: eviction}
+}
+
+// push adds the job to the queue, it returns false if the queue is full and the job is rejected,
+// a queued job evicted to make room for it is returned
+func (q *retryQueue) push(job models.RetryJob) (bool, *models.RetryJob) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.maxSize <= 0 || len(q.jobs) < q.maxSize {
+		q.add(job)
+		return true, nil
+	}
+
+	// the new job is rejected if no policy applies or if it is the one the policy would evict
+	index := q.evictionIndex()
+	if index < 0 || !q.evictsBefore(q.jobs[index].job, job) {
+		return false, nil
+	}
+	evicted := heap.Remove(&q.jobs, index).(queuedRetryJob).job
+	q.add(job)
+	return true, &evicted
+}
+
+// evictionIndex returns the index of the queued job evicted first by the policy, -1 if jobs are never evicted,
+// caller must hold the lock
+func (q *retryQueue) evictionIndex() int {
+	if q.eviction != config.RetryEvictionOldest && q.eviction != config.RetryEvictionLowestFee {
+		return -1
+	}
+	index := -1
+	for i, queued := range q.jobs {
+		if index < 0 || q.evictsBefore(queued.job, q.jobs[index].job) {
+			index = i
+		}
+	}
+	return index
+}
+
+// evictsBefore returns true if the policy evicts job a before job b, ties evict the oldest intent
+func (q *retryQueue) evictsBefore(a, b models.RetryJob) bool {
+	if q.eviction == config.RetryEvictionLowestFee && a.FeeUSD != b.FeeUSD {
+		return a.FeeUSD < b.FeeUSD
+	}
+	return a.Intent.CreatedAt.Before(b.Intent.CreatedAt)
+}
+
+// requeue puts back a job popped from the queue, regardless of the size limit so that it is never lost
+func (q *retryQueue) requeue(job models.RetryJob) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.add(job)
+}
+
+// add pushes the job to the heap, caller must hold the lock
+func (q *retryQueue) add(job models.RetryJob) {
+	q.seq++
+	heap.Push(&q.jobs, queuedRetryJob{job: job, seq: q.seq})
+}
+
+// pop removes and returns the earliest due job, false if the queue is empty
+func (q *retryQueue) pop() (models.RetryJob, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.jobs) == 0 {
+		return models.RetryJob{}, false
+	}
+	return heap.Pop(&q.jobs).(queuedRetryJob).job, true
+}
+
+// drain removes and returns all the jobs in order of next attempt
+func (q *retryQueue) drain() []models.RetryJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	jobs := make([]models.RetryJob, 0, len(q.jobs))
+	for len(q.jobs) > 0 {
+		jobs = append(jobs, heap.Pop(&q.jobs).(queuedRetryJob).job)
+	}
+	return jobs
+}
+
+// popDue removes and returns the earliest job if it is due at now, false if no job is due
+func (q *retryQueue) popDue(now time.Time) (models.RetryJob, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.jobs) == 0 || now.Before(q.jobs[0].job.NextAttempt) {
+		return models.RetryJob{}, false
+	}
+	return heap.Pop(&q.jobs).(queuedRetryJob).job, true
+}
+
+// removeExpired removes and returns the jobs whose deadline is passed at now, due or not
+func (q *retryQueue) removeExpired(now time.Time) []models.RetryJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var expired []models.RetryJob
+	kept := q.jobs[:0]
+	for _, queued := range q.jobs {
+		if queued.job.Expired(now) {
+			expired = append(expired, queued.job)
+		} else {
+			kept = append(kept, queued)
+		}
+	}
+	if len(expired) > 0 {
+		clear(q.jobs[len(kept):])
+		q.jobs = kept
+		heap.Init(&q.jobs)
+	}
+	return expired
+}
+
+// nextAttempt returns the next attempt of the earliest job, false if the queue is empty
+func (q *retryQueue) nextAttempt() (time.Time, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.jobs) == 0 {
+		return time.Time{}, false
+	}
+	return q.jobs[0].job.NextAttempt, true
+}
+
+// len returns the number of jobs in the queue
+func (q *retryQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.jobs)
+}
+
+// queuedRetryJob is a retry job with its insertion sequence number
+type queuedRetryJob struct {
+	job models.RetryJob
+	seq uint64
+}
+
+// retryJobHeap is a min-heap of retry jobs by next attempt then insertion, implementing heap.Interface
+type retryJobHeap []queuedRetryJob
+
+func (h retryJobHeap) Len() int { return len(h) }
+
+func (h retryJobHeap) Less(i, j int) bool {
+	if !h[i].job.NextAttempt.Equal(h[j].job.NextAttempt) {
+		return h[i].job.NextAttempt.Before(h[j].job.NextAttempt)
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h retryJobHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *retryJobHeap) Push(x any) { *h = append(*h, x.(queuedRetryJob)) }
+
+func (h *retryJobHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = queuedRetryJob{}
+	*h = old[:n-1]
+	return item
+}
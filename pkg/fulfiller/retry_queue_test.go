@@ -0,0 +1,158 @@
+package fulfiller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/speedrun-hq/speedrunner/pkg/config"
+	"github.com/speedrun-hq/speedrunner/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// popRetryJob pops the earliest job of the queue, failing the test if it is empty
+func popRetryJob(t *testing.T, q *retryQueue) models.RetryJob {
+	t.Helper()
+	job, ok := q.pop()
+	require.True(t, ok, "retry queue is empty")
+	return job
+}
+
+// TestRetryQueueOrder verifies jobs are popped by next attempt then insertion order
+func TestRetryQueueOrder(t *testing.T) {
+	q := newRetryQueue(0)
+	now := time.Now()
+
+	q.push(models.RetryJob{Intent: models.Intent{ID: "c"}, NextAttempt: now.Add(2 * time.Minute)})
+	q.push(models.RetryJob{Intent: models.Intent{ID: "a"}, NextAttempt: now.Add(-time.Minute)})
+	q.push(models.RetryJob{Intent: models.Intent{ID: "b1"}, NextAttempt: now})
+	q.push(models.RetryJob{Intent: models.Intent{ID: "b2"}, NextAttempt: now})
+	require.Equal(t, 4, q.len())
+
+	next, ok := q.nextAttempt()
+	require.True(t, ok)
+	assert.Equal(t, now.Add(-time.Minute), next)
+
+	// only the due jobs are popped, earliest first
+	var ids []string
+	for {
+		job, ok := q.popDue(now)
+		if !ok {
+			break
+		}
+		ids = append(ids, job.Intent.ID)
+	}
+	assert.Equal(t, []string{"a", "b1", "b2"}, ids)
+	assert.Equal(t, 1, q.len())
+
+	assert.Equal(t, "c", popRetryJob(t, q).Intent.ID)
+	_, ok = q.pop()
+	assert.False(t, ok)
+	_, ok = q.nextAttempt()
+	assert.False(t, ok)
+}
+
+// TestRetryQueueMaxSize verifies jobs are rejected when the queue is full, but popped jobs can always be put back
+func TestRetryQueueMaxSize(t *testing.T) {
+	q := newRetryQueue(2)
+	pushed, _ := q.push(models.RetryJob{Intent: models.Intent{ID: "a"}})
+	assert.True(t, pushed)
+	pushed, _ = q.push(models.RetryJob{Intent: models.Intent{ID: "b"}})
+	assert.True(t, pushed)
+	pushed, evicted := q.push(models.RetryJob{Intent: models.Intent{ID: "c"}})
+	assert.False(t, pushed)
+	assert.Nil(t, evicted)
+	assert.Equal(t, 2, q.len())
+
+	q.requeue(models.RetryJob{Intent: models.Intent{ID: "d"}})
+	assert.Equal(t, 3, q.len())
+}
+
+// TestRetryQueueEviction verifies a full queue evicts the job selected by the policy unless the new job would be evicted first
+func TestRetryQueueEviction(t *testing.T) {
+	now := time.Now()
+	jobs := []models.RetryJob{
+		{Intent: models.Intent{ID: "old_high", CreatedAt: now.Add(-time.Hour)}, FeeUSD: 10},
+		{Intent: models.Intent{ID: "new_low", CreatedAt: now.Add(-time.Minute)}, FeeUSD: 1},
+	}
+
+	tests := []struct {
+		name        string
+		eviction    string
+		job         models.RetryJob
+		wantPushed  bool
+		wantEvicted string
+	}{
+		{
+			name:       "none rejects the new job",
+			eviction:   config.RetryEvictionNone,
+			job:        models.RetryJob{Intent: models.Intent{ID: "c", CreatedAt: now}, FeeUSD: 100},
+			wantPushed: false,
+		},
+		{
+			name:        "oldest evicts the oldest intent",
+			eviction:    config.RetryEvictionOldest,
+			job:         models.RetryJob{Intent: models.Intent{ID: "c", CreatedAt: now}},
+			wantPushed:  true,
+			wantEvicted: "old_high",
+		},
+		{
+			name:       "oldest rejects an older intent",
+			eviction:   config.RetryEvictionOldest,
+			job:        models.RetryJob{Intent: models.Intent{ID: "c", CreatedAt: now.Add(-2 * time.Hour)}},
+			wantPushed: false,
+		},
+		{
+			name:        "lowest fee evicts the lowest fee",
+			eviction:    config.RetryEvictionLowestFee,
+			job:         models.RetryJob{Intent: models.Intent{ID: "c", CreatedAt: now}, FeeUSD: 5},
+			wantPushed:  true,
+			wantEvicted: "new_low",
+		},
+		{
+			name:       "lowest fee rejects a lower fee",
+			eviction:   config.RetryEvictionLowestFee,
+			job:        models.RetryJob{Intent: models.Intent{ID: "c", CreatedAt: now}, FeeUSD: 0.5},
+			wantPushed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newEvictingRetryQueue(len(jobs), tt.eviction)
+			for _, job := range jobs {
+				pushed, _ := q.push(job)
+				require.True(t, pushed)
+			}
+
+			pushed, evicted := q.push(tt.job)
+			assert.Equal(t, tt.wantPushed, pushed)
+			if tt.wantEvicted == "" {
+				assert.Nil(t, evicted)
+			} else {
+				require.NotNil(t, evicted)
+				assert.Equal(t, tt.wantEvicted, evicted.Intent.ID)
+			}
+			assert.Equal(t, len(jobs), q.len())
+		})
+	}
+}
+
+// TestRetryQueueRemoveExpired verifies expired jobs are removed wherever they are in the queue
+func TestRetryQueueRemoveExpired(t *testing.T) {
+	q := newRetryQueue(0)
+	now := time.Now()
+
+	q.push(models.RetryJob{Intent: models.Intent{ID: "due"}, NextAttempt: now})
+	q.push(models.RetryJob{Intent: models.Intent{ID: "expired"}, NextAttempt: now.Add(time.Hour), Deadline: now.Add(-time.Second)})
+	q.push(models.RetryJob{Intent: models.Intent{ID: "later"}, NextAttempt: now.Add(time.Minute), Deadline: now.Add(time.Hour)})
+
+	expired := q.removeExpired(now)
+	require.Len(t, expired, 1)
+	assert.Equal(t, "expired", expired[0].Intent.ID)
+	assert.Empty(t, q.removeExpired(now))
+
+	require.Equal(t, 2, q.len())
+	assert.Equal(t, "due", popRetryJob(t, q).Intent.ID)
+	assert.Equal(t, "later", popRetryJob(t, q).Intent.ID)
+}
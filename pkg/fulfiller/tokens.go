@@ -1,28 +1,24 @@
 package fulfiller
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/speedrun-hq/speedrunner/pkg/contracts"
+	"github.com/speedrun-hq/speedrunner/pkg/chains"
+	"github.com/speedrun-hq/speedrunner/pkg/models"
 )
 
 // getTokenBalance gets the token balance for a given chain and token address
 func (s *Fulfiller) getTokenBalance(chainID int, tokenAddress common.Address) (*big.Float, error) {
-	chainClient, exists := s.chainClients[chainID]
-	if !exists {
-		return nil, fmt.Errorf("chain client not found for chain %d", chainID)
-	}
-
-	// Create ERC20 contract instance
-	token, err := contracts.NewERC20(tokenAddress, chainClient.Client)
+	reader, err := s.balanceReader(chainID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create ERC20 contract: %v", err)
+		return nil, err
 	}
 
 	// Get raw balance
-	rawBalance, err := token.BalanceOf(nil, common.HexToAddress(s.config.FulfillerAddress))
+	rawBalance, err := reader.TokenBalance(context.Background(), tokenAddress, common.HexToAddress(s.config.FulfillerAddress))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get token balance: %v", err)
 	}
@@ -32,3 +28,86 @@ func (s *Fulfiller) getTokenBalance(chainID int, tokenAddress common.Address) (*
 
 	return balanceFloat, nil
 }
+
+// getNativeBalance gets the native token balance of the fulfiller for a given chain
+func (s *Fulfiller) getNativeBalance(chainID int) (*big.Float, error) {
+	reader, err := s.balanceReader(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	rawBalance, err := reader.NativeBalance(context.Background(), common.HexToAddress(s.config.FulfillerAddress))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get native balance: %v", err)
+	}
+
+	return new(big.Float).SetInt(rawBalance), nil
+}
+
+// amountUSD returns the USD value of an amount of the intent token on the destination chain
+// native token amounts are converted with the price of the gas token of the destination chain
+func amountUSD(amount *big.Int, intent models.Intent, nativePriceUSD float64) (float64, error) {
+	tokenType := chains.GetTokenType(intent.Token)
+	standardized, err := chains.GetStandardizedAmount(amount, intent.DestinationChain, tokenType)
+	if err != nil {
+		return 0, err
+	}
+	if tokenType != chains.TokenTypeNative {
+		return standardized, nil
+	}
+	if nativePriceUSD <= 0 {
+		return 0, fmt.Errorf("native token price unknown for chain %d", intent.DestinationChain)
+	}
+	return standardized * nativePriceUSD, nil
+}
+
+// intentFeeUSD returns the intent fee in USD, the fee is converted from the units of the token on the source chain
+// to the destination chain before being standardized, nativePriceUSD is used for native token intents.
+// All the fee checks of an intent compare USD values from this helper
+func intentFeeUSD(intent models.Intent, nativePriceUSD float64) (float64, error) {
+	fee, ok := new(big.Int).SetString(intent.IntentFee, 10)
+	if !ok {
+		return 0, fmt.Errorf("invalid intent fee: %s", intent.IntentFee)
+	}
+	return amountUSD(convertTokenUnits(fee, intent), intent, nativePriceUSD)
+}
+
+// intentMinFeeUSD returns the min fee in USD of the intent on the destination chain, minFeeUSD takes precedence if set,
+// otherwise minFee is in base units of the intent token on the destination chain
+func intentMinFeeUSD(minFee *big.Int, minFeeUSD float64, intent models.Intent, nativePriceUSD float64) (float64, error) {
+	if minFeeUSD > 0 {
+		return minFeeUSD, nil
+	}
+	if minFee == nil || minFee.Sign() <= 0 {
+		return 0, nil
+	}
+	return amountUSD(minFee, intent, nativePriceUSD)
+}
+
+// convertTokenUnits converts an intent amount from the decimals of the token on the source chain to its decimals
+// on the destination chain (e.g. USDC has 18 decimals on BSC and 6 on other chains)
+func convertTokenUnits(amount *big.Int, intent models.Intent) *big.Int {
+	// the native token has 18 decimals on all chains, same-chain intents use the same token on both sides
+	if chains.IsNativeToken(intent.Token) || intent.SourceChain == intent.DestinationChain {
+		return amount
+	}
+	tokenType := chains.GetTokenType(intent.Token)
+	sourceDecimals, err := chains.GetDecimals(intent.SourceChain, tokenType)
+	if err != nil {
+		return amount
+	}
+	destinationDecimals, err := chains.GetDecimals(intent.DestinationChain, tokenType)
+	if err != nil {
+		return amount
+	}
+
+	switch {
+	case sourceDecimals > destinationDecimals:
+		scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(sourceDecimals-destinationDecimals)), nil)
+		return new(big.Int).Div(amount, scale)
+	case sourceDecimals < destinationDecimals:
+		scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(destinationDecimals-sourceDecimals)), nil)
+		return new(big.Int).Mul(amount, scale)
+	}
+	return amount
+}
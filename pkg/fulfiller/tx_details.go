@@ -0,0 +1,61 @@
+package fulfiller
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// fulfillTx holds the parameters of a fulfill transaction, logged when it fails with LogTxDetailsOnError
+type fulfillTx struct {
+	intentIDs     []string
+	intentAddress common.Address
+	token         common.Address
+	amount        *big.Int
+	receiver      common.Address
+	opts          *bind.TransactOpts
+}
+
+// logTxFailure logs the parameters of a failed fulfill transaction when LogTxDetailsOnError is enabled,
+// tx is nil if the transaction couldn't be sent, the gas and nonce of the transactor are logged instead
+func (s *Fulfiller) logTxFailure(chainID int, details fulfillTx, tx *types.Transaction, err error) {
+	if !s.config.LogTxDetailsOnError {
+		return
+	}
+
+	txHash := "none"
+	nonce := "pending"
+	gasPrice, gasTipCap, gasFeeCap := details.opts.GasPrice, details.opts.GasTipCap, details.opts.GasFeeCap
+	gasLimit := details.opts.GasLimit
+	if details.opts.Nonce != nil {
+		nonce = details.opts.Nonce.String()
+	}
+	if tx != nil {
+		txHash = tx.Hash().Hex()
+		nonce = strconv.FormatUint(tx.Nonce(), 10)
+		gasLimit = tx.Gas()
+		if tx.Type() == types.LegacyTxType || tx.Type() == types.AccessListTxType {
+			gasPrice, gasTipCap, gasFeeCap = tx.GasPrice(), nil, nil
+		} else {
+			gasPrice, gasTipCap, gasFeeCap = nil, tx.GasTipCap(), tx.GasFeeCap()
+		}
+	}
+
+	s.logger.ErrorWithChain(chainID, "Fulfill transaction failed: intents=%s contract=%s token=%s amount=%s receiver=%s "+
+		"from=%s value=%s nonce=%s gas_price=%s gas_tip_cap=%s gas_fee_cap=%s gas_limit=%d tx=%s error=%v",
+		strings.Join(details.intentIDs, ","), details.intentAddress.Hex(), details.token.Hex(), weiString(details.amount),
+		details.receiver.Hex(), details.opts.From.Hex(), weiString(details.opts.Value), nonce,
+		weiString(gasPrice), weiString(gasTipCap), weiString(gasFeeCap), gasLimit, txHash, err)
+}
+
+// weiString returns the decimal representation of a wei amount, "none" if not set
+func weiString(amount *big.Int) string {
+	if amount == nil {
+		return "none"
+	}
+	return amount.String()
+}
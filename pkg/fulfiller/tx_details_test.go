@@ -0,0 +1,52 @@
+package fulfiller
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/speedrun-hq/speedrunner/pkg/config"
+	"github.com/speedrun-hq/speedrunner/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLogTxFailure verifies the parameters of failed fulfill transactions are only logged when enabled
+func TestLogTxFailure(t *testing.T) {
+	log := logger.NewMemoryLogger()
+	s := &Fulfiller{config: &config.Config{}, logger: log}
+	details := fulfillTx{
+		intentIDs:     []string{"0x01", "0x02"},
+		intentAddress: common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		token:         common.HexToAddress("0xaf88d065e77c8cC2239327C5EDb3A432268e5831"),
+		amount:        big.NewInt(3_500_000),
+		receiver:      common.HexToAddress("0x1234567890abcdef1234567890abcdef12345678"),
+		opts:          &bind.TransactOpts{GasPrice: big.NewInt(1_000_000_000)},
+	}
+
+	s.logTxFailure(42161, details, nil, errors.New("execution reverted"))
+	assert.Empty(t, log.Entries())
+
+	// the transactor parameters are logged when the transaction couldn't be sent
+	s.config.LogTxDetailsOnError = true
+	s.logTxFailure(42161, details, nil, errors.New("execution reverted"))
+	require.Len(t, log.Entries(), 1)
+	message := log.Entries()[0].Message
+	assert.Contains(t, message, "intents=0x01,0x02")
+	assert.Contains(t, message, "amount=3500000")
+	assert.Contains(t, message, "receiver=0x1234567890AbcdEF1234567890aBcdef12345678")
+	assert.Contains(t, message, "nonce=pending gas_price=1000000000 gas_tip_cap=none")
+	assert.Contains(t, message, "tx=none error=execution reverted")
+
+	// the parameters of the sent transaction are logged once it exists
+	log.Reset()
+	tx := types.NewTx(&types.DynamicFeeTx{Nonce: 7, GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(30), Gas: 90000})
+	s.logTxFailure(42161, details, tx, errors.New("transaction failed on 42161"))
+	require.Len(t, log.Entries(), 1)
+	message = log.Entries()[0].Message
+	assert.Contains(t, message, "nonce=7 gas_price=none gas_tip_cap=2 gas_fee_cap=30 gas_limit=90000")
+	assert.Contains(t, message, "tx="+tx.Hash().Hex())
+}
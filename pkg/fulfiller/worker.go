@@ -2,156 +2,423 @@ package fulfiller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
+	"math/rand"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
+	"github.com/speedrun-hq/speedrunner/pkg/config"
 	"github.com/speedrun-hq/speedrunner/pkg/metrics"
 	"github.com/speedrun-hq/speedrunner/pkg/models"
 )
 
-// worker processes intents from the job queue
+// worker processes intents and batches of intents from the job queues
 func (s *Fulfiller) worker(ctx context.Context, id int) {
 	s.logger.Info("Starting worker %d", id)
-	for {
+	jobs, batches := s.pendingJobs, s.pendingBatches
+	for jobs != nil || batches != nil {
 		select {
 		case <-ctx.Done():
 			s.logger.Info("Worker %d shutting down", id)
 			return
-		case intent, ok := <-s.pendingJobs:
+		case intent, ok := <-jobs:
 			if !ok {
-				// Channel closed
-				s.logger.Info("Worker %d shutting down: channel closed", id)
-				return
+				jobs = nil
+				continue
 			}
-
-			// Check if circuit breaker is enabled and open for destination chain
-			if cb, ok := s.circuitBreakers[intent.DestinationChain]; ok && cb.IsEnabled() && cb.IsOpen() {
-				failureCount, lastFailure, _, _ := cb.GetState()
-				s.logger.Info("Worker %d: Circuit breaker open for chain %d (last failure: %v, failure count: %d), skipping intent %s",
-					id, intent.DestinationChain, lastFailure, failureCount, intent.ID)
-				s.wg.Done()
+			s.processIntent(ctx, id, intent)
+		case batch, ok := <-batches:
+			if !ok {
+				batches = nil
 				continue
 			}
+			s.processBatch(ctx, id, batch)
+		}
+	}
+	// Channels closed
+	s.logger.Info("Worker %d shutting down: channel closed", id)
+}
+
+// processIntent fulfills an intent of the job queue
+func (s *Fulfiller) processIntent(ctx context.Context, id int, intent models.Intent) {
+	// Check if circuit breaker is enabled and open for destination chain
+	if cb, ok := s.circuitBreakers[intent.DestinationChain]; ok && cb.IsEnabled() && cb.IsOpen() {
+		failureCount, lastFailure, _, _ := cb.GetState()
+		s.logger.Info("Worker %d: Circuit breaker open for chain %d (last failure: %v, failure count: %d), skipping intent %s",
+			id, intent.DestinationChain, lastFailure, failureCount, intent.ID)
+		s.releaseExposure(intent)
+		s.wg.Done()
+		return
+	}
+
+	// Limit the concurrency on the chain while its circuit breaker is in cooldown
+	release, err := s.acquireCooldownSlot(ctx, intent.DestinationChain)
+	if err != nil {
+		s.releaseExposure(intent)
+		s.wg.Done()
+		return
+	}
+	defer release()
+
+	// Skip the intent if it was fulfilled since it was fetched, then claim it
+	// so that other instances don't fulfill it concurrently
+	if !s.verifyIntentStatus(ctx, intent) || !s.claimIntent(ctx, intent) {
+		s.releaseExposure(intent)
+		s.wg.Done()
+		return
+	}
+
+	s.runIntent(ctx, id, intent)
+}
+
+// acquireCooldownSlot waits for one of the CooldownConcurrency slots of the chain while its circuit breaker is in cooldown,
+// it returns the function releasing the slot, the chain isn't limited outside the cooldown
+func (s *Fulfiller) acquireCooldownSlot(ctx context.Context, chainID int) (func(), error) {
+	slots := s.cooldownSlots[chainID]
+	cb, ok := s.circuitBreakers[chainID]
+	if slots == nil || !ok || !cb.InCooldown() {
+		return func() {}, nil
+	}
+
+	select {
+	case slots <- struct{}{}:
+	default:
+		s.logger.DebugWithChain(chainID, "Circuit breaker cooldown, waiting for one of the %d slots", cap(slots))
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return func() { <-slots }, nil
+}
+
+// runIntent fulfills a claimed intent and handles the result
+func (s *Fulfiller) runIntent(ctx context.Context, id int, intent models.Intent) {
+	s.logger.Info("Worker %d processing intent %s (source: %d, dest: %d, amount: %s)",
+		id, intent.ID, intent.SourceChain, intent.DestinationChain, intent.Amount)
+
+	// Record start time for processing duration metric
+	startTime := time.Now()
+
+	result, err := s.fulfillWithTimeout(ctx, intent)
+
+	// Record processing time
+	processingTime := time.Since(startTime).Seconds()
+	metrics.IntentProcessingTime.WithLabelValues(strconv.Itoa(intent.DestinationChain)).Observe(processingTime)
+
+	s.handleResult(ctx, id, intent, result, err)
+}
+
+// handleResult records the result of the fulfillment of an intent and schedules its retry on failure,
+// it releases the job of the intent unless a retry is queued
+func (s *Fulfiller) handleResult(ctx context.Context, id int, intent models.Intent, result *models.FulfillmentResult, err error) {
+	retrying := false
+	if err != nil {
+		s.logger.Info("Worker %d error fulfilling intent %s: %v", id, intent.ID, err)
 
-			s.logger.Info("Worker %d processing intent %s (source: %d, dest: %d, amount: %s)",
-				id, intent.ID, intent.SourceChain, intent.DestinationChain, intent.Amount)
-
-			// Record start time for processing duration metric
-			startTime := time.Now()
-
-			err := s.fulfillIntent(ctx, intent)
-
-			// Record processing time
-			processingTime := time.Since(startTime).Seconds()
-			metrics.IntentProcessingTime.WithLabelValues(strconv.Itoa(intent.DestinationChain)).Observe(processingTime)
-
-			if err != nil {
-				s.logger.Info("Worker %d error fulfilling intent %s: %v", id, intent.ID, err)
-
-				// Classify error to determine if retry is needed
-				shouldRetry, errorType := shouldRetryError(err)
-
-				// Log the error classification
-				s.logger.Info("Error fulfilling intent %s classified as: %s (retry: %v)", intent.ID, errorType, shouldRetry)
-
-				// Track error type in metrics
-				metrics.FulfillmentErrors.WithLabelValues(strconv.Itoa(intent.DestinationChain), errorType).Inc()
-
-				// If it's an "already processed" type of error, mark as success and don't retry
-				if errorType == "already_processed" {
-					s.logger.Info("Intent %s is already settled or fulfilled, marking as success", intent.ID)
-					metrics.IntentsFulfilled.WithLabelValues(strconv.Itoa(intent.DestinationChain), "success").Inc()
-					s.wg.Done()
-					continue
-				}
-
-				// Record failure in circuit breaker
-				circuitTripped := false
-				if cb, ok := s.circuitBreakers[intent.DestinationChain]; ok {
-					circuitTripped = cb.RecordFailure()
-					failureCount, _, failureWindow, failThreshold := cb.GetState()
-					if circuitTripped {
-						s.logger.Info("Circuit breaker tripped for chain %d - threshold reached: %d failures in %v window",
-							intent.DestinationChain, failureCount, failureWindow)
-					} else {
-						s.logger.Info("Recorded failure for chain %d - current count: %d/%d in %v window",
-							intent.DestinationChain, failureCount, failThreshold, failureWindow)
-					}
-				}
-
-				// Update metrics for failed intent
-				metrics.IntentsFulfilled.WithLabelValues(strconv.Itoa(intent.DestinationChain), "failed").Inc()
-
-				// Only retry if we should retry this error type and circuit is not tripped
-				if shouldRetry && !circuitTripped {
-					// Check for retry tag in intent ID to determine retry count
-					parts := strings.Split(intent.ID, "_retry_")
-					retryCount := 0
-					if len(parts) > 1 {
-						retryCount, _ = strconv.Atoi(parts[1])
-					}
-
-					// Only retry up to 3 times
-					if retryCount < 3 {
-						// Calculate exponential backoff (2^retry * 10 seconds)
-						backoff := time.Duration(math.Pow(2, float64(retryCount))) * 10 * time.Second
-
-						// Set a maximum backoff of 2 minutes
-						maxBackoff := 2 * time.Minute
-						if backoff > maxBackoff {
-							backoff = maxBackoff
-						}
-
-						nextAttempt := time.Now().Add(backoff)
-
-						// Create a retry job
-						retryJob := models.RetryJob{
-							Intent:      intent,
-							RetryCount:  retryCount + 1,
-							NextAttempt: nextAttempt,
-						}
-
-						// Store error type in the ID for now (since the field is causing linter issues)
-						if errorType != "" {
-							// Add error type as a tag to the intent ID
-							retryJob.Intent.ID = fmt.Sprintf("%s_retry_%d_error_%s", parts[0], retryCount+1, errorType)
-						} else {
-							// Standard ID format without error type
-							retryJob.Intent.ID = fmt.Sprintf("%s_retry_%d", parts[0], retryCount+1)
-						}
-
-						// Update retry count metric
-						metrics.RetryCount.WithLabelValues(strconv.Itoa(intent.DestinationChain)).Inc()
-
-						s.logger.Info("Scheduling retry for intent %s in %v (error: %s)", intent.ID, backoff, errorType)
-						s.wg.Add(1)
-						s.retryJobs <- retryJob
-					} else {
-						s.logger.Info("Max retries reached for intent %s, giving up (error: %s)", intent.ID, errorType)
-						metrics.MaxRetriesReached.WithLabelValues(strconv.Itoa(intent.DestinationChain), errorType).Inc()
-					}
-				} else if !shouldRetry {
-					s.logger.Info("Not retrying intent %s due to permanent error type: %s", intent.ID, errorType)
-					metrics.PermanentErrors.WithLabelValues(strconv.Itoa(intent.DestinationChain), errorType).Inc()
-				} else {
-					s.logger.Info("Skipping retry for intent %s due to tripped circuit breaker", intent.ID)
-				}
+		// Classify error to determine if retry is needed
+		shouldRetry, errorType := shouldRetryError(err)
+
+		// Log the error classification
+		s.logger.Info("Error fulfilling intent %s classified as: %s (retry: %v)", intent.ID, errorType, shouldRetry)
+
+		// Track error type in metrics
+		metrics.FulfillmentErrors.WithLabelValues(strconv.Itoa(intent.DestinationChain), errorType).Inc()
+
+		// Realign the local nonce state with the chain before the retry
+		if errorType == "nonce_error" {
+			s.syncNonces(ctx, intent.DestinationChain)
+		}
+
+		// If it's an "already processed" type of error, mark as success and don't retry
+		if errorType == "already_processed" {
+			s.logger.Info("Intent %s is already settled or fulfilled, marking as success", intent.ID)
+			metrics.IntentsFulfilled.WithLabelValues(strconv.Itoa(intent.DestinationChain), "success").Inc()
+			s.recordFulfilled(intent)
+			s.releaseExposure(intent)
+			s.wg.Done()
+			return
+		}
+
+		// Record failure in circuit breaker
+		circuitTripped := false
+		if cb, ok := s.circuitBreakers[intent.DestinationChain]; ok {
+			circuitTripped = cb.RecordFailure()
+			failureCount, _, failureWindow, failThreshold := cb.GetState()
+			if circuitTripped {
+				s.logger.Info("Circuit breaker tripped for chain %d - threshold reached: %d failures in %v window",
+					intent.DestinationChain, failureCount, failureWindow)
 			} else {
-				s.logger.Info("Worker %d successfully fulfilled intent %s", id, intent.ID)
-				// Update metrics for successful intent
-				metrics.IntentsFulfilled.WithLabelValues(strconv.Itoa(intent.DestinationChain), "success").Inc()
+				s.logger.Info("Recorded failure for chain %d - current count: %d/%d in %v window",
+					intent.DestinationChain, failureCount, failThreshold, failureWindow)
 			}
-			s.wg.Done()
 		}
+
+		// Update metrics for failed intent
+		metrics.IntentsFulfilled.WithLabelValues(strconv.Itoa(intent.DestinationChain), "failed").Inc()
+
+		// Only retry if we should retry this error type and circuit is not tripped
+		if shouldRetry && !circuitTripped {
+			retrying = s.scheduleRetry(ctx, intent, errorType)
+		} else if !shouldRetry {
+			s.logger.Info("Not retrying intent %s due to permanent error type: %s", intent.ID, errorType)
+			metrics.PermanentErrors.WithLabelValues(strconv.Itoa(intent.DestinationChain), errorType).Inc()
+			s.releaseIntent(ctx, intent)
+		} else {
+			s.logger.Info("Skipping retry for intent %s due to tripped circuit breaker", intent.ID)
+			s.releaseIntent(ctx, intent)
+		}
+	} else {
+		s.logger.Info("Worker %d successfully fulfilled intent %s (tx: %s, gas used: %d, gas price: %s, approval: %v)",
+			id, intent.ID, result.TxHash, result.GasUsed, result.GasPrice, result.ApprovalNeeded)
+		s.recordFulfilled(intent)
+		s.goBackground(func() { s.reportFulfillment(ctx, intent, result) })
+		// Update metrics for successful intent
+		metrics.IntentsFulfilled.WithLabelValues(strconv.Itoa(intent.DestinationChain), "success").Inc()
+		metrics.GasUsed.WithLabelValues(strconv.Itoa(intent.DestinationChain)).Observe(float64(result.GasUsed))
+		if result.ApprovalNeeded {
+			metrics.ApprovalGasUsed.WithLabelValues(strconv.Itoa(intent.DestinationChain)).Observe(float64(result.ApprovalGasUsed))
+		}
+		if chainClient, ok := s.chainClients[intent.DestinationChain]; ok {
+			s.checkGasUsed(intent, result, chainClient)
+			s.recordProfit(intent, result, chainClient)
+			s.exportFulfillment(intent, result, chainClient)
+
+			// Re-check the fulfillment once deep enough on chains subject to reorgs
+			s.goBackground(func() { s.monitorReorg(ctx, chainClient, intent, result) })
+		}
+	}
+	if !retrying {
+		s.releaseExposure(intent)
+	}
+	s.wg.Done()
+}
+
+// goBackground runs f in a goroutine awaited on shutdown before the retries are saved and the chain clients closed
+func (s *Fulfiller) goBackground(f func()) {
+	s.backgroundWg.Add(1)
+	go func() {
+		defer s.backgroundWg.Done()
+		f()
+	}()
+}
+
+// scheduleRetry queues a retry of the intent with the backoff of the error type retry policy,
+// or gives up if the policy max retries is reached, it returns true if a retry was queued
+func (s *Fulfiller) scheduleRetry(ctx context.Context, intent models.Intent, errorType string) bool {
+	// Check for retry tag in intent ID to determine retry count
+	retryCount, _ := parseRetryTag(intent.ID)
+
+	policy := s.retryPolicy(errorType)
+	if retryCount >= policy.MaxRetries {
+		s.logger.Info("Max retries reached for intent %s, giving up (error: %s)", intent.ID, errorType)
+		metrics.MaxRetriesReached.WithLabelValues(strconv.Itoa(intent.DestinationChain), errorType).Inc()
+		s.releaseIntent(ctx, intent)
+		return false
+	}
+
+	backoff := retryBackoff(policy, retryCount)
+	if s.config.RetryJitter {
+		backoff = fullJitter(backoff)
+	}
+
+	// Create a retry job
+	retryJob := models.RetryJob{
+		Intent:      intent,
+		RetryCount:  retryCount + 1,
+		NextAttempt: s.now().Add(backoff),
+		ErrorType:   errorType,
+		Deadline:    s.retryDeadline(intent),
+		FeeUSD:      s.retryFeeUSD(intent),
+	}
+	if retryJob.Expired(s.now()) {
+		s.dropExpiredRetry(ctx, retryJob)
+		return false
+	}
+
+	// Store error type in the ID for now (since the field is causing linter issues)
+	if errorType != "" {
+		// Add error type as a tag to the intent ID
+		retryJob.Intent.ID = fmt.Sprintf("%s_retry_%d_error_%s", baseIntentID(intent.ID), retryCount+1, errorType)
+	} else {
+		// Standard ID format without error type
+		retryJob.Intent.ID = fmt.Sprintf("%s_retry_%d", baseIntentID(intent.ID), retryCount+1)
 	}
+
+	// Give up rather than block the worker when the retry queue is full
+	// queued jobs are not counted in the wait group until they are dispatched to the workers
+	pushed, evicted := s.retryJobs.push(retryJob)
+	if !pushed {
+		s.logger.Info("Retry queue full, not retrying intent %s (error: %s)", intent.ID, errorType)
+		metrics.RetriesSkipped.WithLabelValues(strconv.Itoa(intent.DestinationChain), "retry_queue_full").Inc()
+		s.releaseIntent(ctx, intent)
+		return false
+	}
+	if evicted != nil {
+		s.dropEvictedRetry(ctx, *evicted)
+	}
+
+	// Update retry count metric
+	metrics.RetryCount.WithLabelValues(strconv.Itoa(intent.DestinationChain)).Inc()
+
+	s.logger.Info("Scheduling retry for intent %s in %v (error: %s)", intent.ID, backoff, errorType)
+	return true
+}
+
+// retryDeadline returns the time after which the retries of the intent are dropped, RetryMaxAge after the intent creation,
+// zero if there is no max age
+func (s *Fulfiller) retryDeadline(intent models.Intent) time.Time {
+	if s.config.RetryMaxAge <= 0 {
+		return time.Time{}
+	}
+	if intent.CreatedAt.IsZero() {
+		return s.now().Add(s.config.RetryMaxAge)
+	}
+	return intent.CreatedAt.Add(s.config.RetryMaxAge)
+}
+
+// retryFeeUSD returns the USD value of the intent fee used by the retry queue eviction, 0 if it can't be computed
+func (s *Fulfiller) retryFeeUSD(intent models.Intent) float64 {
+	if s.config.RetryEviction != config.RetryEvictionLowestFee {
+		return 0
+	}
+
+	var tokenPriceUSD float64
+	s.mu.Lock()
+	if chainClient, exists := s.chainClients[intent.DestinationChain]; exists {
+		tokenPriceUSD = chainClient.GetStoredTokenPriceUSD()
+	}
+	s.mu.Unlock()
+
+	feeUSD, err := intentFeeUSD(intent, tokenPriceUSD)
+	if err != nil {
+		return 0
+	}
+	return feeUSD
+}
+
+// dropEvictedRetry gives up on a queued retry job evicted to make room in the full retry queue
+func (s *Fulfiller) dropEvictedRetry(ctx context.Context, job models.RetryJob) {
+	s.logger.Info("Retry queue full, evicted retry of intent %s (policy: %s, fee: %.2f USD, error: %s)",
+		job.Intent.ID, s.config.RetryEviction, job.FeeUSD, job.ErrorType)
+	metrics.DroppedRetries.WithLabelValues(strconv.Itoa(job.Intent.DestinationChain)).Inc()
+	s.releaseIntent(ctx, job.Intent)
+	s.releaseExposure(job.Intent)
+}
+
+// dropExpiredRetry gives up on a retry job whose deadline is passed, the caller releases the exposure
+func (s *Fulfiller) dropExpiredRetry(ctx context.Context, job models.RetryJob) {
+	s.logger.Info("Retries expired for intent %s (created at %v), giving up (error: %s)",
+		job.Intent.ID, job.Intent.CreatedAt, job.ErrorType)
+	metrics.MaxRetriesReached.WithLabelValues(strconv.Itoa(job.Intent.DestinationChain), "expired").Inc()
+	s.releaseIntent(ctx, job.Intent)
+}
+
+// parseRetryTag returns the retry count and the error type of the previous attempt from the retry tag
+// of the intent ID (<id>_retry_<count>_error_<type>), 0 and an empty type for a first attempt
+func parseRetryTag(id string) (int, string) {
+	parts := strings.SplitN(id, "_retry_", 2)
+	if len(parts) < 2 {
+		return 0, ""
+	}
+	tag := strings.SplitN(parts[1], "_error_", 2)
+	retryCount, _ := strconv.Atoi(tag[0])
+	if len(tag) < 2 {
+		return retryCount, ""
+	}
+	return retryCount, tag[1]
+}
+
+// retryPolicy returns the retry policy of the error type, or the default policy if none is configured
+func (s *Fulfiller) retryPolicy(errorType string) config.RetryPolicy {
+	if policy, ok := s.config.RetryPolicies[errorType]; ok {
+		return policy
+	}
+	return config.DefaultRetryPolicy
+}
+
+// retryBackoff returns the exponential backoff of a retry (2^retry * backoff) capped at the policy max backoff
+func retryBackoff(policy config.RetryPolicy, retryCount int) time.Duration {
+	backoff := time.Duration(math.Pow(2, float64(retryCount))) * policy.Backoff
+	if backoff > policy.MaxBackoff || backoff <= 0 {
+		backoff = policy.MaxBackoff
+	}
+	return backoff
+}
+
+// fullJitter returns a random delay between 0 and the backoff, spreading out the retries of intents failing together
+func fullJitter(backoff time.Duration) time.Duration {
+	if backoff <= 0 {
+		return backoff
+	}
+	return time.Duration(rand.Int63n(int64(backoff) + 1))
+}
+
+// recordFulfilled adds the intent to the fulfilled intent log
+func (s *Fulfiller) recordFulfilled(intent models.Intent) {
+	if err := s.fulfilled.Record(baseIntentID(intent.ID)); err != nil {
+		s.logger.ErrorWithChain(intent.DestinationChain, "Failed to record fulfilled intent %s: %v", intent.ID, err)
+	}
+}
+
+// syncNonces realigns the nonces of a chain after a nonce error
+func (s *Fulfiller) syncNonces(ctx context.Context, chainID int) {
+	s.mu.Lock()
+	chainClient, exists := s.chainClients[chainID]
+	s.mu.Unlock()
+	if !exists {
+		return
+	}
+
+	syncCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := chainClient.SyncNonces(syncCtx); err != nil {
+		s.logger.ErrorWithChain(chainID, "Failed to sync nonces: %v", err)
+	}
+}
+
+// fulfillWithTimeout fulfills the intent, aborting if it doesn't complete within the configured timeout
+func (s *Fulfiller) fulfillWithTimeout(ctx context.Context, intent models.Intent) (*models.FulfillmentResult, error) {
+	if s.config.FulfillTimeout <= 0 {
+		return s.fulfillIntent(ctx, intent)
+	}
+
+	fulfillCtx, cancel := context.WithTimeout(ctx, s.config.FulfillTimeout)
+	defer cancel()
+
+	result, err := s.fulfillIntent(fulfillCtx, intent)
+	if err != nil && !errors.Is(err, errFulfillTimeout) && errors.Is(fulfillCtx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%w after %v: %v", errFulfillTimeout, s.config.FulfillTimeout, err)
+	}
+	return result, err
 }
 
 // shouldRetryError classifies errors to determine if a retry should be attempted
 // Returns (shouldRetry, errorType)
 func shouldRetryError(err error) (bool, string) {
+	// Fulfillment deadline reached - retry replaces the stuck transaction
+	if errors.Is(err, errFulfillTimeout) {
+		return true, "timeout"
+	}
+
+	// Fulfillment reorged out after its confirmations - retry once the node state settles
+	if errors.Is(err, errTxReorged) {
+		return true, "node_state_error"
+	}
+
+	// Errors of go-ethereum wrapped through the fulfillment, errors returned by the node are matched on their message
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return true, "network_error"
+	case errors.Is(err, ethereum.NotFound):
+		return true, "node_state_error"
+	}
+
 	errStr := err.Error()
 
 	// Check for "already processed" errors - no retry needed
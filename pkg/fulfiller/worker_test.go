@@ -2,14 +2,27 @@ package fulfiller
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/speedrun-hq/speedrunner/pkg/circuitbreaker"
+	"github.com/speedrun-hq/speedrunner/pkg/clock"
+	"github.com/speedrun-hq/speedrunner/pkg/config"
+	"github.com/speedrun-hq/speedrunner/pkg/logger"
+	"github.com/speedrun-hq/speedrunner/pkg/metrics"
 	"github.com/speedrun-hq/speedrunner/pkg/models"
+	"github.com/speedrun-hq/speedrunner/pkg/srunclient"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // MockJobQueue is a test implementation of a job queue
@@ -247,3 +260,355 @@ func TestWorkerHandlesErrors(t *testing.T) {
 	assert.Equal(t, testIntent.ID, mockService.failedIntents[0].ID,
 		"The correct intent should be marked as failed")
 }
+
+// TestShouldRetryErrorTimeout tests that fulfillment timeouts are retried with the timeout error type
+func TestShouldRetryErrorTimeout(t *testing.T) {
+	err := fmt.Errorf("%w: transaction 0x01 not mined", errFulfillTimeout)
+	shouldRetry, errorType := shouldRetryError(err)
+	assert.True(t, shouldRetry)
+	assert.Equal(t, "timeout", errorType)
+
+	// a plain deadline error is still a network error
+	shouldRetry, errorType = shouldRetryError(context.DeadlineExceeded)
+	assert.True(t, shouldRetry)
+	assert.Equal(t, "network_error", errorType)
+}
+
+// TestShouldRetryErrorWrapped tests go-ethereum errors are classified through the wrapping of the fulfillment
+func TestShouldRetryErrorWrapped(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		shouldRetry bool
+		errorType   string
+	}{
+		{"nonce too low", errors.New("nonce too low"), true, "nonce_error"},
+		{"replacement underpriced", errors.New("replacement transaction underpriced"), true, "nonce_error"},
+		{"insufficient funds for gas", errors.New("insufficient funds for gas * price + value"), true, "gas_error"},
+		{"already fulfilled", errors.New("execution reverted: Intent already fulfilled"), false, "already_processed"},
+		{"receipt not found", ethereum.NotFound, true, "node_state_error"},
+		{"deadline", context.DeadlineExceeded, true, "network_error"},
+		{"reorged", errTxReorged, true, "node_state_error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fmt.Errorf("failed to fulfill intent on %d: %w", 8453, tt.err)
+			require.ErrorIs(t, err, tt.err)
+
+			shouldRetry, errorType := shouldRetryError(err)
+			assert.Equal(t, tt.shouldRetry, shouldRetry)
+			assert.Equal(t, tt.errorType, errorType)
+		})
+	}
+}
+
+// TestRetryBackoff tests the exponential backoff is capped by the policy
+func TestRetryBackoff(t *testing.T) {
+	policy := config.RetryPolicy{MaxRetries: 5, Backoff: 5 * time.Second, MaxBackoff: time.Minute}
+	assert.Equal(t, 5*time.Second, retryBackoff(policy, 0))
+	assert.Equal(t, 10*time.Second, retryBackoff(policy, 1))
+	assert.Equal(t, 40*time.Second, retryBackoff(policy, 3))
+	assert.Equal(t, time.Minute, retryBackoff(policy, 4))
+	assert.Equal(t, time.Minute, retryBackoff(policy, 100))
+}
+
+// TestFullJitter tests the jittered backoff stays within the backoff window and is spread out
+func TestFullJitter(t *testing.T) {
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 100; i++ {
+		delay := fullJitter(time.Minute)
+		assert.GreaterOrEqual(t, delay, time.Duration(0))
+		assert.LessOrEqual(t, delay, time.Minute)
+		seen[delay] = true
+	}
+	assert.Greater(t, len(seen), 1)
+	assert.Zero(t, fullJitter(0))
+}
+
+// TestScheduleRetryPolicy tests retries are scheduled with the policy of the error type
+func TestScheduleRetryPolicy(t *testing.T) {
+	log := logger.NewMemoryLogger()
+	now := clock.NewFake(time.Now())
+	s := &Fulfiller{
+		config: &config.Config{
+			RetryPolicies: map[string]config.RetryPolicy{
+				"network_error": {MaxRetries: 2, Backoff: time.Second, MaxBackoff: time.Minute},
+			},
+		},
+		retryJobs: newRetryQueue(0),
+		clock:     now,
+		logger:    log,
+	}
+	intent := models.Intent{ID: "intent1", DestinationChain: 42161}
+
+	s.scheduleRetry(context.Background(), intent, "network_error")
+	require.Equal(t, 1, s.retryJobs.len())
+	job := popRetryJob(t, s.retryJobs)
+	assert.Equal(t, 1, job.RetryCount)
+	assert.Equal(t, "network_error", job.ErrorType)
+	assert.Equal(t, "intent1_retry_1_error_network_error", job.Intent.ID)
+	assert.Equal(t, now.Now().Add(time.Second), job.NextAttempt)
+
+	// the retry count is read from the tagged ID
+	s.scheduleRetry(context.Background(), job.Intent, "network_error")
+	require.Equal(t, 1, s.retryJobs.len())
+	job = popRetryJob(t, s.retryJobs)
+	assert.Equal(t, 2, job.RetryCount)
+	assert.Equal(t, "intent1_retry_2_error_network_error", job.Intent.ID)
+	assert.Equal(t, now.Now().Add(2*time.Second), job.NextAttempt)
+
+	assert.True(t, log.Contains(logger.InfoLevel, "Scheduling retry for intent intent1_retry_1_error_network_error in 2s"))
+
+	// max retries of the policy reached
+	s.scheduleRetry(context.Background(), job.Intent, "network_error")
+	assert.Zero(t, s.retryJobs.len())
+	assert.True(t, log.Contains(logger.InfoLevel, "Max retries reached for intent intent1_retry_2_error_network_error, giving up (error: network_error)"))
+
+	// error types without a policy use the default policy
+	s.scheduleRetry(context.Background(), intent, "unknown_error")
+	require.Equal(t, 1, s.retryJobs.len())
+	job = popRetryJob(t, s.retryJobs)
+	assert.Equal(t, now.Now().Add(config.DefaultRetryPolicy.Backoff), job.NextAttempt)
+
+	// no retry is scheduled when the retry queue is full
+	s.retryJobs = newRetryQueue(1)
+	assert.True(t, s.scheduleRetry(context.Background(), intent, "network_error"))
+	assert.False(t, s.scheduleRetry(context.Background(), models.Intent{ID: "intent2", DestinationChain: 42161}, "network_error"))
+	assert.Equal(t, 1, s.retryJobs.len())
+	assert.True(t, log.Contains(logger.InfoLevel, "Retry queue full, not retrying intent intent2"))
+}
+
+// TestRetryMaxAge verifies retries of intents older than RetryMaxAge are dropped
+func TestRetryMaxAge(t *testing.T) {
+	log := logger.NewMemoryLogger()
+	now := clock.NewFake(time.Now())
+	s := &Fulfiller{
+		config: &config.Config{
+			RetryPolicies: map[string]config.RetryPolicy{
+				"gas_error": {MaxRetries: 3, Backoff: time.Minute, MaxBackoff: time.Minute},
+			},
+			RetryMaxAge: 30 * time.Minute,
+		},
+		retryJobs: newRetryQueue(0),
+		exposure:  newExposureTracker(0),
+		clock:     now,
+		logger:    log,
+	}
+	expiredBefore := testutil.ToFloat64(metrics.MaxRetriesReached.WithLabelValues("42161", "expired"))
+
+	// the deadline is set from the intent creation
+	createdAt := now.Now().Add(-time.Minute)
+	s.scheduleRetry(context.Background(), models.Intent{ID: "intent1", DestinationChain: 42161, CreatedAt: createdAt}, "gas_error")
+	require.Equal(t, 1, s.retryJobs.len())
+	job := popRetryJob(t, s.retryJobs)
+	assert.Equal(t, createdAt.Add(30*time.Minute), job.Deadline)
+
+	// a job not due yet is put back until its deadline
+	s.retryJobs.push(job)
+	s.processRetryJobs(context.Background())
+	require.Equal(t, 1, s.retryJobs.len())
+
+	// a job past its deadline is dropped even if it is not due
+	job = popRetryJob(t, s.retryJobs)
+	job.NextAttempt = job.Deadline.Add(time.Minute)
+	s.retryJobs.push(job)
+	now.Set(job.Deadline)
+	s.processRetryJobs(context.Background())
+	assert.Zero(t, s.retryJobs.len())
+	assert.True(t, log.Contains(logger.InfoLevel, "Retries expired for intent intent1_retry_1_error_gas_error"))
+
+	// no retry is scheduled for an intent already too old
+	s.scheduleRetry(context.Background(), models.Intent{ID: "intent2", DestinationChain: 42161, CreatedAt: now.Now().Add(-time.Hour)}, "gas_error")
+	assert.Zero(t, s.retryJobs.len())
+
+	assert.Equal(t, expiredBefore+2, testutil.ToFloat64(metrics.MaxRetriesReached.WithLabelValues("42161", "expired")))
+}
+
+// TestParseRetryTag tests the retry count and error type are read from tagged intent IDs
+func TestParseRetryTag(t *testing.T) {
+	count, errorType := parseRetryTag("0xabc")
+	assert.Equal(t, 0, count)
+	assert.Equal(t, "", errorType)
+
+	count, errorType = parseRetryTag("0xabc_retry_2")
+	assert.Equal(t, 2, count)
+	assert.Equal(t, "", errorType)
+
+	count, errorType = parseRetryTag("0xabc_retry_3_error_node_state_error")
+	assert.Equal(t, 3, count)
+	assert.Equal(t, "node_state_error", errorType)
+}
+
+// TestVerifyIntentStatus verifies only intents still pending in the API are fulfilled
+func TestVerifyIntentStatus(t *testing.T) {
+	statuses := map[string]string{"0x01": "pending", "0x02": "fulfilled", "0x03": "PENDING"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Path[len("/api/v1/intents/"):]
+		status, ok := statuses[id]
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"id": %q, "status": %q}`, id, status)
+	}))
+	defer server.Close()
+
+	memLogger := logger.NewMemoryLogger()
+	s := &Fulfiller{
+		config:     &config.Config{VerifyIntentStatus: true},
+		srunClient: srunclient.New(server.URL, memLogger),
+		logger:     memLogger,
+	}
+	ctx := context.Background()
+
+	assert.True(t, s.verifyIntentStatus(ctx, models.Intent{ID: "0x01"}))
+	assert.True(t, s.verifyIntentStatus(ctx, models.Intent{ID: "0x03"}))
+	// retries are checked with the ID of the intent
+	assert.False(t, s.verifyIntentStatus(ctx, models.Intent{ID: "0x02_retry_1"}))
+	assert.True(t, memLogger.Contains(logger.InfoLevel, "Skipping intent 0x02_retry_1: status is fulfilled"))
+
+	// the fulfillment proceeds if the status can't be fetched
+	assert.True(t, s.verifyIntentStatus(ctx, models.Intent{ID: "0x04"}))
+	assert.True(t, memLogger.Contains(logger.ErrorLevel, "Failed to verify status of intent 0x04"))
+
+	s.config.VerifyIntentStatus = false
+	assert.True(t, s.verifyIntentStatus(ctx, models.Intent{ID: "0x02"}))
+}
+
+// TestAcquireCooldownSlot tests the concurrency of a chain is limited only while its circuit breaker is in cooldown
+func TestAcquireCooldownSlot(t *testing.T) {
+	log := logger.NewMemoryLogger()
+	breaker := circuitbreaker.NewCircuitBreaker(137, true, 1, time.Minute, time.Minute, 0, time.Minute, log)
+	fake := clock.NewFake(time.Now())
+	breaker.SetClock(fake)
+	s := &Fulfiller{
+		circuitBreakers: map[int]*circuitbreaker.CircuitBreaker{137: breaker},
+		cooldownSlots:   map[int]chan struct{}{137: make(chan struct{}, 1)},
+		logger:          log,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// not limited before the circuit breaker trips
+	release1, err := s.acquireCooldownSlot(ctx, 137)
+	require.NoError(t, err)
+	release2, err := s.acquireCooldownSlot(ctx, 137)
+	require.NoError(t, err)
+	release1()
+	release2()
+
+	breaker.RecordFailure()
+	fake.Advance(time.Minute + time.Second)
+
+	release, err := s.acquireCooldownSlot(context.Background(), 137)
+	require.NoError(t, err)
+	_, err = s.acquireCooldownSlot(ctx, 137)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	release()
+
+	// full concurrency after the cooldown
+	fake.Advance(time.Minute)
+	release1, err = s.acquireCooldownSlot(context.Background(), 137)
+	require.NoError(t, err)
+	release2, err = s.acquireCooldownSlot(context.Background(), 137)
+	require.NoError(t, err)
+	release1()
+	release2()
+}
+
+// TestHandleResultTrippedBreakerReleasesClaim verifies the claim of an intent is released when the tripped circuit
+// breaker skips its retry, so that other fulfillers can pick it up
+func TestHandleResultTrippedBreakerReleasesClaim(t *testing.T) {
+	var mu sync.Mutex
+	var released []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			mu.Lock()
+			released = append(released, r.URL.Path)
+			mu.Unlock()
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	log := logger.NewMemoryLogger()
+	s := &Fulfiller{
+		config: &config.Config{
+			IntentClaiming:   true,
+			FulfillerAddress: "0x1111111111111111111111111111111111111111",
+			RetryPolicies: map[string]config.RetryPolicy{
+				"network_error": {MaxRetries: 3, Backoff: time.Second, MaxBackoff: time.Minute},
+			},
+		},
+		srunClient: srunclient.New(server.URL, log),
+		circuitBreakers: map[int]*circuitbreaker.CircuitBreaker{
+			42161: circuitbreaker.NewCircuitBreaker(42161, true, 1, time.Minute, time.Minute, 0, 0, log),
+		},
+		retryJobs: newRetryQueue(0),
+		exposure:  newExposureTracker(0),
+		clock:     clock.Real{},
+		logger:    log,
+	}
+	intent := models.Intent{ID: "0xabc", DestinationChain: 42161}
+
+	s.wg.Add(1)
+	s.handleResult(context.Background(), 0, intent, nil, errors.New("connection refused"))
+
+	assert.True(t, log.Contains(logger.InfoLevel, "Skipping retry for intent 0xabc due to tripped circuit breaker"))
+	assert.Equal(t, 0, s.retryJobs.len())
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"/api/v1/intents/0xabc/claim"}, released)
+}
+
+// TestClaimIntentSharedAddress verifies two instances sharing the fulfiller address don't both win the claim
+// of an intent, and that only the instance holding the claim releases it
+func TestClaimIntentSharedAddress(t *testing.T) {
+	var mu sync.Mutex
+	claims := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Claimer string `json:"claimer"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		mu.Lock()
+		defer mu.Unlock()
+		holder, claimed := claims[r.URL.Path]
+		switch {
+		case claimed && holder != body.Claimer:
+			w.WriteHeader(http.StatusConflict)
+		case r.Method == http.MethodDelete:
+			delete(claims, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			claims[r.URL.Path] = body.Claimer
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	newInstance := func() *Fulfiller {
+		return &Fulfiller{
+			config: &config.Config{
+				IntentClaiming:   true,
+				FulfillerAddress: config.DefaultFulfillerAddress,
+			},
+			srunClient: srunclient.New(server.URL, &logger.EmptyLogger{}),
+			claimerID:  newClaimerID(),
+			logger:     &logger.EmptyLogger{},
+		}
+	}
+	first, second := newInstance(), newInstance()
+	require.NotEqual(t, first.claimerID, second.claimerID)
+	intent := models.Intent{ID: "0xabc", DestinationChain: 42161}
+
+	assert.True(t, first.claimIntent(context.Background(), intent))
+	assert.False(t, second.claimIntent(context.Background(), intent))
+
+	second.releaseIntent(context.Background(), intent)
+	assert.False(t, second.claimIntent(context.Background(), intent))
+
+	first.releaseIntent(context.Background(), intent)
+	assert.True(t, second.claimIntent(context.Background(), intent))
+}
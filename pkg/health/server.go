@@ -3,15 +3,19 @@ package health
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
+	"net"
 	"net/http"
+	"net/http/pprof"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/speedrun-hq/speedrunner/pkg/chainclient"
 	"github.com/speedrun-hq/speedrunner/pkg/chains"
@@ -24,21 +28,46 @@ import (
 
 // Server represents a health check HTTP server
 type Server struct {
+	host            string
 	port            string
 	chains          map[int]*chainclient.Client
 	circuitBreakers map[int]*circuitbreaker.CircuitBreaker
 	metricsAPIKey   string
+	httpServer      *http.Server
 	logger          logger.Logger
+
+	// results of the RPC checks of the readiness endpoint by chain, cached for rpcCheckCacheTTL
+	rpcChecksMu sync.Mutex
+	rpcChecks   map[int]rpcCheck
+}
+
+// rpcCheck is the cached result of a readiness RPC check of a chain
+type rpcCheck struct {
+	checkedAt time.Time
+	err       error
 }
 
+const (
+	// readHeaderTimeout is the maximum duration to read request headers
+	readHeaderTimeout = 10 * time.Second
+
+	// rpcCheckTimeout is the maximum duration of the readiness RPC check of a chain
+	rpcCheckTimeout = 3 * time.Second
+
+	// rpcCheckCacheTTL is the duration the readiness RPC check of a chain is cached for, to keep probes lightweight
+	rpcCheckCacheTTL = 5 * time.Second
+)
+
 // NewServer creates a new health check server
 func NewServer(
+	host,
 	port string,
 	chains map[int]*chainclient.Client,
 	circuitBreakers map[int]*circuitbreaker.CircuitBreaker,
 	logger logger.Logger,
 ) *Server {
 	return &Server{
+		host:            host,
 		port:            port,
 		chains:          chains,
 		circuitBreakers: circuitBreakers,
@@ -47,70 +76,144 @@ func NewServer(
 	}
 }
 
-// Start starts the health check server
-func (s *Server) Start() {
-	// Health check endpoint
-	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+// ErrorResponse is the JSON body returned by the server on errors
+type ErrorResponse struct {
+	Error string `json:"error"`
+	Code  int    `json:"code"`
+}
+
+// ReadinessResponse is the JSON body returned by the readiness endpoint when not ready,
+// with the reason each chain is not ready or "ok"
+type ReadinessResponse struct {
+	ErrorResponse
+	Chains map[string]string `json:"chains"`
+}
+
+// writeJSON writes v as JSON with the status code, the body is encoded before the header is sent
+// so that encoding failures can still be reported with an error status
+func (s *Server) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		s.logger.Error("Error encoding JSON response: %v", err)
+		s.writeError(w, http.StatusInternalServerError, "failed to encode response")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(append(body, '\n'))
+}
+
+// writeError writes a JSON error body with the status code
+func (s *Server) writeError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(ErrorResponse{
+		Error: fmt.Sprintf(format, args...),
+		Code:  status,
+	})
+}
+
+// Start binds the listen address and serves the health check server in the background
+// It returns an error if the address can't be bound, e.g. when the port is already in use
+func (s *Server) Start() error {
+	addr := net.JoinHostPort(s.host, s.port)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %v", addr, err)
+	}
+
+	s.httpServer = &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	s.logger.Notice("Starting health and metrics server on %s", listener.Addr().String())
+	go func() {
+		if err := s.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("Health server error: %v", err)
+		}
+	}()
+	return nil
+}
+
+// Shutdown gracefully stops the server, waiting for active requests until the context is done
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.httpServer == nil {
+		return nil
+	}
+	return s.httpServer.Shutdown(ctx)
+}
+
+// Handler returns the HTTP handler serving the health, status, admin and metrics endpoints
+func (s *Server) Handler() http.Handler {
+	// Use a dedicated mux, net/http/pprof registers unauthenticated handlers on the default one
+	mux := http.NewServeMux()
+
+	// Liveness check, the process is up and serving
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("OK"))
 	})
 
-	// Readiness check
-	http.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
-		// Check if all chain clients are connected
+	// Readiness check, every chain RPC responds and the fees were updated at least once
+	mux.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
+		ready := true
+		chainStatus := make(map[string]string, len(s.chains))
 		for chainID, chainConfig := range s.chains {
-			if chainConfig.Client == nil {
-				w.WriteHeader(http.StatusServiceUnavailable)
-				_, _ = fmt.Fprintf(w, "Chain %d client not connected", chainID)
-				return
+			status := "ok"
+			if err := s.checkChainReady(r.Context(), chainID, chainConfig); err != nil {
+				ready = false
+				status = err.Error()
 			}
+			chainStatus[fmt.Sprintf("chain_%d", chainID)] = status
+		}
+
+		if !ready {
+			s.writeJSON(w, http.StatusServiceUnavailable, ReadinessResponse{
+				ErrorResponse: ErrorResponse{Error: "not ready", Code: http.StatusServiceUnavailable},
+				Chains:        chainStatus,
+			})
+			return
 		}
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("Ready"))
 	})
 
 	// Chain status endpoint
-	http.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
 		status := make(map[string]interface{})
 
 		for chainID, chainConfig := range s.chains {
 			status[fmt.Sprintf("chain_%d", chainID)] = s.getChainStatus(r.Context(), chainID, chainConfig)
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(status); err != nil {
-			s.logger.Error("Error encoding status JSON: %v", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			_, _ = w.Write([]byte("Failed to encode status"))
-		}
+		s.writeJSON(w, http.StatusOK, status)
 	})
 
 	// Circuit breaker admin control endpoint
-	http.HandleFunc("/circuit/reset", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/circuit/reset", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
-			w.WriteHeader(http.StatusMethodNotAllowed)
-			_, _ = w.Write([]byte("Method not allowed"))
+			w.Header().Set("Allow", http.MethodPost)
+			s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
 			return
 		}
 
 		chainIDStr := r.URL.Query().Get("chain")
 		if chainIDStr == "" {
-			w.WriteHeader(http.StatusBadRequest)
-			_, _ = w.Write([]byte("Missing chain parameter"))
+			s.writeError(w, http.StatusBadRequest, "missing chain parameter")
 			return
 		}
 
 		chainID, err := strconv.Atoi(chainIDStr)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			_, _ = w.Write([]byte("Invalid chain ID"))
+			s.writeError(w, http.StatusBadRequest, "invalid chain ID: %s", chainIDStr)
 			return
 		}
 
 		cb, ok := s.circuitBreakers[chainID]
 		if !ok {
-			w.WriteHeader(http.StatusNotFound)
-			_, _ = fmt.Fprintf(w, "No circuit breaker for chain %d", chainID)
+			s.writeError(w, http.StatusNotFound, "no circuit breaker for chain %d", chainID)
 			return
 		}
 
@@ -119,13 +222,134 @@ func (s *Server) Start() {
 		_, _ = fmt.Fprintf(w, "Circuit breaker for chain %d reset", chainID)
 	})
 
+	// Min fee admin control endpoint, updates the min fee of a chain until the next restart
+	mux.Handle("/admin/minfee", s.adminAuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chainID, chainClient, minFee, ok := s.parseAdminChainValue(w, r)
+		if !ok {
+			return
+		}
+		if chainClient.MinFeeUSD > 0 {
+			s.writeError(w, http.StatusConflict, "chain %d uses a min fee of %.2f USD which takes precedence", chainID, chainClient.MinFeeUSD)
+			return
+		}
+
+		previous := chainClient.GetMinFee()
+		chainClient.SetMinFee(minFee)
+		s.logger.NoticeWithChain(chainID, "Min fee updated from %v to %s", previous, minFee.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(w, "Min fee for chain %d set to %s", chainID, minFee.String())
+	})))
+
+	// Max gas price admin control endpoint, updates the gas price cap of a chain until the next restart
+	mux.Handle("/admin/maxgas", s.adminAuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chainID, chainClient, maxGasPrice, ok := s.parseAdminChainValue(w, r)
+		if !ok {
+			return
+		}
+		if maxGasPrice.Sign() == 0 {
+			s.writeError(w, http.StatusBadRequest, "max gas price must be greater than 0")
+			return
+		}
+
+		previous := chainClient.GetMaxGasPrice()
+		chainClient.SetMaxGasPrice(maxGasPrice)
+		s.logger.NoticeWithChain(chainID, "Max gas price updated from %v to %s wei", previous, maxGasPrice.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(w, "Max gas price for chain %d set to %s wei", chainID, maxGasPrice.String())
+	})))
+
 	// Expose Prometheus metrics with API key authentication
-	http.Handle("/metrics", s.metricsAuthMiddleware(promhttp.Handler()))
+	mux.Handle("/metrics", s.metricsAuthMiddleware(promhttp.Handler()))
+
+	// Expose profiling endpoints with API key authentication
+	mux.Handle("/debug/pprof/", s.metricsAuthMiddleware(http.HandlerFunc(pprof.Index)))
+	mux.Handle("/debug/pprof/cmdline", s.metricsAuthMiddleware(http.HandlerFunc(pprof.Cmdline)))
+	mux.Handle("/debug/pprof/profile", s.metricsAuthMiddleware(http.HandlerFunc(pprof.Profile)))
+	mux.Handle("/debug/pprof/symbol", s.metricsAuthMiddleware(http.HandlerFunc(pprof.Symbol)))
+	mux.Handle("/debug/pprof/trace", s.metricsAuthMiddleware(http.HandlerFunc(pprof.Trace)))
+
+	return mux
+}
+
+// parseAdminChainValue reads the chain and value parameters of a POST admin request,
+// it writes the error response and returns false if the request is invalid or the chain unknown
+func (s *Server) parseAdminChainValue(w http.ResponseWriter, r *http.Request) (int, *chainclient.Client, *big.Int, bool) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return 0, nil, nil, false
+	}
+
+	chainIDStr := r.URL.Query().Get("chain")
+	chainID, err := strconv.Atoi(chainIDStr)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid chain ID: %q", chainIDStr)
+		return 0, nil, nil, false
+	}
+
+	valueStr := r.URL.Query().Get("value")
+	value, ok := new(big.Int).SetString(valueStr, 10)
+	if !ok || value.Sign() < 0 {
+		s.writeError(w, http.StatusBadRequest, "invalid value: %q, must be a non-negative integer", valueStr)
+		return 0, nil, nil, false
+	}
 
-	s.logger.Notice("Starting health and metrics server on port %s", s.port)
-	if err := http.ListenAndServe(":"+s.port, nil); err != nil {
-		s.logger.Error("Health server error: %v", err)
+	chainClient, ok := s.chains[chainID]
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "unknown chain %d", chainID)
+		return 0, nil, nil, false
 	}
+	return chainID, chainClient, value, true
+}
+
+// checkChainReady returns why the chain is not ready to fulfill intents, or nil if it is ready
+func (s *Server) checkChainReady(ctx context.Context, chainID int, chainConfig *chainclient.Client) error {
+	if chainConfig.RPCClient() == nil {
+		return fmt.Errorf("client not connected")
+	}
+	if chainConfig.GetFeeUpdatedAt().IsZero() {
+		return fmt.Errorf("no successful fee update yet")
+	}
+	return s.checkRPC(ctx, chainID, chainConfig)
+}
+
+// checkRPC verifies the chain RPC responds by fetching the latest block number, the result is cached for rpcCheckCacheTTL
+func (s *Server) checkRPC(ctx context.Context, chainID int, chainConfig *chainclient.Client) error {
+	s.rpcChecksMu.Lock()
+	defer s.rpcChecksMu.Unlock()
+
+	if check, ok := s.rpcChecks[chainID]; ok && time.Since(check.checkedAt) < rpcCheckCacheTTL {
+		return check.err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, rpcCheckTimeout)
+	defer cancel()
+
+	var checkErr error
+	if _, err := chainConfig.GetLatestBlockNumber(ctx); err != nil {
+		checkErr = fmt.Errorf("RPC not responding: %v", err)
+	}
+
+	if s.rpcChecks == nil {
+		s.rpcChecks = make(map[int]rpcCheck)
+	}
+	s.rpcChecks[chainID] = rpcCheck{checkedAt: time.Now(), err: checkErr}
+	return checkErr
+}
+
+// adminAuthMiddleware is a middleware that checks for a valid API key, the admin endpoints changing
+// the fulfiller behavior are disabled if no API key is configured
+func (s *Server) adminAuthMiddleware(next http.Handler) http.Handler {
+	authenticated := s.metricsAuthMiddleware(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.metricsAPIKey == "" {
+			s.writeError(w, http.StatusForbidden, "admin endpoints are disabled, METRICS_API_KEY is not set")
+			return
+		}
+		authenticated.ServeHTTP(w, r)
+	})
 }
 
 // metricsAuthMiddleware is a middleware that checks for a valid API key
@@ -140,20 +364,20 @@ func (s *Server) metricsAuthMiddleware(next http.Handler) http.Handler {
 		// Get API key from Authorization header
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
-			http.Error(w, "Missing Authorization header", http.StatusUnauthorized)
+			s.writeError(w, http.StatusUnauthorized, "missing Authorization header")
 			return
 		}
 
 		// Check if the header has the correct format
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			http.Error(w, "Invalid Authorization header format", http.StatusUnauthorized)
+			s.writeError(w, http.StatusUnauthorized, "invalid Authorization header format")
 			return
 		}
 
 		// Validate API key
 		if parts[1] != s.metricsAPIKey {
-			http.Error(w, "Invalid API key", http.StatusUnauthorized)
+			s.writeError(w, http.StatusUnauthorized, "invalid API key")
 			return
 		}
 
@@ -171,9 +395,20 @@ func (s *Server) getTokenBalances(ctx context.Context, chainID int, chainConfig
 		return tokenBalances
 	}
 
+	if chainConfig.RPCClient() == nil {
+		s.logger.Info("Warning: Client of chain %s not connected", chainName)
+		return tokenBalances
+	}
+	backend := chainConfig.ContractBackend()
+	opts, err := chainConfig.TransactOpts()
+	if err != nil {
+		s.logger.Info("Warning: Failed to get fulfiller address for chain %s: %v", chainName, err)
+		return tokenBalances
+	}
+
 	// Get USDC balance
 	if usdcAddr := chains.GetTokenAddress(chainID, chains.TokenTypeUSDC); usdcAddr != "" {
-		if balance, err := s.getTokenBalance(ctx, chainConfig.Client, common.HexToAddress(usdcAddr), chainConfig.Auth.From); err == nil {
+		if balance, err := s.getTokenBalance(ctx, chainID, backend, common.HexToAddress(usdcAddr), opts.From); err == nil {
 			tokenBalances["USDC"] = balance.String()
 		} else {
 			s.logger.Info("Warning: Failed to get USDC balance for chain %s: %v", chainName, err)
@@ -184,7 +419,7 @@ func (s *Server) getTokenBalances(ctx context.Context, chainID int, chainConfig
 
 	// Get USDT balance
 	if usdtAddr := chains.GetTokenAddress(chainID, chains.TokenTypeUSDT); usdtAddr != "" {
-		if balance, err := s.getTokenBalance(ctx, chainConfig.Client, common.HexToAddress(usdtAddr), chainConfig.Auth.From); err == nil {
+		if balance, err := s.getTokenBalance(ctx, chainID, backend, common.HexToAddress(usdtAddr), opts.From); err == nil {
 			tokenBalances["USDT"] = balance.String()
 		} else {
 			s.logger.Info("Warning: Failed to get USDT balance for chain %s: %v", chainName, err)
@@ -204,14 +439,30 @@ func (s *Server) getChainStatus(ctx context.Context, chainID int, config *chainc
 	}
 
 	chainStatus := map[string]interface{}{
-		"rpc_url":        config.RPCURL,
-		"intent_address": config.IntentAddress,
-		"connected":      config.Client != nil,
-		"circuit":        circuitStatus,
+		"rpc_url":                  config.RPCURL,
+		"intent_address":           config.IntentAddress,
+		"intent_addresses":         config.IntentAddresses,
+		"connected":                config.RPCClient() != nil,
+		"circuit":                  circuitStatus,
+		"gas_source":               config.GasSource,
+		"max_pending_transactions": config.MaxPendingTx,
+		"min_fee_usd":              config.MinFeeUSD,
+	}
+	if minFee := config.GetMinFee(); minFee != nil {
+		chainStatus["min_fee"] = minFee.String()
+	}
+	if maxGasPrice := config.GetMaxGasPrice(); maxGasPrice != nil {
+		chainStatus["max_gas_price"] = maxGasPrice.String()
+	}
+	if config.MinTip != nil {
+		chainStatus["min_tip"] = config.MinTip.String()
+	}
+	if effectiveTip := config.GetEffectiveTip(); effectiveTip != nil {
+		chainStatus["effective_tip"] = effectiveTip.String()
 	}
 
 	// Get latest block number if connected
-	if config.Client != nil {
+	if config.RPCClient() != nil {
 		blockNumber, err := config.GetLatestBlockNumber(ctx)
 		if err == nil {
 			chainStatus["latest_block"] = blockNumber
@@ -219,6 +470,13 @@ func (s *Server) getChainStatus(ctx context.Context, chainID int, config *chainc
 			s.logger.InfoWithChain(chainID, "Warning: Failed to get latest block for chain %d: %v", err)
 		}
 
+		// Get transactions sent but not mined yet
+		if pending, err := config.PendingTxCount(ctx); err == nil {
+			chainStatus["pending_transactions"] = pending
+		} else {
+			s.logger.InfoWithChain(chainID, "Warning: Failed to get pending transactions: %v", err)
+		}
+
 		// Get token balances
 		if tokenBalances := s.getTokenBalances(ctx, chainID, config); len(tokenBalances) > 0 {
 			chainStatus["token_balances"] = tokenBalances
@@ -229,8 +487,13 @@ func (s *Server) getChainStatus(ctx context.Context, chainID int, config *chainc
 }
 
 // getTokenBalance retrieves the token balance for a given address
-func (s *Server) getTokenBalance(ctx context.Context, client *ethclient.Client, tokenAddress, ownerAddress common.Address) (*big.Int, error) {
-	token, err := contracts.NewERC20(tokenAddress, client)
+func (s *Server) getTokenBalance(
+	ctx context.Context,
+	chainID int,
+	backend bind.ContractBackend,
+	tokenAddress, ownerAddress common.Address,
+) (*big.Int, error) {
+	token, err := contracts.NewERC20(tokenAddress, backend)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create token contract: %v", err)
 	}
@@ -266,16 +529,9 @@ func (s *Server) getTokenBalance(ctx context.Context, client *ethclient.Client,
 	balanceFloat.Quo(balanceFloat, decimalsMultiplier)
 	balanceFloat64, _ := balanceFloat.Float64()
 
-	// Get chain ID
-	chainID, err := client.ChainID(ctx)
-	if err != nil {
-		s.logger.Info("Warning: Failed to get chain ID: %v", err)
-		return balance, nil // Return balance even if we can't get chain ID
-	}
-
 	// Update Prometheus metric
 	metrics.TokenBalance.WithLabelValues(
-		chains.GetChainName(int(chainID.Int64())),
+		chains.GetChainName(chainID),
 		symbol,
 	).Set(balanceFloat64)
 
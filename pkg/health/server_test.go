@@ -0,0 +1,250 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/speedrun-hq/speedrunner/pkg/chainclient"
+	"github.com/speedrun-hq/speedrunner/pkg/circuitbreaker"
+	"github.com/speedrun-hq/speedrunner/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer() *Server {
+	return &Server{
+		chains:          map[int]*chainclient.Client{},
+		circuitBreakers: map[int]*circuitbreaker.CircuitBreaker{},
+		metricsAPIKey:   "secret",
+		logger:          &logger.EmptyLogger{},
+	}
+}
+
+// TestErrorResponses verifies errors are returned as JSON with a consistent shape and status code
+func TestErrorResponses(t *testing.T) {
+	handler := newTestServer().Handler()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{"method not allowed", http.MethodGet, "/circuit/reset?chain=1", http.StatusMethodNotAllowed},
+		{"missing chain", http.MethodPost, "/circuit/reset", http.StatusBadRequest},
+		{"invalid chain", http.MethodPost, "/circuit/reset?chain=abc", http.StatusBadRequest},
+		{"unknown chain", http.MethodPost, "/circuit/reset?chain=1", http.StatusNotFound},
+		{"missing API key", http.MethodGet, "/metrics", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+			var body ErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, tt.status, body.Code)
+			assert.NotEmpty(t, body.Error)
+		})
+	}
+}
+
+// TestStatusResponse verifies the status endpoint returns JSON with a success status
+func TestStatusResponse(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newTestServer().Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, "{}", rec.Body.String())
+}
+
+// TestWriteJSONEncodingFailure verifies a body that can't be encoded results in a clean 500 error
+func TestWriteJSONEncodingFailure(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newTestServer().writeJSON(rec, http.StatusOK, map[string]interface{}{"invalid": make(chan int)})
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	var body ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, http.StatusInternalServerError, body.Code)
+}
+
+// TestStartPortInUse verifies an error is returned when the port is already bound and the server can be shut down
+func TestStartPortInUse(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+
+	s := newTestServer()
+	s.host = "127.0.0.1"
+	s.port = port
+	assert.Error(t, s.Start())
+
+	s.port = "0"
+	require.NoError(t, s.Start())
+	assert.NoError(t, s.Shutdown(context.Background()))
+}
+
+// fakeBlockNumberService serves eth_blockNumber and counts the calls
+type fakeBlockNumberService struct {
+	calls atomic.Int32
+}
+
+func (s *fakeBlockNumberService) BlockNumber() hexutil.Uint64 {
+	s.calls.Add(1)
+	return 100
+}
+
+// TestReadiness verifies the readiness endpoint requires a fee update and a responding RPC, with cached RPC checks
+func TestReadiness(t *testing.T) {
+	svc := &fakeBlockNumberService{}
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", svc))
+	t.Cleanup(server.Stop)
+
+	client := &chainclient.Client{ChainID: 1, Client: ethclient.NewClient(rpc.DialInProc(server))}
+	s := newTestServer()
+	s.chains[1] = client
+	handler := s.Handler()
+
+	ready := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		return rec
+	}
+
+	// not ready until the fees were updated
+	rec := ready()
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	var body ReadinessResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, http.StatusServiceUnavailable, body.Code)
+	assert.Equal(t, "no successful fee update yet", body.Chains["chain_1"])
+	assert.Zero(t, svc.calls.Load())
+
+	client.FeeUpdatedAt = time.Now()
+	rec = ready()
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "Ready", rec.Body.String())
+	assert.EqualValues(t, 1, svc.calls.Load())
+
+	// the RPC check is cached
+	assert.Equal(t, http.StatusOK, ready().Code)
+	assert.EqualValues(t, 1, svc.calls.Load())
+
+	// disconnected clients are not ready
+	client.Client = nil
+	assert.Equal(t, http.StatusServiceUnavailable, ready().Code)
+}
+
+// TestReadinessRPCFailure verifies a chain whose RPC doesn't respond is reported as not ready
+func TestReadinessRPCFailure(t *testing.T) {
+	// no eth service is registered, eth_blockNumber fails
+	server := rpc.NewServer()
+	t.Cleanup(server.Stop)
+
+	s := newTestServer()
+	s.chains[1] = &chainclient.Client{
+		ChainID:      1,
+		Client:       ethclient.NewClient(rpc.DialInProc(server)),
+		FeeUpdatedAt: time.Now(),
+	}
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var body ReadinessResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Contains(t, body.Chains["chain_1"], "RPC not responding")
+}
+
+// TestAdminMinFee verifies the min fee of a chain is updated through the admin endpoint
+func TestAdminMinFee(t *testing.T) {
+	s := newTestServer()
+	s.chains[8453] = &chainclient.Client{ChainID: 8453, MinFee: big.NewInt(100000)}
+	s.chains[56] = &chainclient.Client{ChainID: 56, MinFee: big.NewInt(100000), MinFeeUSD: 0.5}
+	handler := s.Handler()
+
+	post := func(path, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		if apiKey != "" {
+			req.Header.Set("Authorization", "Bearer "+apiKey)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		apiKey string
+		status int
+	}{
+		{"missing API key", "/admin/minfee?chain=8453&value=200000", "", http.StatusUnauthorized},
+		{"invalid API key", "/admin/minfee?chain=8453&value=200000", "wrong", http.StatusUnauthorized},
+		{"invalid chain", "/admin/minfee?chain=abc&value=200000", "secret", http.StatusBadRequest},
+		{"invalid value", "/admin/minfee?chain=8453&value=0.2", "secret", http.StatusBadRequest},
+		{"negative value", "/admin/minfee?chain=8453&value=-1", "secret", http.StatusBadRequest},
+		{"unknown chain", "/admin/minfee?chain=1&value=200000", "secret", http.StatusNotFound},
+		{"min fee in USD", "/admin/minfee?chain=56&value=200000", "secret", http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.status, post(tt.path, tt.apiKey).Code)
+		})
+	}
+	assert.Equal(t, big.NewInt(100000), s.chains[8453].GetMinFee())
+
+	rec := post("/admin/minfee?chain=8453&value=200000", "secret")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, big.NewInt(200000), s.chains[8453].GetMinFee())
+
+	// the admin endpoints are disabled without an API key
+	s.metricsAPIKey = ""
+	assert.Equal(t, http.StatusForbidden, post("/admin/minfee?chain=8453&value=300000", "").Code)
+	assert.Equal(t, big.NewInt(200000), s.chains[8453].GetMinFee())
+}
+
+// TestAdminMaxGas verifies the max gas price of a chain is updated through the admin endpoint
+func TestAdminMaxGas(t *testing.T) {
+	s := newTestServer()
+	s.chains[8453] = &chainclient.Client{ChainID: 8453, MaxGasPrice: big.NewInt(5000000000)}
+	handler := s.Handler()
+
+	post := func(path string) int {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusBadRequest, post("/admin/maxgas?chain=8453&value=0"))
+	assert.Equal(t, http.StatusBadRequest, post("/admin/maxgas?chain=8453&value=abc"))
+	assert.Equal(t, http.StatusNotFound, post("/admin/maxgas?chain=1&value=10000000000"))
+	assert.False(t, s.chains[8453].IsWithinMax(big.NewInt(8000000000)))
+
+	assert.Equal(t, http.StatusOK, post("/admin/maxgas?chain=8453&value=10000000000"))
+	assert.Equal(t, big.NewInt(10000000000), s.chains[8453].GetMaxGasPrice())
+	assert.True(t, s.chains[8453].IsWithinMax(big.NewInt(8000000000)))
+}
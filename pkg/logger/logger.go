@@ -1,9 +1,14 @@
 package logger
 
 import (
-	"github.com/fatih/color"
+	"fmt"
 	"log"
+	"os"
 	"sync"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+	"github.com/speedrun-hq/speedrunner/pkg/registry"
 )
 
 // Level represents the severity level of a log message.
@@ -16,49 +21,17 @@ const (
 	ErrorLevel
 )
 
-type Chain int
+// noChainColor is the color of the logs without chain
+const noChainColor = color.FgWhite
 
-const (
-	None = iota
-	Eth
-	Bsc
-	Pol
-	Arb
-	Ava
-	Base
-	Zeta
-)
-
-var chainIDMap = map[int]Chain{
-	1:     Eth,
-	56:    Bsc,
-	137:   Pol,
-	42161: Arb,
-	43114: Ava,
-	8453:  Base,
-	7000:  Zeta,
-}
-
-var chainPrefixes = map[Chain]string{
-	None: "",
-	Eth:  "[ETH]  ",
-	Bsc:  "[BSC]  ",
-	Pol:  "[POL]  ",
-	Arb:  "[ARB]  ",
-	Ava:  "[AVA]  ",
-	Base: "[BASE] ",
-	Zeta: "[ZETA] ",
-}
-
-var colors = map[Chain]color.Attribute{
-	None: color.FgWhite,
-	Eth:  color.FgHiGreen,
-	Bsc:  color.FgYellow,
-	Pol:  color.FgMagenta,
-	Arb:  color.FgHiBlue,
-	Ava:  color.FgRed,
-	Base: color.FgBlue,
-	Zeta: color.FgGreen,
+// chainPrefix returns the log prefix and color of the chain from the chain registry,
+// logs without chain or of unknown chains have no prefix
+func chainPrefix(chainID int) (string, color.Attribute) {
+	chain, exists := registry.Chains.Get(chainID)
+	if !exists {
+		return "", noChainColor
+	}
+	return fmt.Sprintf("%-6s ", "["+chain.ShortName+"]"), chain.Color
 }
 
 // Logger is a simple interface for logging messages.
@@ -94,6 +67,12 @@ func (l *EmptyLogger) DebugWithChain(_ int, _ string, _ ...interface{})  {}
 func (l *EmptyLogger) Notice(_ string, _ ...interface{})                 {}
 func (l *EmptyLogger) NoticeWithChain(_ int, _ string, _ ...interface{}) {}
 
+// IsTerminal returns true if the output of StdLogger (stderr) is a terminal, to color logs only there
+func IsTerminal() bool {
+	fd := os.Stderr.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
 // StdLogger is a standard implementation of the Logger interface that logs messages to the console.
 type StdLogger struct {
 	enableColoring bool
@@ -111,10 +90,13 @@ func NewStdLogger(enableColoring bool, level Level) *StdLogger {
 }
 
 // formatMessage formats the log message with the appropriate log level, chain prefix, and coloring if enabled.
-func (l *StdLogger) formatMessage(level Level, chain Chain, format string) string {
-	chainPrefix := chainPrefixes[chain]
+func (l *StdLogger) formatMessage(level Level, chainID int, format string) string {
+	prefix, chainColor := chainPrefix(chainID)
 	if l.enableColoring {
-		chainPrefix = color.New(colors[chain]).Sprint(chainPrefix)
+		// force the colors, the color package disables them when stdout isn't a terminal while logs go to stderr
+		c := color.New(chainColor)
+		c.EnableColor()
+		prefix = c.Sprint(prefix)
 	}
 
 	var levelStr string
@@ -129,14 +111,14 @@ func (l *StdLogger) formatMessage(level Level, chain Chain, format string) strin
 		levelStr = "[ERROR]  "
 	}
 
-	return levelStr + chainPrefix + format
+	return levelStr + prefix + format
 }
 
 func (l *StdLogger) Info(format string, args ...interface{}) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	if l.level <= InfoLevel {
-		log.Printf(l.formatMessage(InfoLevel, None, format), args...)
+		log.Printf(l.formatMessage(InfoLevel, 0, format), args...)
 	}
 }
 
@@ -144,10 +126,8 @@ func (l *StdLogger) InfoWithChain(chainID int, format string, args ...interface{
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	chain := chainIDMap[chainID]
-
 	if l.level <= InfoLevel {
-		log.Printf(l.formatMessage(InfoLevel, chain, format), args...)
+		log.Printf(l.formatMessage(InfoLevel, chainID, format), args...)
 	}
 }
 
@@ -155,7 +135,7 @@ func (l *StdLogger) Error(format string, args ...interface{}) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	if l.level <= ErrorLevel {
-		log.Printf(l.formatMessage(ErrorLevel, None, format), args...)
+		log.Printf(l.formatMessage(ErrorLevel, 0, format), args...)
 	}
 }
 
@@ -163,10 +143,8 @@ func (l *StdLogger) ErrorWithChain(chainID int, format string, args ...interface
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	chain := chainIDMap[chainID]
-
 	if l.level <= ErrorLevel {
-		log.Printf(l.formatMessage(ErrorLevel, chain, format), args...)
+		log.Printf(l.formatMessage(ErrorLevel, chainID, format), args...)
 	}
 }
 
@@ -174,7 +152,7 @@ func (l *StdLogger) Debug(format string, args ...interface{}) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	if l.level <= DebugLevel {
-		log.Printf(l.formatMessage(DebugLevel, None, format), args...)
+		log.Printf(l.formatMessage(DebugLevel, 0, format), args...)
 	}
 }
 
@@ -182,10 +160,8 @@ func (l *StdLogger) DebugWithChain(chainID int, format string, args ...interface
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	chain := chainIDMap[chainID]
-
 	if l.level <= DebugLevel {
-		log.Printf(l.formatMessage(DebugLevel, chain, format), args...)
+		log.Printf(l.formatMessage(DebugLevel, chainID, format), args...)
 	}
 }
 
@@ -193,7 +169,7 @@ func (l *StdLogger) Notice(format string, args ...interface{}) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	if l.level <= NoticeLevel {
-		log.Printf(l.formatMessage(NoticeLevel, None, format), args...)
+		log.Printf(l.formatMessage(NoticeLevel, 0, format), args...)
 	}
 }
 
@@ -201,9 +177,7 @@ func (l *StdLogger) NoticeWithChain(chainID int, format string, args ...interfac
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	chain := chainIDMap[chainID]
-
 	if l.level <= NoticeLevel {
-		log.Printf(l.formatMessage(NoticeLevel, chain, format), args...)
+		log.Printf(l.formatMessage(NoticeLevel, chainID, format), args...)
 	}
 }
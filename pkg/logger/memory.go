@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Entry is a message recorded by MemoryLogger, ChainID is 0 for messages logged without chain
+type Entry struct {
+	Level   Level
+	ChainID int
+	Message string
+}
+
+// MemoryLogger is an implementation of the Logger interface that records the formatted messages
+// of all levels in memory, meant for tests asserting on log output.
+type MemoryLogger struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+var _ Logger = (*MemoryLogger)(nil)
+
+// NewMemoryLogger creates an empty memory logger
+func NewMemoryLogger() *MemoryLogger {
+	return &MemoryLogger{}
+}
+
+func (l *MemoryLogger) record(level Level, chainID int, format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, Entry{Level: level, ChainID: chainID, Message: fmt.Sprintf(format, args...)})
+}
+
+// Entries returns a copy of the recorded messages in the order they were logged
+func (l *MemoryLogger) Entries() []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entries := make([]Entry, len(l.entries))
+	copy(entries, l.entries)
+	return entries
+}
+
+// Contains returns true if a message containing substr was logged at the level
+func (l *MemoryLogger) Contains(level Level, substr string) bool {
+	for _, entry := range l.Entries() {
+		if entry.Level == level && strings.Contains(entry.Message, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset drops the recorded messages
+func (l *MemoryLogger) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = nil
+}
+
+func (l *MemoryLogger) Info(format string, args ...interface{}) {
+	l.record(InfoLevel, 0, format, args...)
+}
+
+func (l *MemoryLogger) InfoWithChain(chainID int, format string, args ...interface{}) {
+	l.record(InfoLevel, chainID, format, args...)
+}
+
+func (l *MemoryLogger) Error(format string, args ...interface{}) {
+	l.record(ErrorLevel, 0, format, args...)
+}
+
+func (l *MemoryLogger) ErrorWithChain(chainID int, format string, args ...interface{}) {
+	l.record(ErrorLevel, chainID, format, args...)
+}
+
+func (l *MemoryLogger) Debug(format string, args ...interface{}) {
+	l.record(DebugLevel, 0, format, args...)
+}
+
+func (l *MemoryLogger) DebugWithChain(chainID int, format string, args ...interface{}) {
+	l.record(DebugLevel, chainID, format, args...)
+}
+
+func (l *MemoryLogger) Notice(format string, args ...interface{}) {
+	l.record(NoticeLevel, 0, format, args...)
+}
+
+func (l *MemoryLogger) NoticeWithChain(chainID int, format string, args ...interface{}) {
+	l.record(NoticeLevel, chainID, format, args...)
+}
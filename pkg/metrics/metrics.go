@@ -12,6 +12,16 @@ var (
 		Help: "The total number of fulfilled intents",
 	}, []string{"chain_id", "status"})
 
+	BatchFulfillments = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fulfiller_batch_fulfillments_total",
+		Help: "The total number of intent batches fulfilled in one transaction, by status (success, fallback to individual fulfillments, or failed after sending the transaction)",
+	}, []string{"chain_id", "status"})
+
+	FulfillmentReports = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fulfiller_fulfillment_reports_total",
+		Help: "The total number of fulfillments reported to the API, by status (success, failed)",
+	}, []string{"chain_id", "status"})
+
 	IntentProcessingTime = promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Name:    "fulfiller_intent_processing_seconds",
 		Help:    "Time taken to process intents",
@@ -24,16 +34,58 @@ var (
 		Buckets: prometheus.ExponentialBuckets(21000, 2, 10), // Start at 21000 with 10 buckets doubling in size
 	}, []string{"chain_id"})
 
+	ApprovalGasUsed = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "fulfiller_approval_gas_used",
+		Help:    "Gas used for token approvals sent before fulfilling intents",
+		Buckets: prometheus.ExponentialBuckets(21000, 2, 10),
+	}, []string{"chain_id"})
+
+	GasUsedAnomalies = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fulfiller_gas_used_anomalies_total",
+		Help: "Number of fulfillments using more gas than the withdraw gas estimate times GAS_USED_ALERT_RATIO",
+	}, []string{"chain_id"})
+
+	RPCLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "fulfiller_rpc_latency_seconds",
+		Help:    "Latency of RPC calls to the chains",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 14), // Start at 10ms with 14 buckets doubling in size
+	}, []string{"chain_id", "method"})
+
 	GasPrice = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "fulfiller_gas_price_gwei",
 		Help: "Current gas price in gwei",
 	}, []string{"chain_id"})
 
+	PriorityFee = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "fulfiller_priority_fee_gwei",
+		Help: "Priority fee in gwei of the last dynamic fee transaction, after the tip floor",
+	}, []string{"chain_id"})
+
+	ReorgsDetected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fulfiller_reorgs_detected_total",
+		Help: "The total number of fulfillment transactions reorged out of their block",
+	}, []string{"chain_id", "requeued"})
+
 	PendingIntents = promauto.NewGauge(prometheus.GaugeOpts{
 		Name: "fulfiller_pending_intents",
 		Help: "Number of intents pending fulfillment",
 	})
 
+	LastSuccessfulFetch = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "fulfiller_last_successful_fetch_timestamp",
+		Help: "Unix timestamp of the last successful fetch of the pending intents from the API",
+	})
+
+	ExposureUSD = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "fulfiller_exposure_usd",
+		Help: "USD value committed to intents in flight across all chains",
+	})
+
+	PendingTransactions = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "fulfiller_pending_transactions",
+		Help: "Number of transactions of the fulfiller sent but not mined yet",
+	}, []string{"chain_id"})
+
 	RetryCount = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "fulfiller_retry_count_total",
 		Help: "Total number of retry attempts",
@@ -56,7 +108,7 @@ var (
 
 	MaxRetriesReached = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "fulfiller_max_retries_reached_total",
-		Help: "Number of intents that reached maximum retry attempts",
+		Help: "Number of intents that reached maximum retry attempts or whose retries expired (error_type expired)",
 	}, []string{"chain_id", "error_type"})
 
 	RetryQueueSize = promauto.NewGauge(prometheus.GaugeOpts{
@@ -69,6 +121,21 @@ var (
 		Help: "Seconds until the next scheduled retry",
 	})
 
+	SkippedIntents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fulfiller_intents_skipped_total",
+		Help: "Number of intents skipped by the filter by source chain and reason",
+	}, []string{"source_chain_id", "reason"})
+
+	InvalidIntents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fulfiller_invalid_intents_total",
+		Help: "Number of intents rejected because of invalid or missing fields",
+	}, []string{"reason"})
+
+	UnknownTokens = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fulfiller_unknown_token_total",
+		Help: "Number of intents skipped because their token address isn't a supported token",
+	}, []string{"token"})
+
 	RetriesExecuted = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "fulfiller_retries_executed_total",
 		Help: "Number of retries that were executed",
@@ -83,4 +150,36 @@ var (
 		Name: "fulfiller_retries_dropped_total",
 		Help: "Number of retries that were dropped due to queue capacity",
 	}, []string{"chain_id"})
+
+	RealizedProfitUSD = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "fulfiller_realized_profit_usd",
+		Help:    "Realized profit in USD of each fulfillment: intent fee minus transaction costs",
+		Buckets: []float64{-10, -5, -1, -0.5, -0.1, 0, 0.1, 0.5, 1, 5, 10, 50},
+	}, []string{"chain_id"})
+
+	FeeCostRatio = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "fulfiller_fee_cost_ratio",
+		Help:    "Ratio of the intent fee to the current withdraw fee in USD of each intent evaluated, above 1 the fee covers the cost",
+		Buckets: []float64{0.25, 0.5, 0.75, 1, 1.25, 1.5, 2, 3, 5, 10, 25, 100},
+	}, []string{"chain_id"})
+
+	FeesEarnedUSD = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fulfiller_fees_earned_usd_total",
+		Help: "Running total of intent fees earned in USD, subtract fulfiller_fulfillment_cost_usd_total for the total profit",
+	}, []string{"chain_id"})
+
+	FulfillmentCostUSD = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fulfiller_fulfillment_cost_usd_total",
+		Help: "Running total of transaction costs paid in USD to fulfill intents",
+	}, []string{"chain_id"})
+
+	CircuitBreakerOpen = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "fulfiller_circuit_breaker_open",
+		Help: "Whether the circuit breaker is open (1) or closed (0)",
+	}, []string{"chain_id"})
+
+	CircuitBreakerTrips = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fulfiller_circuit_breaker_trips_total",
+		Help: "Total number of times the circuit breaker tripped",
+	}, []string{"chain_id"})
 )
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"math/big"
+)
+
+// FulfillmentResult represents the outcome of a successful intent fulfillment
+type FulfillmentResult struct {
+	TxHash   string   // Hash of the fulfill transaction
+	GasUsed  uint64   // Gas used by the fulfill transaction
+	GasPrice *big.Int // Effective gas price paid for the fulfill transaction
+
+	BlockNumber uint64 // Number of the block including the fulfill transaction
+	BlockHash   string // Hash of the block including the fulfill transaction
+
+	ApprovalNeeded   bool     // Whether a token approval transaction was sent before fulfilling
+	ApprovalGasUsed  uint64   // Gas used by the approval transaction, if any
+	ApprovalGasPrice *big.Int // Effective gas price paid for the approval transaction, if any
+}
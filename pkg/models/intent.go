@@ -1,7 +1,14 @@
 package models
 
 import (
+	"fmt"
+	"math/big"
+	"strings"
 	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/speedrun-hq/speedrunner/pkg/chains"
 )
 
 // Intent represents an intent from the API
@@ -13,7 +20,85 @@ type Intent struct {
 	Amount           string    `json:"amount"`
 	Recipient        string    `json:"recipient"`
 	IntentFee        string    `json:"intent_fee"`
+	Contract         string    `json:"contract,omitempty"` // Intent contract fulfilling the intent on the destination chain, empty for the default one
 	Status           string    `json:"status"`
 	CreatedAt        time.Time `json:"created_at"`
 	UpdatedAt        time.Time `json:"updated_at"`
 }
+
+// Reasons an intent fails validation, used as metric labels
+const (
+	InvalidReasonID               = "invalid_id"
+	InvalidReasonToken            = "invalid_token"
+	InvalidReasonAmount           = "invalid_amount"
+	InvalidReasonIntentFee        = "invalid_intent_fee"
+	InvalidReasonRecipient        = "invalid_recipient"
+	InvalidReasonZeroRecipient    = "zero_recipient"
+	InvalidReasonContract         = "invalid_contract"
+	InvalidReasonSourceChain      = "unknown_source_chain"
+	InvalidReasonDestinationChain = "unknown_destination_chain"
+	InvalidReasonNativeAsset      = "native_asset_mismatch"
+)
+
+// InvalidIntentError is returned by Validate when a required field of the intent is invalid
+type InvalidIntentError struct {
+	Reason  string
+	Message string
+}
+
+func (e *InvalidIntentError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Reason, e.Message)
+}
+
+// Validate checks that the fields required to fulfill the intent are well-formed
+// the ID must be a hex encoded bytes32, the token a valid address, the recipient a valid non-zero address,
+// the amount and fee positive base-10 integers, the contract a valid address if set and both chains supported,
+// the gas tokens of both chains the same asset for native token intents
+func (i Intent) Validate() error {
+	// retried intents carry a "_retry_" tag after the on-chain ID
+	id, _, _ := strings.Cut(i.ID, "_retry_")
+	if b, err := hexutil.Decode(id); err != nil || len(b) == 0 || len(b) > common.HashLength {
+		return &InvalidIntentError{Reason: InvalidReasonID, Message: fmt.Sprintf("ID %q is not a valid bytes32 hex string", i.ID)}
+	}
+
+	if !common.IsHexAddress(i.Token) {
+		return &InvalidIntentError{Reason: InvalidReasonToken, Message: fmt.Sprintf("token %q is not a valid address", i.Token)}
+	}
+
+	if amount, ok := new(big.Int).SetString(i.Amount, 10); !ok || amount.Sign() <= 0 {
+		return &InvalidIntentError{Reason: InvalidReasonAmount, Message: fmt.Sprintf("amount %q is not a positive integer", i.Amount)}
+	}
+
+	if fee, ok := new(big.Int).SetString(i.IntentFee, 10); !ok || fee.Sign() <= 0 {
+		return &InvalidIntentError{Reason: InvalidReasonIntentFee, Message: fmt.Sprintf("intent fee %q is not a positive integer", i.IntentFee)}
+	}
+
+	if !common.IsHexAddress(i.Recipient) {
+		return &InvalidIntentError{Reason: InvalidReasonRecipient, Message: fmt.Sprintf("recipient %q is not a valid address", i.Recipient)}
+	}
+
+	// the zero address would burn the tokens sent
+	if common.HexToAddress(i.Recipient) == (common.Address{}) {
+		return &InvalidIntentError{Reason: InvalidReasonZeroRecipient, Message: "recipient is the zero address"}
+	}
+
+	if i.Contract != "" && !common.IsHexAddress(i.Contract) {
+		return &InvalidIntentError{Reason: InvalidReasonContract, Message: fmt.Sprintf("contract %q is not a valid address", i.Contract)}
+	}
+
+	if !chains.IsSupportedChain(i.SourceChain) {
+		return &InvalidIntentError{Reason: InvalidReasonSourceChain, Message: fmt.Sprintf("source chain %d is not supported", i.SourceChain)}
+	}
+
+	if !chains.IsSupportedChain(i.DestinationChain) {
+		return &InvalidIntentError{Reason: InvalidReasonDestinationChain, Message: fmt.Sprintf("destination chain %d is not supported", i.DestinationChain)}
+	}
+
+	// native token intents are paid out 1:1 in the gas token of the destination chain, which must be the same asset
+	if chains.IsNativeToken(i.Token) && !chains.SameNativeAsset(i.SourceChain, i.DestinationChain) {
+		return &InvalidIntentError{Reason: InvalidReasonNativeAsset, Message: fmt.Sprintf(
+			"native token of source chain %d is not the native token of destination chain %d", i.SourceChain, i.DestinationChain)}
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validIntent() Intent {
+	return Intent{
+		ID:               "0x4b3f1a1e2c6f4b8a9d0e1f2a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e",
+		SourceChain:      8453,
+		DestinationChain: 42161,
+		Token:            "0xaf88d065e77c8cC2239327C5EDb3A432268e5831",
+		Amount:           "1000000",
+		Recipient:        "0x1234567890abcdef1234567890abcdef12345678",
+		IntentFee:        "10000",
+	}
+}
+
+func TestIntentValidate(t *testing.T) {
+	require.NoError(t, validIntent().Validate())
+
+	retried := validIntent()
+	retried.ID += "_retry_1_error_gas_error"
+	assert.NoError(t, retried.Validate())
+
+	tests := []struct {
+		name   string
+		modify func(*Intent)
+		reason string
+	}{
+		{"id not hex", func(i *Intent) { i.ID = "intent1" }, InvalidReasonID},
+		{"id empty", func(i *Intent) { i.ID = "0x" }, InvalidReasonID},
+		{"id too long", func(i *Intent) { i.ID = validIntent().ID + "00" }, InvalidReasonID},
+		{"token invalid", func(i *Intent) { i.Token = "usdc" }, InvalidReasonToken},
+		{"amount not a number", func(i *Intent) { i.Amount = "1.5" }, InvalidReasonAmount},
+		{"amount zero", func(i *Intent) { i.Amount = "0" }, InvalidReasonAmount},
+		{"fee missing", func(i *Intent) { i.IntentFee = "" }, InvalidReasonIntentFee},
+		{"fee negative", func(i *Intent) { i.IntentFee = "-1" }, InvalidReasonIntentFee},
+		{"recipient invalid", func(i *Intent) { i.Recipient = "0x1234" }, InvalidReasonRecipient},
+		{"recipient missing", func(i *Intent) { i.Recipient = "" }, InvalidReasonRecipient},
+		{"recipient zero", func(i *Intent) { i.Recipient = "0x0000000000000000000000000000000000000000" }, InvalidReasonZeroRecipient},
+		{"contract invalid", func(i *Intent) { i.Contract = "0x1234" }, InvalidReasonContract},
+		{"unknown source chain", func(i *Intent) { i.SourceChain = 999 }, InvalidReasonSourceChain},
+		{"unknown destination chain", func(i *Intent) { i.DestinationChain = 0 }, InvalidReasonDestinationChain},
+		{"native asset mismatch", func(i *Intent) {
+			i.Token, i.SourceChain, i.DestinationChain = "0x0000000000000000000000000000000000000000", 1, 56
+		}, InvalidReasonNativeAsset},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			intent := validIntent()
+			tt.modify(&intent)
+
+			err := intent.Validate()
+			var invalid *InvalidIntentError
+			require.True(t, errors.As(err, &invalid), "expected InvalidIntentError, got %v", err)
+			assert.Equal(t, tt.reason, invalid.Reason)
+		})
+	}
+}
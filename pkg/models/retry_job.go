@@ -9,5 +9,12 @@ type RetryJob struct {
 	Intent      Intent
 	RetryCount  int
 	NextAttempt time.Time
-	ErrorType   string // Type of error that caused the retry
+	ErrorType   string    // Type of error that caused the retry
+	Deadline    time.Time // Time after which the job is dropped, zero for no deadline
+	FeeUSD      float64   // USD value of the intent fee, used to evict the lowest fee job from a full retry queue
+}
+
+// Expired returns true if the deadline of the job is passed
+func (j RetryJob) Expired(now time.Time) bool {
+	return !j.Deadline.IsZero() && !now.Before(j.Deadline)
 }
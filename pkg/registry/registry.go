@@ -0,0 +1,290 @@
+// Package registry is the single source of truth of the chains supported by the fulfiller,
+// the other packages read the chain names, tokens, log prefixes, gas token price ids and chain defaults from it
+package registry
+
+import (
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+// Chain IDs of the built-in chains
+const (
+	EthereumChainID  = 1
+	BSCChainID       = 56
+	PolygonChainID   = 137
+	ZetaChainChainID = 7000
+	BaseChainID      = 8453
+	ArbitrumChainID  = 42161
+	AvalancheChainID = 43114
+)
+
+// DefaultWithdrawGas is the gas units used to estimate the withdraw fee of the chains without a specific one
+const DefaultWithdrawGas uint64 = 100000
+
+// DefaultNativeDecimals is the decimals of the gas token of the chains without specific ones
+const DefaultNativeDecimals = 18
+
+// Rollup types of the chains paying an L1 data fee, they define how the fee is estimated
+const (
+	RollupArbitrum = "arbitrum"
+	RollupOPStack  = "opstack"
+)
+
+// Token is a token contract of a chain
+type Token struct {
+	Address  string
+	Decimals int
+}
+
+// Chain holds the attributes of a chain
+type Chain struct {
+	ID int
+	// Name is the name of the chain, also the prefix of the environment variables of built-in chains
+	Name string
+	// ShortName is shown in the log prefix of the chain
+	ShortName string
+	// Color is the color of the log prefix of the chain
+	Color color.Attribute
+	// PriceID is the CoinGecko API id of the gas token
+	PriceID string
+	// NativeAsset identifies the asset of the gas token (e.g. ETH on Ethereum and its rollups), native token intents
+	// are only fulfilled between chains with the same native asset, empty if unknown
+	NativeAsset string
+	// NativeDecimals is the decimals of the gas token of the chain
+	NativeDecimals int
+	// WithdrawGas is the gas units used to estimate the withdraw fee of the chain
+	WithdrawGas uint64
+	// MaxGasPrice is the gas price cap in wei of the chain, the global cap is used if empty
+	MaxGasPrice string
+	// MinGasPrice is the gas price floor in wei of the chain, used when the gas source returns a lower price,
+	// the global floor is used if empty
+	MinGasPrice string
+	// ReorgCheckDepth is the number of blocks after which fulfillments are re-checked for reorgs, 0 to not check
+	ReorgCheckDepth uint64
+	// Rollup is the rollup type of chains paying an L1 data fee (RollupArbitrum, RollupOPStack), empty otherwise
+	Rollup string
+	// Tokens maps the token types (USDC, USDT) to their contract on the chain, it must not be modified
+	Tokens map[string]Token
+	// Custom is true for the chains defined through the configuration
+	Custom bool
+}
+
+// ChainRegistry holds the supported chains in registration order
+type ChainRegistry struct {
+	mu     sync.RWMutex
+	chains map[int]Chain
+	ids    []int
+}
+
+// NewChainRegistry creates a registry of the chains, it panics if a chain is defined twice
+func NewChainRegistry(chains ...Chain) *ChainRegistry {
+	r := &ChainRegistry{chains: make(map[int]Chain, len(chains))}
+	for _, chain := range chains {
+		if err := r.Register(chain); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// SameNativeAsset returns true if native token intents can be fulfilled from the source to the destination chain,
+// the chains must be the same or have the same known native asset
+func (r *ChainRegistry) SameNativeAsset(sourceChainID, destinationChainID int) bool {
+	if sourceChainID == destinationChainID {
+		return true
+	}
+	source, sourceExists := r.Get(sourceChainID)
+	destination, destinationExists := r.Get(destinationChainID)
+	return sourceExists && destinationExists && source.NativeAsset != "" && source.NativeAsset == destination.NativeAsset
+}
+
+// Register adds a chain to the registry, the withdraw gas, native decimals and short name are defaulted if not set
+func (r *ChainRegistry) Register(chain Chain) error {
+	if chain.ID <= 0 {
+		return fmt.Errorf("invalid chain ID %d", chain.ID)
+	}
+	if chain.ShortName == "" {
+		chain.ShortName = chain.Name
+	}
+	if chain.WithdrawGas == 0 {
+		chain.WithdrawGas = DefaultWithdrawGas
+	}
+	if chain.NativeDecimals == 0 {
+		chain.NativeDecimals = DefaultNativeDecimals
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.chains[chain.ID]; exists {
+		return fmt.Errorf("chain %d is already registered", chain.ID)
+	}
+	r.chains[chain.ID] = chain
+	r.ids = append(r.ids, chain.ID)
+	return nil
+}
+
+// SetTokenDecimals overrides the decimals of the token of the type on the chain, the tokens of the chain are copied
+// so that the chains returned before are not modified
+func (r *ChainRegistry) SetTokenDecimals(chainID int, tokenType string, decimals int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	chain, exists := r.chains[chainID]
+	if !exists {
+		return fmt.Errorf("chain %d is not registered", chainID)
+	}
+	token, exists := chain.Tokens[tokenType]
+	if !exists {
+		return fmt.Errorf("no %s token on chain %d", tokenType, chainID)
+	}
+	token.Decimals = decimals
+	chain.Tokens = maps.Clone(chain.Tokens)
+	chain.Tokens[tokenType] = token
+	r.chains[chainID] = chain
+	return nil
+}
+
+// Get returns the chain with the ID, false if the chain is not registered
+func (r *ChainRegistry) Get(chainID int) (Chain, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	chain, exists := r.chains[chainID]
+	return chain, exists
+}
+
+// IDs returns the IDs of the registered chains in registration order
+func (r *ChainRegistry) IDs() []int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ids := make([]int, len(r.ids))
+	copy(ids, r.ids)
+	return ids
+}
+
+// All returns the registered chains in registration order
+func (r *ChainRegistry) All() []Chain {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	chains := make([]Chain, 0, len(r.ids))
+	for _, id := range r.ids {
+		chains = append(chains, r.chains[id])
+	}
+	return chains
+}
+
+// Chains is the registry of the supported chains, the built-in chains and the custom chains registered at startup
+var Chains = NewChainRegistry(
+	Chain{
+		ID:          EthereumChainID,
+		Name:        "ETHEREUM",
+		ShortName:   "ETH",
+		Color:       color.FgHiGreen,
+		PriceID:     "ethereum",
+		NativeAsset: "ETH",
+		WithdrawGas: 100000,
+		MaxGasPrice: "10000000000", // 10 gwei
+		MinGasPrice: "10000000",    // 0.01 gwei
+		Tokens: map[string]Token{
+			"USDC": {Address: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", Decimals: 6},
+			"USDT": {Address: "0xdAC17F958D2ee523a2206206994597C13D831ec7", Decimals: 6},
+		},
+	},
+	Chain{
+		ID:          PolygonChainID,
+		Name:        "POLYGON",
+		ShortName:   "POL",
+		Color:       color.FgMagenta,
+		PriceID:     "matic-network",
+		NativeAsset: "POL",
+		WithdrawGas: 80000,
+		MaxGasPrice: "50000000000", // 50 gwei
+		MinGasPrice: "25000000000", // 25 gwei minimum priority fee
+		// reorgs are frequent on Polygon
+		ReorgCheckDepth: 32,
+		Tokens: map[string]Token{
+			"USDC": {Address: "0x3c499c542cEF5E3811e1192ce70d8cC03d5c3359", Decimals: 6},
+			"USDT": {Address: "0xc2132D05D31c914a87C6611C10748AEb04B58e8F", Decimals: 6},
+		},
+	},
+	Chain{
+		ID:          ArbitrumChainID,
+		Name:        "ARBITRUM",
+		ShortName:   "ARB",
+		Color:       color.FgHiBlue,
+		PriceID:     "ethereum", // Arbitrum uses ETH
+		NativeAsset: "ETH",
+		WithdrawGas: 300000,       // gas used includes the L1 calldata cost
+		MaxGasPrice: "5000000000", // 5 gwei
+		MinGasPrice: "10000000",   // 0.01 gwei minimum base fee
+		Rollup:      RollupArbitrum,
+		Tokens: map[string]Token{
+			"USDC": {Address: "0xaf88d065e77c8cC2239327C5EDb3A432268e5831", Decimals: 6},
+			"USDT": {Address: "0xFd086bC7CD5C481DCC9C85ebE478A1C0b69FCbb9", Decimals: 6},
+		},
+	},
+	Chain{
+		ID:          AvalancheChainID,
+		Name:        "AVALANCHE",
+		ShortName:   "AVA",
+		Color:       color.FgRed,
+		PriceID:     "avalanche-2",
+		NativeAsset: "AVAX",
+		WithdrawGas: 100000,
+		MaxGasPrice: "10000000000", // 10 gwei
+		Tokens: map[string]Token{
+			"USDC": {Address: "0xb97ef9ef8734c71904d8002f8b6bc66dd9c48a6e", Decimals: 6},
+			"USDT": {Address: "0x9702230A8Ea53601f5cD2dc00fDBc13d4dF4A8c7", Decimals: 6},
+		},
+	},
+	Chain{
+		ID:          BSCChainID,
+		Name:        "BSC",
+		ShortName:   "BSC",
+		Color:       color.FgYellow,
+		PriceID:     "binancecoin",
+		NativeAsset: "BNB",
+		WithdrawGas: 100000,
+		MaxGasPrice: "10000000000", // 10 gwei
+		MinGasPrice: "100000000",   // 0.1 gwei
+		// reorgs are frequent on BSC
+		ReorgCheckDepth: 15,
+		// the stablecoins of BSC have 18 decimals
+		Tokens: map[string]Token{
+			"USDC": {Address: "0x8AC76a51cc950d9822D68b83fE1Ad97B32Cd580d", Decimals: 18},
+			"USDT": {Address: "0x55d398326f99059fF775485246999027B3197955", Decimals: 18},
+		},
+	},
+	Chain{
+		ID:          ZetaChainChainID,
+		Name:        "ZETACHAIN",
+		ShortName:   "ZETA",
+		Color:       color.FgGreen,
+		PriceID:     "zetachain", // the API id is "zetachain", not the "zeta" ticker
+		NativeAsset: "ZETA",
+		// fulfillments transfer ZRC20 tokens, standard ERC20 transfers paid in ZETA
+		WithdrawGas: 100000,
+		MaxGasPrice: "10000000000", // 10 gwei
+		// ZRC20 USDC and USDT
+		Tokens: map[string]Token{
+			"USDC": {Address: "0x0cbe0dF132a6c6B4a2974Fa1b7Fb953CF0Cc798a", Decimals: 6},
+			"USDT": {Address: "0x7c8dDa80bbBE1254a7aACf3219EBe1481c6E01d7", Decimals: 6},
+		},
+	},
+	Chain{
+		ID:          BaseChainID,
+		Name:        "BASE",
+		ShortName:   "BASE",
+		Color:       color.FgBlue,
+		PriceID:     "ethereum", // Base uses ETH
+		NativeAsset: "ETH",
+		WithdrawGas: 80000,
+		MaxGasPrice: "5000000000", // 5 gwei
+		Rollup:      RollupOPStack,
+		Tokens: map[string]Token{
+			"USDC": {Address: "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913", Decimals: 6},
+			"USDT": {Address: "0x50c5725949A6F0c72E6C4a641F24049A917DB0Cb", Decimals: 6},
+		},
+	},
+)
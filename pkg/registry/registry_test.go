@@ -0,0 +1,82 @@
+package registry
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuiltInChains verifies every built-in chain defines the attributes read by the other packages
+func TestBuiltInChains(t *testing.T) {
+	chains := Chains.All()
+	require.Len(t, chains, 7)
+	for _, chain := range chains {
+		assert.NotEmpty(t, chain.Name, "chain %d", chain.ID)
+		assert.NotEmpty(t, chain.ShortName, "chain %d", chain.ID)
+		assert.NotEmpty(t, chain.PriceID, "chain %d", chain.ID)
+		assert.NotEmpty(t, chain.NativeAsset, "chain %d", chain.ID)
+		assert.NotZero(t, chain.WithdrawGas, "chain %d", chain.ID)
+		assert.Equal(t, DefaultNativeDecimals, chain.NativeDecimals, "chain %d", chain.ID)
+		for _, gasPrice := range []string{chain.MaxGasPrice, chain.MinGasPrice} {
+			if gasPrice != "" {
+				_, ok := new(big.Int).SetString(gasPrice, 10)
+				assert.True(t, ok, "gas price %q of chain %d", gasPrice, chain.ID)
+			}
+		}
+		assert.Contains(t, []string{"", RollupArbitrum, RollupOPStack}, chain.Rollup, "chain %d", chain.ID)
+		assert.False(t, chain.Custom, "chain %d", chain.ID)
+		for _, tokenType := range []string{"USDC", "USDT"} {
+			token := chain.Tokens[tokenType]
+			assert.NotEmpty(t, token.Address, "%s on chain %d", tokenType, chain.ID)
+			assert.Positive(t, token.Decimals, "%s on chain %d", tokenType, chain.ID)
+		}
+	}
+}
+
+// TestRegister verifies chains are registered once with defaults and returned in registration order
+func TestRegister(t *testing.T) {
+	r := NewChainRegistry(Chain{ID: 2, Name: "TWO"})
+	require.NoError(t, r.Register(Chain{ID: 1, Name: "ONE", Custom: true}))
+	assert.ErrorContains(t, r.Register(Chain{ID: 2, Name: "OTHER"}), "already registered")
+	assert.Error(t, r.Register(Chain{ID: 0}))
+
+	assert.Equal(t, []int{2, 1}, r.IDs())
+	chain, exists := r.Get(1)
+	require.True(t, exists)
+	assert.Equal(t, "ONE", chain.ShortName)
+	assert.Equal(t, DefaultWithdrawGas, chain.WithdrawGas)
+	assert.Equal(t, DefaultNativeDecimals, chain.NativeDecimals)
+	_, exists = r.Get(3)
+	assert.False(t, exists)
+}
+
+// TestSetTokenDecimals verifies token decimals are overridden without modifying the chains returned before
+func TestSetTokenDecimals(t *testing.T) {
+	r := NewChainRegistry(Chain{ID: 1, Name: "ONE", Tokens: map[string]Token{"USDC": {Address: "0x01", Decimals: 6}}})
+	before, _ := r.Get(1)
+
+	require.NoError(t, r.SetTokenDecimals(1, "USDC", 18))
+	chain, _ := r.Get(1)
+	assert.Equal(t, Token{Address: "0x01", Decimals: 18}, chain.Tokens["USDC"])
+	assert.Equal(t, 6, before.Tokens["USDC"].Decimals)
+
+	assert.ErrorContains(t, r.SetTokenDecimals(1, "USDT", 18), "no USDT token on chain 1")
+	assert.ErrorContains(t, r.SetTokenDecimals(2, "USDC", 18), "chain 2 is not registered")
+}
+
+// TestSameNativeAsset verifies native token intents are allowed only between chains with the same known native asset
+func TestSameNativeAsset(t *testing.T) {
+	assert.True(t, Chains.SameNativeAsset(EthereumChainID, BaseChainID))
+	assert.True(t, Chains.SameNativeAsset(ArbitrumChainID, EthereumChainID))
+	for _, chainID := range []int{BSCChainID, PolygonChainID, AvalancheChainID, ZetaChainChainID} {
+		assert.False(t, Chains.SameNativeAsset(EthereumChainID, chainID), "chain %d", chainID)
+	}
+
+	// chains without a known native asset only match themselves
+	r := NewChainRegistry(Chain{ID: 1, Name: "ONE"}, Chain{ID: 2, Name: "TWO"})
+	assert.True(t, r.SameNativeAsset(1, 1))
+	assert.False(t, r.SameNativeAsset(1, 2))
+	assert.False(t, r.SameNativeAsset(1, 3))
+}
@@ -0,0 +1,63 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/external"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ClefSigner signs transactions through a clef instance
+type ClefSigner struct {
+	external *external.ExternalSigner
+	account  accounts.Account
+}
+
+var _ Signer = (*ClefSigner)(nil)
+
+// NewClefSigner connects to clef at the given endpoint
+// if address is empty, the first account exposed by clef is used
+func NewClefSigner(endpoint, address string) (*ClefSigner, error) {
+	ext, err := external.NewExternalSigner(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to clef: %v", err)
+	}
+
+	var account accounts.Account
+	if address != "" {
+		account = accounts.Account{Address: common.HexToAddress(address)}
+	} else {
+		available := ext.Accounts()
+		if len(available) == 0 {
+			return nil, errors.New("clef exposes no accounts")
+		}
+		account = available[0]
+	}
+
+	return &ClefSigner{
+		external: ext,
+		account:  account,
+	}, nil
+}
+
+// Address returns the clef account used for signing
+func (s *ClefSigner) Address() common.Address {
+	return s.account.Address
+}
+
+// SignTx requests clef to sign the transaction and verifies clef signed it unchanged
+func (s *ClefSigner) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signed, err := s.external.SignTx(s.account, tx, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("clef failed to sign transaction: %v", err)
+	}
+	if err := checkSignedTx(tx, signed); err != nil {
+		return nil, fmt.Errorf("clef %v", err)
+	}
+	return signed, nil
+}
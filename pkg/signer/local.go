@@ -0,0 +1,63 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// LocalSigner signs transactions with a private key held in memory
+type LocalSigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+var _ Signer = (*LocalSigner)(nil)
+
+// NewLocalSigner creates a signer from a hex encoded private key
+func NewLocalSigner(privateKeyHex string) (*LocalSigner, error) {
+	privateKey, err := crypto.HexToECDSA(privateKeyHex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %v", err)
+	}
+
+	return &LocalSigner{
+		key:     privateKey,
+		address: crypto.PubkeyToAddress(privateKey.PublicKey),
+	}, nil
+}
+
+// NewKeystoreSigner creates a signer from a Web3 Secret Storage keystore file decrypted with the password
+func NewKeystoreSigner(path, password string) (*LocalSigner, error) {
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore file: %v", err)
+	}
+
+	key, err := keystore.DecryptKey(keyJSON, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore: %v", err)
+	}
+
+	return &LocalSigner{
+		key:     key.PrivateKey,
+		address: key.Address,
+	}, nil
+}
+
+// Address returns the address derived from the private key
+func (s *LocalSigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx signs the transaction with the private key
+func (s *LocalSigner) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
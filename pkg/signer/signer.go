@@ -0,0 +1,119 @@
+// Package signer provides transaction signing backends used by the fulfiller.
+package signer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/speedrun-hq/speedrunner/pkg/config"
+)
+
+// Signer signs transactions on behalf of a single account
+type Signer interface {
+	// Address returns the account used to sign transactions
+	Address() common.Address
+
+	// SignTx signs the transaction for the given chain ID
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// New creates the signer described by the configuration
+func New(cfg config.SignerConfig, privateKey string) (Signer, error) {
+	switch cfg.Type {
+	case config.SignerTypeLocal:
+		return NewLocalSigner(privateKey)
+	case config.SignerTypeKeystore:
+		return NewKeystoreSigner(cfg.KeystorePath, cfg.KeystorePassword)
+	case config.SignerTypeClef:
+		return NewClefSigner(cfg.URL, cfg.Address)
+	case config.SignerTypeWeb3Signer:
+		return NewWeb3Signer(cfg.URL, cfg.Address)
+	default:
+		return nil, fmt.Errorf("unsupported signer type: %s", cfg.Type)
+	}
+}
+
+// NewTransactOpts creates transaction options signing with the provided signer for the given chain ID
+func NewTransactOpts(ctx context.Context, s Signer, chainID *big.Int) (*bind.TransactOpts, error) {
+	if s == nil {
+		return nil, errors.New("signer is nil")
+	}
+	if chainID == nil {
+		return nil, errors.New("chain ID is nil")
+	}
+
+	from := s.Address()
+	return &bind.TransactOpts{
+		From: from,
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != from {
+				return nil, bind.ErrNotAuthorized
+			}
+			return s.SignTx(ctx, tx, chainID)
+		},
+		Context: context.Background(),
+	}, nil
+}
+
+// checkSignedTx returns an error if the transaction signed by a remote signer differs from the requested transaction,
+// a compromised or misconfigured signer must not be able to change what is sent on chain
+func checkSignedTx(tx, signed *types.Transaction) error {
+	if signed == nil {
+		return errors.New("returned no signed transaction")
+	}
+
+	var field string
+	switch {
+	case signed.Type() != tx.Type():
+		field = "type"
+	case signed.Nonce() != tx.Nonce():
+		field = "nonce"
+	case !sameRecipient(signed.To(), tx.To()):
+		field = "recipient"
+	case signed.Value().Cmp(tx.Value()) != 0:
+		field = "value"
+	case !bytes.Equal(signed.Data(), tx.Data()):
+		field = "data"
+	case signed.Gas() != tx.Gas():
+		field = "gas limit"
+	case signed.GasFeeCap().Cmp(tx.GasFeeCap()) != 0 || signed.GasTipCap().Cmp(tx.GasTipCap()) != 0:
+		field = "fees"
+	case !sameAccessList(signed.AccessList(), tx.AccessList()):
+		field = "access list"
+	default:
+		return nil
+	}
+	return fmt.Errorf("signed a transaction with a different %s than requested", field)
+}
+
+// sameRecipient returns true if both recipients are the same address or both are contract creations
+func sameRecipient(a, b *common.Address) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// sameAccessList returns true if the access lists have the same entries in the same order
+func sameAccessList(a, b types.AccessList) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Address != b[i].Address || len(a[i].StorageKeys) != len(b[i].StorageKeys) {
+			return false
+		}
+		for j := range a[i].StorageKeys {
+			if a[i].StorageKeys[j] != b[i].StorageKeys[j] {
+				return false
+			}
+		}
+	}
+	return true
+}
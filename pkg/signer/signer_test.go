@@ -0,0 +1,263 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLocalSignerTransactOpts verifies transactions signed through the transact options recover to the signer address
+func TestLocalSignerTransactOpts(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	keyHex := common.Bytes2Hex(crypto.FromECDSA(key))
+
+	s, err := NewLocalSigner(keyHex)
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), s.Address())
+
+	chainID := big.NewInt(8453)
+	opts, err := NewTransactOpts(context.Background(), s, chainID)
+	require.NoError(t, err)
+	assert.Equal(t, s.Address(), opts.From)
+
+	tx := types.NewTransaction(1, common.HexToAddress("0x1"), big.NewInt(0), 21000, big.NewInt(1), nil)
+
+	t.Run("sign with signer address", func(t *testing.T) {
+		signed, err := opts.Signer(opts.From, tx)
+		require.NoError(t, err)
+
+		sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+		require.NoError(t, err)
+		assert.Equal(t, s.Address(), sender)
+	})
+
+	t.Run("reject other address", func(t *testing.T) {
+		_, err := opts.Signer(common.HexToAddress("0x2"), tx)
+		assert.Error(t, err)
+	})
+}
+
+// TestNewLocalSignerInvalidKey verifies an invalid private key is rejected
+func TestNewLocalSignerInvalidKey(t *testing.T) {
+	_, err := NewLocalSigner("not-a-key")
+	assert.Error(t, err)
+}
+
+// TestNewKeystoreSigner verifies the key is decrypted from a keystore file
+func TestNewKeystoreSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	keyJSON, err := keystore.EncryptKey(&keystore.Key{
+		Id:         uuid.New(),
+		Address:    address,
+		PrivateKey: key,
+	}, "password", keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	require.NoError(t, os.WriteFile(path, keyJSON, 0o600))
+
+	t.Run("valid password", func(t *testing.T) {
+		s, err := NewKeystoreSigner(path, "password")
+		require.NoError(t, err)
+		assert.Equal(t, address, s.Address())
+	})
+
+	t.Run("invalid password", func(t *testing.T) {
+		_, err := NewKeystoreSigner(path, "wrong")
+		assert.Error(t, err)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := NewKeystoreSigner(filepath.Join(t.TempDir(), "missing.json"), "password")
+		assert.Error(t, err)
+	})
+}
+
+// fakeRemoteSigner signs the transactions requested through the Web3Signer and clef APIs with a local key,
+// tamper changes the request before signing to simulate a compromised signer
+type fakeRemoteSigner struct {
+	key      *ecdsa.PrivateKey
+	tamper   func(args *apitypes.SendTxArgs)
+	requests []apitypes.SendTxArgs
+}
+
+func (f *fakeRemoteSigner) sign(args apitypes.SendTxArgs) (*types.Transaction, error) {
+	f.requests = append(f.requests, args)
+	if f.tamper != nil {
+		f.tamper(&args)
+	}
+	tx, err := args.ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+	return types.SignTx(tx, types.LatestSignerForChainID(args.ChainID.ToInt()), f.key)
+}
+
+// fakeWeb3SignerAPI serves eth_signTransaction
+type fakeWeb3SignerAPI struct {
+	*fakeRemoteSigner
+}
+
+func (f fakeWeb3SignerAPI) SignTransaction(args apitypes.SendTxArgs) (hexutil.Bytes, error) {
+	signed, err := f.sign(args)
+	if err != nil {
+		return nil, err
+	}
+	return signed.MarshalBinary()
+}
+
+// fakeClefAPI serves account_version, account_list and account_signTransaction
+type fakeClefAPI struct {
+	*fakeRemoteSigner
+}
+
+func (f fakeClefAPI) Version() string {
+	return "6.0.0"
+}
+
+func (f fakeClefAPI) List() []common.Address {
+	return []common.Address{crypto.PubkeyToAddress(f.key.PublicKey)}
+}
+
+func (f fakeClefAPI) SignTransaction(args apitypes.SendTxArgs, _ *string) (map[string]interface{}, error) {
+	signed, err := f.sign(args)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"raw": hexutil.Bytes(raw), "tx": signed}, nil
+}
+
+// newFakeSignerServer serves the API under the namespace over HTTP and returns its URL
+func newFakeSignerServer(t *testing.T, namespace string, api interface{}) string {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName(namespace, api))
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+	return httpServer.URL
+}
+
+// testTransactions returns a transaction of each supported type with an access list when the type allows it
+func testTransactions(chainID *big.Int) map[string]*types.Transaction {
+	to := common.HexToAddress("0x999fce149FD078DCFaa2C681e060e00F528552f4")
+	accessList := types.AccessList{{
+		Address:     common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"),
+		StorageKeys: []common.Hash{{1}},
+	}}
+	return map[string]*types.Transaction{
+		"legacy": types.NewTx(&types.LegacyTx{
+			Nonce: 1, To: &to, Value: big.NewInt(5), Gas: 100000, GasPrice: big.NewInt(1000000000), Data: []byte{0x01},
+		}),
+		"access list": types.NewTx(&types.AccessListTx{
+			ChainID: chainID, Nonce: 2, To: &to, Gas: 100000, GasPrice: big.NewInt(1000000000),
+			Data: []byte{0x02}, AccessList: accessList,
+		}),
+		"dynamic fee": types.NewTx(&types.DynamicFeeTx{
+			ChainID: chainID, Nonce: 3, To: &to, Gas: 100000, GasFeeCap: big.NewInt(2000000000),
+			GasTipCap: big.NewInt(100000000), Data: []byte{0x03}, AccessList: accessList,
+		}),
+	}
+}
+
+// TestWeb3Signer verifies transactions of each type are signed through Web3Signer with their access list,
+// and signatures of another account or of a changed transaction are rejected
+func TestWeb3Signer(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	chainID := big.NewInt(8453)
+	remote := &fakeRemoteSigner{key: key}
+	url := newFakeSignerServer(t, "eth", fakeWeb3SignerAPI{remote})
+
+	_, err = NewWeb3Signer(url, "")
+	assert.Error(t, err)
+
+	s, err := NewWeb3Signer(url, address.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, address, s.Address())
+
+	for name, tx := range testTransactions(chainID) {
+		t.Run(name, func(t *testing.T) {
+			signed, err := s.SignTx(context.Background(), tx, chainID)
+			require.NoError(t, err)
+			assert.Equal(t, tx.Type(), signed.Type())
+			assert.Equal(t, tx.AccessList(), signed.AccessList())
+
+			sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+			require.NoError(t, err)
+			assert.Equal(t, address, sender)
+		})
+	}
+
+	tx := testTransactions(chainID)["dynamic fee"]
+	t.Run("changed transaction", func(t *testing.T) {
+		remote.tamper = func(args *apitypes.SendTxArgs) {
+			args.Value = hexutil.Big(*big.NewInt(1000))
+		}
+		defer func() { remote.tamper = nil }()
+
+		_, err := s.SignTx(context.Background(), tx, chainID)
+		assert.ErrorContains(t, err, "different value")
+	})
+
+	t.Run("other account", func(t *testing.T) {
+		other, err := NewWeb3Signer(url, "0x1111111111111111111111111111111111111111")
+		require.NoError(t, err)
+		_, err = other.SignTx(context.Background(), tx, chainID)
+		assert.ErrorContains(t, err, "transaction signed by")
+	})
+}
+
+// TestClefSigner verifies transactions are signed through clef with the first account it exposes by default,
+// and signatures of a changed transaction are rejected
+func TestClefSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	chainID := big.NewInt(8453)
+	remote := &fakeRemoteSigner{key: key}
+	url := newFakeSignerServer(t, "account", fakeClefAPI{remote})
+
+	s, err := NewClefSigner(url, "")
+	require.NoError(t, err)
+	assert.Equal(t, address, s.Address())
+
+	tx := testTransactions(chainID)["dynamic fee"]
+	signed, err := s.SignTx(context.Background(), tx, chainID)
+	require.NoError(t, err)
+	assert.Equal(t, tx.AccessList(), signed.AccessList())
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	require.NoError(t, err)
+	assert.Equal(t, address, sender)
+
+	remote.tamper = func(args *apitypes.SendTxArgs) {
+		args.Nonce++
+	}
+	_, err = s.SignTx(context.Background(), tx, chainID)
+	assert.ErrorContains(t, err, "different nonce")
+}
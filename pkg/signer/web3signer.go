@@ -0,0 +1,100 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Web3Signer signs transactions through the eth_signTransaction method of a Web3Signer HTTP endpoint
+type Web3Signer struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+var _ Signer = (*Web3Signer)(nil)
+
+// NewWeb3Signer connects to the Web3Signer endpoint, the signing address is required
+func NewWeb3Signer(endpoint, address string) (*Web3Signer, error) {
+	if address == "" {
+		return nil, errors.New("signer address is required for web3signer")
+	}
+
+	client, err := rpc.Dial(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to web3signer: %v", err)
+	}
+
+	return &Web3Signer{
+		client:  client,
+		address: common.HexToAddress(address),
+	}, nil
+}
+
+// Address returns the account used for signing
+func (s *Web3Signer) Address() common.Address {
+	return s.address
+}
+
+// SignTx requests Web3Signer to sign the transaction and decodes the returned raw transaction,
+// it is verified to be signed by the signer address without changes
+func (s *Web3Signer) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	args := map[string]interface{}{
+		"from":    s.address,
+		"gas":     hexutil.Uint64(tx.Gas()),
+		"value":   (*hexutil.Big)(tx.Value()),
+		"data":    hexutil.Bytes(tx.Data()),
+		"nonce":   hexutil.Uint64(tx.Nonce()),
+		"chainId": (*hexutil.Big)(chainID),
+	}
+	if tx.To() != nil {
+		args["to"] = tx.To()
+	}
+	switch tx.Type() {
+	case types.LegacyTxType:
+		args["gasPrice"] = (*hexutil.Big)(tx.GasPrice())
+	case types.AccessListTxType:
+		args["gasPrice"] = (*hexutil.Big)(tx.GasPrice())
+		args["accessList"] = tx.AccessList()
+	case types.DynamicFeeTxType:
+		args["maxFeePerGas"] = (*hexutil.Big)(tx.GasFeeCap())
+		args["maxPriorityFeePerGas"] = (*hexutil.Big)(tx.GasTipCap())
+		args["accessList"] = tx.AccessList()
+	default:
+		return nil, fmt.Errorf("unsupported transaction type %d", tx.Type())
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var raw hexutil.Bytes
+	if err := s.client.CallContext(timeoutCtx, &raw, "eth_signTransaction", args); err != nil {
+		return nil, fmt.Errorf("web3signer failed to sign transaction: %v", err)
+	}
+
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("failed to decode signed transaction: %v", err)
+	}
+
+	// ensure the remote signer signed with the expected account
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover signer of signed transaction: %v", err)
+	}
+	if sender != s.address {
+		return nil, fmt.Errorf("transaction signed by %s, expected %s", sender.Hex(), s.address.Hex())
+	}
+	if err := checkSignedTx(tx, signed); err != nil {
+		return nil, fmt.Errorf("web3signer %v", err)
+	}
+
+	return signed, nil
+}
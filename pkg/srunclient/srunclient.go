@@ -2,7 +2,10 @@
 package srunclient
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,35 +16,46 @@ import (
 )
 
 // APIResponse represents the structure of the API response
+// intents are kept raw to be decoded individually so that a malformed intent doesn't discard the response
 type APIResponse struct {
-	Intents    []models.Intent `json:"intents,omitempty"`
-	Data       []models.Intent `json:"data,omitempty"`    // Some APIs use "data" as the key
-	Results    []models.Intent `json:"results,omitempty"` // Some APIs use "results" as the key
-	Page       int             `json:"page"`
-	PageSize   int             `json:"page_size"`
-	TotalCount int             `json:"total_count"`
-	TotalPages int             `json:"total_pages"`
+	Intents    []json.RawMessage `json:"intents,omitempty"`
+	Data       []json.RawMessage `json:"data,omitempty"`    // Some APIs use "data" as the key
+	Results    []json.RawMessage `json:"results,omitempty"` // Some APIs use "results" as the key
+	Page       int               `json:"page"`
+	PageSize   int               `json:"page_size"`
+	TotalCount int               `json:"total_count"`
+	TotalPages int               `json:"total_pages"`
 }
 
+// DefaultIntentsPath is the path and query of pending intents relative to the API endpoint
+const DefaultIntentsPath = "/api/v1/intents?status=pending"
+
 // Client represents a Speedrun API client
 type Client struct {
-	endpoint   string
-	httpClient *http.Client
-	logger     logger.Logger
+	endpoint    string
+	intentsPath string
+	httpClient  *http.Client
+	logger      logger.Logger
 }
 
 // New creates a new Speedrun API client
 func New(endpoint string, logger logger.Logger) *Client {
 	return &Client{
-		endpoint:   endpoint,
-		httpClient: createHTTPClient(),
-		logger:     logger,
+		endpoint:    endpoint,
+		intentsPath: DefaultIntentsPath,
+		httpClient:  createHTTPClient(),
+		logger:      logger,
 	}
 }
 
+// SetIntentsPath sets the path and query of pending intents relative to the API endpoint
+func (c *Client) SetIntentsPath(path string) {
+	c.intentsPath = path
+}
+
 // FetchPendingIntents gets pending intents from the API
 func (c *Client) FetchPendingIntents() ([]models.Intent, error) {
-	resp, err := c.httpClient.Get(c.endpoint + "/api/v1/intents?status=pending")
+	resp, err := c.httpClient.Get(c.endpoint + c.intentsPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch pending intents: %v", err)
 	}
@@ -66,11 +80,11 @@ func (c *Client) FetchPendingIntents() ([]models.Intent, error) {
 	var apiResp APIResponse
 	if err := json.Unmarshal(bodyBytes, &apiResp); err != nil {
 		// If that fails, try directly as an array
-		var intents []models.Intent
-		if err := json.Unmarshal(bodyBytes, &intents); err != nil {
+		var rawIntents []json.RawMessage
+		if err := json.Unmarshal(bodyBytes, &rawIntents); err != nil {
 			return nil, fmt.Errorf("failed to decode intents: %v, body: %s", err, string(bodyBytes))
 		}
-		return intents, nil
+		return c.decodeIntents(rawIntents), nil
 	}
 
 	// Handle paginated response with no data
@@ -81,42 +95,220 @@ func (c *Client) FetchPendingIntents() ([]models.Intent, error) {
 	}
 
 	// Get intents from whatever field is populated
-	var intents []models.Intent
 	if len(apiResp.Intents) > 0 {
-		intents = apiResp.Intents
+		return c.decodeIntents(apiResp.Intents), nil
 	} else if len(apiResp.Data) > 0 {
-		intents = apiResp.Data
+		return c.decodeIntents(apiResp.Data), nil
 	} else if len(apiResp.Results) > 0 {
-		intents = apiResp.Results
-	} else {
-		// Try one more thing - maybe it's in a top level array with a different name
-		// Parse as generic map and look for any array field
-		var genericResp map[string]interface{}
-		if err := json.Unmarshal(bodyBytes, &genericResp); err != nil {
-			return nil, fmt.Errorf("failed to decode response: %v", err)
+		return c.decodeIntents(apiResp.Results), nil
+	}
+
+	// Try one more thing - maybe it's in a top level array with a different name
+	// Parse as generic map and look for any array field
+	var genericResp map[string]json.RawMessage
+	if err := json.Unmarshal(bodyBytes, &genericResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	for key, value := range genericResp {
+		var rawIntents []json.RawMessage
+		if err := json.Unmarshal(value, &rawIntents); err != nil || len(rawIntents) == 0 {
+			continue
+		}
+		// only arrays of objects can hold intents, don't warn about other arrays
+		if trimmed := bytes.TrimSpace(rawIntents[0]); len(trimmed) == 0 || trimmed[0] != '{' {
+			continue
+		}
+		// Found an array, try to convert it to intents
+		if intents := c.decodeIntents(rawIntents); len(intents) > 0 {
+			c.logger.Debug("Found intents in field: %s", key)
+			return intents, nil
 		}
+	}
+
+	// This is a normal case when there are no pending intents
+	c.logger.Debug("No pending intents found in API response")
+	return []models.Intent{}, nil
+}
 
-		for key, value := range genericResp {
-			if arrayValue, ok := value.([]interface{}); ok && len(arrayValue) > 0 {
-				// Found an array, try to convert it to intents
-				arrayJSON, err := json.Marshal(arrayValue)
-				if err != nil {
-					continue
-				}
-				if err := json.Unmarshal(arrayJSON, &intents); err == nil && len(intents) > 0 {
-					c.logger.Debug("Found intents in field: %s", key)
-					break
-				}
-			}
+// decodeIntents decodes each intent individually, malformed intents are skipped with a logged warning
+func (c *Client) decodeIntents(rawIntents []json.RawMessage) []models.Intent {
+	intents := make([]models.Intent, 0, len(rawIntents))
+	for i, raw := range rawIntents {
+		var intent models.Intent
+		if err := json.Unmarshal(raw, &intent); err != nil {
+			c.logger.Error("Warning: skipping malformed intent at index %d: %v, intent: %s", i, err, string(raw))
+			continue
 		}
+		intents = append(intents, intent)
+	}
+	return intents
+}
+
+// ErrIntentNotFound is returned by GetIntent when the API doesn't know the intent
+var ErrIntentNotFound = errors.New("intent not found")
+
+// GetIntent gets an intent by ID from the API, to check its current status
+func (c *Client) GetIntent(ctx context.Context, id string) (*models.Intent, error) {
+	url := fmt.Sprintf("%s/api/v1/intents/%s", c.endpoint, id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get intent %s: %v", id, err)
+	}
 
-		if len(intents) == 0 {
-			// This is a normal case when there are no pending intents
-			c.logger.Debug("No pending intents found in API response")
-			return []models.Intent{}, nil
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get intent %s: %v", id, err)
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			c.logger.Error("Failed to close response body: %v", err)
 		}
+	}(resp.Body)
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, ErrIntentNotFound
+	default:
+		return nil, fmt.Errorf("unexpected status code getting intent %s: %d, body: %s", id, resp.StatusCode, string(bodyBytes))
+	}
+
+	var intent models.Intent
+	if err := json.Unmarshal(bodyBytes, &intent); err != nil {
+		return nil, fmt.Errorf("failed to decode intent %s: %v, body: %s", id, err, string(bodyBytes))
+	}
+	return &intent, nil
+}
+
+// ErrReportRejected is returned by ReportFulfillment when the API rejects the report, it is not worth retrying
+var ErrReportRejected = errors.New("fulfillment report rejected")
+
+// fulfillmentReport is the body of fulfillment reports
+type fulfillmentReport struct {
+	TxHash  string `json:"tx_hash"`
+	ChainID int    `json:"chain_id"`
+}
+
+// ReportFulfillment reports the transaction fulfilling an intent so that the API can track its settlement
+// A conflict is considered a success as the fulfillment was already reported
+func (c *Client) ReportFulfillment(ctx context.Context, intentID, txHash string, chainID int) error {
+	payload, err := json.Marshal(fulfillmentReport{TxHash: txHash, ChainID: chainID})
+	if err != nil {
+		return fmt.Errorf("failed to report fulfillment of intent %s: %v", intentID, err)
+	}
+
+	url := fmt.Sprintf("%s/api/v1/intents/%s/fulfillment", c.endpoint, intentID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to report fulfillment of intent %s: %v", intentID, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to report fulfillment of intent %s: %v", intentID, err)
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			c.logger.Error("Failed to close response body: %v", err)
+		}
+	}(resp.Body)
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusConflict || (resp.StatusCode >= 200 && resp.StatusCode < 300):
+		return nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests:
+		return fmt.Errorf("%w for intent %s: status code %d, body: %s", ErrReportRejected, intentID, resp.StatusCode, string(bodyBytes))
+	default:
+		return fmt.Errorf("unexpected status code reporting fulfillment of intent %s: %d, body: %s",
+			intentID, resp.StatusCode, string(bodyBytes))
+	}
+}
+
+// ErrIntentClaimed is returned by ClaimIntent when the intent is already claimed by another fulfiller
+var ErrIntentClaimed = errors.New("intent already claimed")
+
+// claimRequest is the body of intent claim requests
+type claimRequest struct {
+	Claimer string `json:"claimer"`
+}
+
+// ClaimIntent claims an intent for the claimer before fulfilling it, so other fulfiller instances skip it
+// The claim is idempotent for the same claimer, ErrIntentClaimed is returned if another claimer holds it
+func (c *Client) ClaimIntent(ctx context.Context, id, claimer string) error {
+	status, body, err := c.doClaimRequest(ctx, http.MethodPost, id, claimer)
+	if err != nil {
+		return fmt.Errorf("failed to claim intent %s: %v", id, err)
+	}
+
+	switch status {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return nil
+	case http.StatusConflict:
+		return ErrIntentClaimed
+	default:
+		return fmt.Errorf("unexpected status code claiming intent %s: %d, body: %s", id, status, body)
+	}
+}
+
+// ReleaseIntent releases the claim on an intent so that other fulfiller instances can pick it up
+func (c *Client) ReleaseIntent(ctx context.Context, id, claimer string) error {
+	status, body, err := c.doClaimRequest(ctx, http.MethodDelete, id, claimer)
+	if err != nil {
+		return fmt.Errorf("failed to release intent %s: %v", id, err)
+	}
+
+	switch status {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return fmt.Errorf("unexpected status code releasing intent %s: %d, body: %s", id, status, body)
+	}
+}
+
+// doClaimRequest sends a request to the claim endpoint of an intent and returns the status code and body
+func (c *Client) doClaimRequest(ctx context.Context, method, id, claimer string) (int, string, error) {
+	payload, err := json.Marshal(claimRequest{Claimer: claimer})
+	if err != nil {
+		return 0, "", err
+	}
+
+	url := fmt.Sprintf("%s/api/v1/intents/%s/claim", c.endpoint, id)
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			c.logger.Error("Failed to close response body: %v", err)
+		}
+	}(resp.Body)
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to read response body: %v", err)
 	}
-	return intents, nil
+	return resp.StatusCode, string(bodyBytes), nil
 }
 
 // Helper function to create an HTTP client with timeouts
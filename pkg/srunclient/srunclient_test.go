@@ -0,0 +1,173 @@
+package srunclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/speedrun-hq/speedrunner/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClaimIntent verifies claim responses are mapped to the expected errors
+func TestClaimIntent(t *testing.T) {
+	claims := map[string]string{"claimed": "0xother"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req claimRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		id := r.URL.Path[len("/api/v1/intents/") : len(r.URL.Path)-len("/claim")]
+		switch r.Method {
+		case http.MethodPost:
+			if id == "broken" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			if claimer, ok := claims[id]; ok && claimer != req.Claimer {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			claims[id] = req.Claimer
+			w.WriteHeader(http.StatusOK)
+		case http.MethodDelete:
+			if _, ok := claims[id]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			delete(claims, id)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	c := New(server.URL, &logger.EmptyLogger{})
+	ctx := context.Background()
+
+	// claiming is idempotent for the same claimer
+	assert.NoError(t, c.ClaimIntent(ctx, "free", "0xme"))
+	assert.NoError(t, c.ClaimIntent(ctx, "free", "0xme"))
+
+	assert.ErrorIs(t, c.ClaimIntent(ctx, "claimed", "0xme"), ErrIntentClaimed)
+
+	err := c.ClaimIntent(ctx, "broken", "0xme")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrIntentClaimed)
+
+	// released intents can be claimed by others, releasing twice is not an error
+	assert.NoError(t, c.ReleaseIntent(ctx, "free", "0xme"))
+	assert.NoError(t, c.ReleaseIntent(ctx, "free", "0xme"))
+	assert.NoError(t, c.ClaimIntent(ctx, "free", "0xother"))
+}
+
+// TestFetchPendingIntentsSkipsMalformed verifies a malformed intent is skipped without discarding the others
+func TestFetchPendingIntentsSkipsMalformed(t *testing.T) {
+	responses := map[string]string{
+		"wrapper": `{"intents": [{"id": "0x01", "source_chain": 8453}, {"id": "0x02", "source_chain": "invalid"},
+			{"id": "0x03", "source_chain": 42161}], "total_count": 3}`,
+		"array":   `[{"id": "0x01", "source_chain": 8453}, {"id": 2}, {"id": "0x03", "source_chain": 42161}]`,
+		"generic": `{"errors": ["none"], "items": [{"id": "0x01"}, "invalid", {"id": "0x03"}], "total_count": 3}`,
+	}
+
+	for name, body := range responses {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			intents, err := New(server.URL, &logger.EmptyLogger{}).FetchPendingIntents()
+			require.NoError(t, err)
+			require.Len(t, intents, 2)
+			assert.Equal(t, "0x01", intents[0].ID)
+			assert.Equal(t, "0x03", intents[1].ID)
+		})
+	}
+}
+
+// TestFetchPendingIntentsPath verifies pending intents are fetched from the configured path and query
+func TestFetchPendingIntentsPath(t *testing.T) {
+	var requested string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.RequestURI()
+		_, _ = w.Write([]byte(`[{"id": "0x01"}]`))
+	}))
+	defer server.Close()
+
+	c := New(server.URL, &logger.EmptyLogger{})
+	_, err := c.FetchPendingIntents()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultIntentsPath, requested)
+
+	c.SetIntentsPath("/proxy/v2/intents?state=open")
+	intents, err := c.FetchPendingIntents()
+	require.NoError(t, err)
+	require.Len(t, intents, 1)
+	assert.Equal(t, "/proxy/v2/intents?state=open", requested)
+}
+
+// TestGetIntent verifies an intent is fetched by ID and unknown intents are mapped to ErrIntentNotFound
+func TestGetIntent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/intents/0x01":
+			_, _ = w.Write([]byte(`{"id": "0x01", "destination_chain": 8453, "status": "fulfilled"}`))
+		case "/api/v1/intents/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := New(server.URL, &logger.EmptyLogger{})
+	ctx := context.Background()
+
+	intent, err := c.GetIntent(ctx, "0x01")
+	require.NoError(t, err)
+	assert.Equal(t, "0x01", intent.ID)
+	assert.Equal(t, 8453, intent.DestinationChain)
+	assert.Equal(t, "fulfilled", intent.Status)
+
+	_, err = c.GetIntent(ctx, "0x02")
+	assert.ErrorIs(t, err, ErrIntentNotFound)
+
+	_, err = c.GetIntent(ctx, "broken")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrIntentNotFound)
+}
+
+// TestReportFulfillment verifies the report body and the mapping of the response status codes
+func TestReportFulfillment(t *testing.T) {
+	var report fulfillmentReport
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&report))
+		switch r.URL.Path {
+		case "/api/v1/intents/0x01/fulfillment":
+			w.WriteHeader(http.StatusCreated)
+		case "/api/v1/intents/reported/fulfillment":
+			w.WriteHeader(http.StatusConflict)
+		case "/api/v1/intents/invalid/fulfillment":
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	c := New(server.URL, &logger.EmptyLogger{})
+	ctx := context.Background()
+
+	require.NoError(t, c.ReportFulfillment(ctx, "0x01", "0xabc", 8453))
+	assert.Equal(t, fulfillmentReport{TxHash: "0xabc", ChainID: 8453}, report)
+
+	assert.NoError(t, c.ReportFulfillment(ctx, "reported", "0xabc", 8453))
+	assert.ErrorIs(t, c.ReportFulfillment(ctx, "invalid", "0xabc", 8453), ErrReportRejected)
+
+	err := c.ReportFulfillment(ctx, "unavailable", "0xabc", 8453)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrReportRejected)
+}